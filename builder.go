@@ -0,0 +1,193 @@
+package fexpr
+
+import "strings"
+
+// FieldRef represents a field identifier used as comparison value
+// (eg. the "b" in `a = b`).
+type FieldRef string
+
+// ConditionBuilder constructs the native conditions of a query builder
+// or ORM (eg. squirrel, goqu, gorm or ent) and it is used by
+// BuildCondition to convert a parsed filter bottom-up.
+//
+// The field arguments are the left operand identifiers in their plain
+// `.` separated form (use MapFields to rename or restrict them in advance).
+//
+// The comparison values are int64, float64, string, bool, nil (for `null`)
+// or FieldRef (for identifier right operands).
+type ConditionBuilder interface {
+	Eq(field string, value interface{}) interface{}
+	Neq(field string, value interface{}) interface{}
+	Lt(field string, value interface{}) interface{}
+	Lte(field string, value interface{}) interface{}
+	Gt(field string, value interface{}) interface{}
+	Gte(field string, value interface{}) interface{}
+
+	In(field string, values []interface{}) interface{}
+	NotIn(field string, values []interface{}) interface{}
+
+	// Like and NotLike receive SQL like patterns with
+	// `%` and `_` wildcards and `\` as escape character.
+	Like(field string, pattern string, caseInsensitive bool) interface{}
+	NotLike(field string, pattern string, caseInsensitive bool) interface{}
+
+	// And and Or join two or more conditions.
+	And(conditions []interface{}) interface{}
+	Or(conditions []interface{}) interface{}
+
+	// Group wraps the condition of a nested parenthesized group
+	// (could return the condition as it is if the builder
+	// doesn't need explicit grouping).
+	Group(condition interface{}) interface{}
+}
+
+// UnaryConditionBuilder is an optional ConditionBuilder extension
+// for the custom unary operators (see RegisterUnaryOp).
+type UnaryConditionBuilder interface {
+	Unary(op UnaryOp, condition interface{}) interface{}
+}
+
+// BuildCondition converts the provided parsed filter into the native
+// condition of a ConditionBuilder implementation.
+//
+// The `~` and `!~` operators are converted to case-insensitive
+// contains patterns (eg. `a ~ "test"` => Like("a", "%test%", true)).
+// Expressions with literal left and identifier right operand are mirrored
+// (eg. `1 < a` => Gt("a", 1)).
+//
+// An error is returned for the array/any and array/all operators,
+// for non-identifier left operands, for the field identifiers that are
+// not plain `.` separated names (eg. `a:lower`, `a->"b"` or `data["b"]`)
+// and for the custom unary operators
+// if b doesn't implement UnaryConditionBuilder.
+// An empty filter is returned as nil condition.
+func BuildCondition(exprs []ExprGroup, b ConditionBuilder) (interface{}, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+
+	disjuncts := []interface{}{}
+
+	for _, conjunction := range splitOr(exprs) {
+		conditions := make([]interface{}, 0, len(conjunction))
+
+		for _, g := range conjunction {
+			condition, err := buildItemCondition(g.Item, b)
+			if err != nil {
+				return nil, err
+			}
+
+			conditions = append(conditions, condition)
+		}
+
+		if len(conditions) == 1 {
+			disjuncts = append(disjuncts, conditions[0])
+		} else {
+			disjuncts = append(disjuncts, b.And(conditions))
+		}
+	}
+
+	if len(disjuncts) == 1 {
+		return disjuncts[0], nil
+	}
+
+	return b.Or(disjuncts), nil
+}
+
+// buildItemCondition converts a single ExprGroup.Item into a builder condition.
+func buildItemCondition(item interface{}, b ConditionBuilder) (interface{}, error) {
+	switch v := item.(type) {
+	case Expr:
+		return buildExprCondition(v, b)
+	case []ExprGroup:
+		condition, err := BuildCondition(v, b)
+		if err != nil {
+			return nil, err
+		}
+		return b.Group(condition), nil
+	case UnaryExpr:
+		ub, ok := b.(UnaryConditionBuilder)
+		if !ok {
+			return nil, errorf(ErrUnsupportedItem, "unsupported unary operator %q", v.Op)
+		}
+		condition, err := buildItemCondition(v.Item, b)
+		if err != nil {
+			return nil, err
+		}
+		return ub.Unary(v.Op, condition), nil
+	default:
+		return nil, errorf(ErrUnsupportedItem, "unsupported expression group item %T", item)
+	}
+}
+
+// buildExprCondition converts a single expression into a builder condition.
+func buildExprCondition(expr Expr, b ConditionBuilder) (interface{}, error) {
+	if _, isLiteral := literalValue(expr.Left); isLiteral {
+		if _, rightIsLiteral := literalValue(expr.Right); !rightIsLiteral {
+			if op, ok := mirroredSignOps[expr.Op]; ok {
+				expr = Expr{Left: expr.Right, Op: op, Right: expr.Left}
+			}
+		}
+	}
+
+	if _, isLiteral := literalValue(expr.Left); isLiteral {
+		return nil, errorf(ErrInvalidOperand, "expected field identifier, got %q (%s)", expr.Left.Literal, expr.Left.Type)
+	}
+
+	path, err := fieldPath(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	field := strings.Join(path, ".")
+
+	value, ok := literalValue(expr.Right)
+	if !ok {
+		refPath, err := fieldPath(expr.Right)
+		if err != nil {
+			return nil, err
+		}
+		value = FieldRef(strings.Join(refPath, "."))
+	}
+
+	switch expr.Op {
+	case SignEq:
+		return b.Eq(field, value), nil
+	case SignNeq:
+		return b.Neq(field, value), nil
+	case SignLt:
+		return b.Lt(field, value), nil
+	case SignLte:
+		return b.Lte(field, value), nil
+	case SignGt:
+		return b.Gt(field, value), nil
+	case SignGte:
+		return b.Gte(field, value), nil
+	case SignIn, SignNotIn:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, errorf(ErrInvalidOperand, "expected literal values list, got %q (%s)", expr.Right.Literal, expr.Right.Type)
+		}
+		if expr.Op == SignNotIn {
+			return b.NotIn(field, values), nil
+		}
+		return b.In(field, values), nil
+	}
+
+	if expr.Right.Type != TokenText {
+		return nil, errorf(ErrInvalidOperand, "expected text right operand for %q, got %q (%s)", expr.Op, expr.Right.Literal, expr.Right.Type)
+	}
+
+	switch expr.Op {
+	case SignLike:
+		return b.Like(field, containsPattern(expr.Right.Literal), true), nil
+	case SignNlike:
+		return b.NotLike(field, containsPattern(expr.Right.Literal), true), nil
+	case SignSQLLike, SignSQLIlike:
+		return b.Like(field, expr.Right.Literal, expr.Op == SignSQLIlike), nil
+	case SignSQLNlike, SignSQLNilike:
+		return b.NotLike(field, expr.Right.Literal, expr.Op == SignSQLNilike), nil
+	default:
+		return nil, unsupportedSignOpError(expr.Op, "ConditionBuilder")
+	}
+}
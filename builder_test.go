@@ -0,0 +1,161 @@
+package fexpr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testConditionBuilder is a ConditionBuilder that constructs
+// the conditions as function call strings.
+type testConditionBuilder struct{}
+
+func (testConditionBuilder) Eq(field string, value interface{}) interface{} {
+	return fmt.Sprintf("eq(%s, %#v)", field, value)
+}
+
+func (testConditionBuilder) Neq(field string, value interface{}) interface{} {
+	return fmt.Sprintf("neq(%s, %#v)", field, value)
+}
+
+func (testConditionBuilder) Lt(field string, value interface{}) interface{} {
+	return fmt.Sprintf("lt(%s, %#v)", field, value)
+}
+
+func (testConditionBuilder) Lte(field string, value interface{}) interface{} {
+	return fmt.Sprintf("lte(%s, %#v)", field, value)
+}
+
+func (testConditionBuilder) Gt(field string, value interface{}) interface{} {
+	return fmt.Sprintf("gt(%s, %#v)", field, value)
+}
+
+func (testConditionBuilder) Gte(field string, value interface{}) interface{} {
+	return fmt.Sprintf("gte(%s, %#v)", field, value)
+}
+
+func (testConditionBuilder) In(field string, values []interface{}) interface{} {
+	return fmt.Sprintf("in(%s, %#v)", field, values)
+}
+
+func (testConditionBuilder) NotIn(field string, values []interface{}) interface{} {
+	return fmt.Sprintf("notIn(%s, %#v)", field, values)
+}
+
+func (testConditionBuilder) Like(field string, pattern string, caseInsensitive bool) interface{} {
+	return fmt.Sprintf("like(%s, %q, %v)", field, pattern, caseInsensitive)
+}
+
+func (testConditionBuilder) NotLike(field string, pattern string, caseInsensitive bool) interface{} {
+	return fmt.Sprintf("notLike(%s, %q, %v)", field, pattern, caseInsensitive)
+}
+
+func (testConditionBuilder) And(conditions []interface{}) interface{} {
+	return "and(" + joinConditions(conditions) + ")"
+}
+
+func (testConditionBuilder) Or(conditions []interface{}) interface{} {
+	return "or(" + joinConditions(conditions) + ")"
+}
+
+func (testConditionBuilder) Group(condition interface{}) interface{} {
+	return "group(" + condition.(string) + ")"
+}
+
+func joinConditions(conditions []interface{}) string {
+	parts := make([]string, len(conditions))
+	for i, c := range conditions {
+		parts[i] = c.(string)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func TestBuildCondition(t *testing.T) {
+	scenarios := []struct {
+		input          string
+		expectedError  bool
+		expectedResult interface{}
+	}{
+		{`1 = 2`, true, nil},
+		{`"a" = b`, false, `eq(b, "a")`},
+		{`a ?= 1`, true, nil},
+		{`a ~ 1`, true, nil},
+		{`a like b`, true, nil},
+		{`a = 1`, false, `eq(a, 1)`},
+		{`a != null && b < 1.5 && c <= true`, false, `and(neq(a, <nil>), lt(b, 1.5), lte(c, true))`},
+		{`1 < a || 2 >= b`, false, `or(gt(a, 1), lte(b, 2))`},
+		{`a > b.c`, false, `gt(a, "b.c")`},
+		{`a:lower = 1`, true, nil},
+		{`a->"b" = 1`, true, nil},
+		{`data["b"] = 1`, true, nil},
+		{`a.* = 1`, true, nil},
+		{`a = b:lower`, true, nil},
+		{`a = 1 && (b = 2 || c >= 3) || d = 4`, false, `or(and(eq(a, 1), group(or(eq(b, 2), gte(c, 3)))), eq(d, 4))`},
+		{`a in (1, "x") && b not in (2)`, false, `and(in(a, []interface {}{1, "x"}), notIn(b, []interface {}{2}))`},
+		{
+			`a ~ "x" && b !~ "y%" && c like "z" && d not ilike "w"`,
+			false,
+			`and(like(a, "%x%", true), notLike(b, "y%", true), like(c, "z", false), notLike(d, "w", true))`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := BuildCondition(exprs, testConditionBuilder{})
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if fmt.Sprint(result) != fmt.Sprint(s.expectedResult) {
+				t.Fatalf("Expected %v, got %v", s.expectedResult, result)
+			}
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := BuildCondition(nil, testConditionBuilder{})
+		if result != nil || err != nil {
+			t.Fatalf("Expected nil result and error, got %v, %v", result, err)
+		}
+	})
+}
+
+// testUnaryConditionBuilder is a testConditionBuilder with unary operators support.
+type testUnaryConditionBuilder struct {
+	testConditionBuilder
+}
+
+func (testUnaryConditionBuilder) Unary(op UnaryOp, condition interface{}) interface{} {
+	return fmt.Sprintf("unary(%s, %s)", op, condition)
+}
+
+func TestBuildConditionUnary(t *testing.T) {
+	exprs, err := Parse(`~~ a = 1 || b = 2`, ScannerOptions(RegisterUnaryOp("~~", UnaryOpOptions{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := BuildCondition(exprs, testConditionBuilder{}); err == nil {
+		t.Fatal("Expected unsupported unary operator error, got nil")
+	}
+
+	result, err := BuildCondition(exprs, testUnaryConditionBuilder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `or(unary(~~, eq(a, 1)), eq(b, 2))`; result != expected {
+		t.Fatalf("Expected %s, got %v", expected, result)
+	}
+}
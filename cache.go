@@ -0,0 +1,155 @@
+package fexpr
+
+import (
+	"container/list"
+	"math/big"
+	"sync"
+)
+
+// Cache is a concurrent safe LRU cache of parsed filter expressions
+// keyed by their raw text.
+//
+// Each Cache.Parse call returns a deep copy of the cached result so
+// the caller is free to modify it without affecting the cache.
+type Cache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+	opts  []ParseOption
+}
+
+// cacheEntry represents a single Cache item.
+type cacheEntry struct {
+	text   string
+	result []ExprGroup
+}
+
+// NewCache creates and returns a new Cache instance that can hold up
+// to size parsed expressions.
+//
+// The optional opts are applied to every Parse call of the cache.
+//
+// A non-positive size disables the caching and every Cache.Parse call
+// is forwarded directly to Parse.
+//
+// NewCache panics if opts contain a per-call option (Comments, Warnings,
+// OnExpr, OnToken or Placeholders) since the side effects could not be
+// replayed for the cached results (and their destinations would be shared
+// between the concurrent Cache.Parse calls) and the cached resolved
+// placeholders could become stale (eg. `@now`).
+func NewCache(size int, opts ...ParseOption) *Cache {
+	if newParser(opts).hasPerCallOptions() {
+		panic("fexpr: the Comments, Warnings, OnExpr, OnToken and Placeholders options are not supported by Cache")
+	}
+
+	return &Cache{
+		size:  size,
+		order: list.New(),
+		items: map[string]*list.Element{},
+		opts:  opts,
+	}
+}
+
+// Parse returns the cached result for text or, if missing, parses
+// the text with Parse and stores the result in the cache.
+//
+// Errored parse results are not cached.
+func (c *Cache) Parse(text string) ([]ExprGroup, error) {
+	if c.size <= 0 {
+		return Parse(text, c.opts...)
+	}
+
+	c.mu.Lock()
+	if el, ok := c.items[text]; ok {
+		c.order.MoveToFront(el)
+		result := copyExprGroups(el.Value.(*cacheEntry).result)
+		c.mu.Unlock()
+		return result, nil
+	}
+	c.mu.Unlock()
+
+	result, err := Parse(text, c.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// could have been stored by another goroutine in the meantime
+	if el, ok := c.items[text]; ok {
+		c.order.MoveToFront(el)
+	} else {
+		c.items[text] = c.order.PushFront(&cacheEntry{text: text, result: result})
+
+		for c.order.Len() > c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*cacheEntry).text)
+		}
+	}
+
+	return copyExprGroups(result), nil
+}
+
+// Len returns the number of currently cached expressions.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// hasPerCallOptions reports whether the parser options have per-call
+// side effects or results (aka. options destinations or callbacks).
+func (p *parser) hasPerCallOptions() bool {
+	if p.comments != nil || p.warnings != nil || len(p.exprHooks) > 0 || p.placeholders != nil {
+		return true
+	}
+
+	return len(NewScanner(nil, p.scannerOpts...).tokenHooks) > 0
+}
+
+// copyItem returns a deep copy of a single ExprGroup.Item.
+func copyItem(item interface{}) interface{} {
+	switch v := item.(type) {
+	case Expr:
+		v.Left = copyToken(v.Left)
+		v.Right = copyToken(v.Right)
+		return v
+	case []ExprGroup:
+		return copyExprGroups(v)
+	case UnaryExpr:
+		v.Item = copyItem(v.Item)
+		return v
+	default:
+		return item
+	}
+}
+
+// copyToken returns a copy of t with its own precise Number (if any).
+func copyToken(t Token) Token {
+	if t.Number != nil {
+		t.Number = new(big.Rat).Set(t.Number)
+	}
+
+	return t
+}
+
+// copyExprGroups returns a deep copy of the provided ExprGroup slice.
+func copyExprGroups(groups []ExprGroup) []ExprGroup {
+	if groups == nil {
+		return nil
+	}
+
+	result := make([]ExprGroup, len(groups))
+
+	for i, g := range groups {
+		result[i] = g
+
+		result[i].Item = copyItem(g.Item)
+	}
+
+	return result
+}
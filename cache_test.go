@@ -0,0 +1,148 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCacheParse(t *testing.T) {
+	c := NewCache(2)
+
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+		expectedLen   int
+	}{
+		{`a = 1`, false, `[{&& {{identifier a} = {number 1}}}]`, 1},
+		{`a >`, true, `[]`, 1},
+		{`(b = 2)`, false, `[{&& [{&& {{identifier b} = {number 2}}}]}]`, 2},
+		{`a = 1`, false, `[{&& {{identifier a} = {number 1}}}]`, 2},
+		{`c = 3`, false, `[{&& {{identifier c} = {number 3}}}]`, 2},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := c.Parse(s.input)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+
+			if l := c.Len(); l != s.expectedLen {
+				t.Fatalf("Expected %d cached items, got %d", s.expectedLen, l)
+			}
+		})
+	}
+
+	// "(b = 2)" should have been evicted as the least recently used
+	if _, ok := c.items["(b = 2)"]; ok {
+		t.Fatal("Expected (b = 2) to be evicted")
+	}
+}
+
+func TestCacheParseCopy(t *testing.T) {
+	c := NewCache(1)
+
+	v1, err := c.Parse(`(a = 1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// modify the returned result
+	v1[0].Item.([]ExprGroup)[0].Item = Expr{}
+
+	v2, err := c.Parse(`(a = 1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& [{&& {{identifier a} = {number 1}}}]}]`
+	if vPrint := fmt.Sprintf("%v", v2); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+}
+
+func TestCacheParseCopyPreciseNumbers(t *testing.T) {
+	c := NewCache(1, ScannerOptions(PreciseNumbers()))
+
+	v1, err := c.Parse(`a = 0.1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// modify the returned precise number
+	v1[0].Item.(Expr).Right.Number.SetInt64(5)
+
+	v2, err := c.Parse(`a = 0.1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& {{identifier a} = {number 0.1 1/10}}}]`
+	if vPrint := fmt.Sprintf("%v", v2); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+}
+
+func TestNewCachePerCallOptionsPanic(t *testing.T) {
+	var comments []ExprComment
+	var warnings []Warning
+
+	scenarios := []struct {
+		name string
+		opt  ParseOption
+	}{
+		{"Comments", Comments(&comments)},
+		{"Warnings", Warnings(&warnings)},
+		{"OnExpr", OnExpr(func(g ExprGroup, span Span) error { return nil })},
+		{"OnToken", ScannerOptions(OnToken(func(t Token, span Span, err error) error { return err }))},
+		{"Placeholders", Placeholders(PlaceholderMap{"@now": {Type: TokenText, Literal: "2022-01-01"}})},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Expected panic for %s", s.name)
+				}
+			}()
+
+			NewCache(1, s.opt)
+		})
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	c := NewCache(0)
+
+	if _, err := c.Parse(`a = 1`); err != nil {
+		t.Fatal(err)
+	}
+
+	if l := c.Len(); l != 0 {
+		t.Fatalf("Expected no cached items, got %d", l)
+	}
+}
+
+func TestCacheParseOptions(t *testing.T) {
+	c := NewCache(1, Macro("test", "a = 1"))
+
+	v, err := c.Parse(`#test`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& [{&& {{identifier a} = {number 1}}}]}]`
+	if vPrint := fmt.Sprintf("%v", v); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+}
@@ -0,0 +1,89 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Command fexpr-wasm exposes the fexpr parser to JavaScript so that
+// the filters could be validated client-side with the same parser
+// used by the Go backend.
+//
+// Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o fexpr.wasm ./cmd/fexpr-wasm
+//
+// Once loaded (eg. with the Go distribution wasm_exec.js), it registers
+// a global `fexpr` object with the following functions:
+//
+//	fexpr.validate(filter) // {valid: boolean, error: string}
+//	fexpr.parse(filter)    // {result: PlainExprGroup[], error: string}
+//	fexpr.format(filter)   // {result: string, error: string}
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/ganigeorgiev/fexpr"
+)
+
+func main() {
+	js.Global().Set("fexpr", js.ValueOf(map[string]interface{}{
+		"validate": js.FuncOf(validate),
+		"parse":    js.FuncOf(parse),
+		"format":   js.FuncOf(format),
+	}))
+
+	// keep the module alive for the registered callbacks
+	select {}
+}
+
+// filterArg returns the first call argument as filter string.
+func filterArg(args []js.Value) string {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return ""
+	}
+
+	return args[0].String()
+}
+
+func validate(this js.Value, args []js.Value) interface{} {
+	_, err := fexpr.Parse(filterArg(args))
+
+	return map[string]interface{}{
+		"valid": err == nil,
+		"error": errorString(err),
+	}
+}
+
+func parse(this js.Value, args []js.Value) interface{} {
+	exprs, err := fexpr.Parse(filterArg(args))
+	if err != nil {
+		return map[string]interface{}{"result": nil, "error": err.Error()}
+	}
+
+	raw, err := json.Marshal(fexpr.ToPlain(exprs))
+	if err != nil {
+		return map[string]interface{}{"result": nil, "error": err.Error()}
+	}
+
+	return map[string]interface{}{
+		"result": js.Global().Get("JSON").Call("parse", string(raw)),
+		"error":  "",
+	}
+}
+
+func format(this js.Value, args []js.Value) interface{} {
+	result, err := fexpr.Format(filterArg(args), fexpr.FormatOptions{})
+
+	return map[string]interface{}{
+		"result": result,
+		"error":  errorString(err),
+	}
+}
+
+// errorString returns the err message or empty string if err is nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
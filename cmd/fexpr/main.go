@@ -0,0 +1,162 @@
+// Command fexpr is a small helper for debugging fexpr filter expressions.
+//
+// Usage:
+//
+//	fexpr <command> [flags] [filter]
+//
+// The filter is read from stdin if not provided as argument.
+//
+// Commands:
+//
+//	tokenize  prints the filter tokens (one per line)
+//	parse     prints the parsed filter AST (-output json|tree)
+//	format    prints the formatted filter
+//	validate  checks whether the filter is valid
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ganigeorgiev/fexpr"
+)
+
+const usage = `Usage: fexpr <command> [flags] [filter]
+
+The filter is read from stdin if not provided as argument.
+
+Commands:
+  tokenize  prints the filter tokens (one per line)
+  parse     prints the parsed filter AST (-output json|tree)
+  format    prints the formatted filter
+  validate  checks whether the filter is valid
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the CLI command specified in args and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	command := args[0]
+
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var output string
+	var indent string
+	switch command {
+	case "parse":
+		fs.StringVar(&output, "output", "json", "the AST output format (json or tree)")
+	case "format":
+		fs.StringVar(&indent, "indent", "", "the nested groups indentation (default to 4 spaces)")
+	case "tokenize", "validate":
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", command, usage)
+		return 2
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	filter, err := readFilter(fs.Args(), stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	switch command {
+	case "tokenize":
+		err = tokenize(filter, stdout)
+	case "parse":
+		err = parse(filter, output, stdout)
+	case "format":
+		err = format(filter, indent, stdout)
+	case "validate":
+		_, err = fexpr.Parse(filter)
+		if err == nil {
+			fmt.Fprintln(stdout, "OK")
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// readFilter returns the filter from the command arguments or
+// from r if there are no arguments.
+func readFilter(args []string, r io.Reader) (string, error) {
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// tokenize writes the filter tokens into w (one per line).
+func tokenize(filter string, w io.Writer) error {
+	tokens, err := fexpr.Tokenize(filter)
+
+	for _, t := range tokens {
+		fmt.Fprintf(w, "%s %q\n", t.Type, t.Literal)
+	}
+
+	return err
+}
+
+// parse writes the parsed filter AST into w in the specified output format.
+func parse(filter string, output string, w io.Writer) error {
+	exprs, err := fexpr.Parse(filter)
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(exprs)
+	case "tree":
+		_, err := fmt.Fprintln(w, fexpr.Dump(exprs))
+		return err
+	default:
+		return errors.New("invalid output format " + output + " (expected json or tree)")
+	}
+}
+
+// format writes the formatted filter into w.
+func format(filter string, indent string, w io.Writer) error {
+	result, err := fexpr.Format(filter, fexpr.FormatOptions{Indent: indent})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, result)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	scenarios := []struct {
+		args           []string
+		stdin          string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{nil, "", 2, "", "Usage: fexpr"},
+		{[]string{"unknown"}, "", 2, "", `unknown command "unknown"`},
+		{[]string{"help"}, "", 0, "Usage: fexpr", ""},
+		{[]string{"validate", "a", "=", "1"}, "", 0, "OK\n", ""},
+		{[]string{"validate"}, "a > ", 1, "", "invalid or incomplete filter expression"},
+		{[]string{"tokenize", "a>1"}, "", 0, "identifier \"a\"\nsign \">\"\nnumber \"1\"\n", ""},
+		{[]string{"tokenize", "a$"}, "", 1, "identifier \"a\"\nunexpected \"$\"\n", "unexpected character"},
+		{[]string{"format", "-indent", "\t", "a=1 && (b=2 || c=3)"}, "", 0, "a = 1 &&\n(\n\tb = 2 ||\n\tc = 3\n)\n", ""},
+		{[]string{"parse", "-output", "invalid", "a=1"}, "", 1, "", "invalid output format"},
+		{[]string{"parse", "-output", "tree"}, "a=1 || (b>'c')", 0, "|-- && identifier \"a\" = number \"1\"\n`-- || group\n    `-- && identifier \"b\" > text \"c\"\n", ""},
+		{[]string{"parse"}, "a=1", 0, `[
+  {
+    "Join": "&&",
+    "Item": {
+      "Left": {
+        "Type": "identifier",
+        "Literal": "a"
+      },
+      "Op": "=",
+      "Right": {
+        "Type": "number",
+        "Literal": "1"
+      }
+    }
+  }
+]
+`, ""},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%v", i, s.args), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(s.args, strings.NewReader(s.stdin), &stdout, &stderr)
+
+			if code != s.expectedCode {
+				t.Fatalf("Expected exit code %d, got %d (stderr: %s)", s.expectedCode, code, stderr.String())
+			}
+
+			if s.expectedCode == 0 || s.expectedStdout != "" {
+				if !strings.HasPrefix(stdout.String(), s.expectedStdout) {
+					t.Fatalf("Expected stdout\n%q\ngot\n%q", s.expectedStdout, stdout.String())
+				}
+			}
+
+			if !strings.Contains(stderr.String(), s.expectedStderr) {
+				t.Fatalf("Expected stderr to contain %q, got %q", s.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package fexpr
+
+// And combines the provided parsed filters into a single filter
+// where each of them is wrapped in its own group and joined with `&&`.
+//
+// Empty filters are skipped.
+func And(filters ...[]ExprGroup) []ExprGroup {
+	return combine(JoinAnd, filters)
+}
+
+// Or combines the provided parsed filters into a single filter
+// where each of them is wrapped in its own group and joined with `||`.
+//
+// Empty filters are skipped.
+func Or(filters ...[]ExprGroup) []ExprGroup {
+	return combine(JoinOr, filters)
+}
+
+// combine wraps each non-empty filter in a group joined with join.
+func combine(join JoinOp, filters [][]ExprGroup) []ExprGroup {
+	result := make([]ExprGroup, 0, len(filters))
+
+	for _, f := range filters {
+		if len(f) == 0 {
+			continue
+		}
+
+		group := ExprGroup{Join: join, Item: f}
+
+		// similar to Parse, the first group is always with && join
+		if len(result) == 0 {
+			group.Join = JoinAnd
+		}
+
+		result = append(result, group)
+	}
+
+	return result
+}
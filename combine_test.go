@@ -0,0 +1,38 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAndOr(t *testing.T) {
+	a, err := Parse(`a = 1 || b = 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Parse(`tenant = "test"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		result   []ExprGroup
+		expected string
+	}{
+		{And(), `[]`},
+		{And(nil, []ExprGroup{}), `[]`},
+		{And(a), `[{&& [{&& {{identifier a} = {number 1}}} {|| {{identifier b} = {number 2}}}]}]`},
+		{And(a, nil, b), `[{&& [{&& {{identifier a} = {number 1}}} {|| {{identifier b} = {number 2}}}]} {&& [{&& {{identifier tenant} = {text test}}}]}]`},
+		{Or(nil, a, b), `[{&& [{&& {{identifier a} = {number 1}}} {|| {{identifier b} = {number 2}}}]} {|| [{&& {{identifier tenant} = {text test}}}]}]`},
+		{And(Or(a, b), b), `[{&& [{&& [{&& {{identifier a} = {number 1}}} {|| {{identifier b} = {number 2}}}]} {|| [{&& {{identifier tenant} = {text test}}}]}]} {&& [{&& {{identifier tenant} = {text test}}}]}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d", i), func(t *testing.T) {
+			if v := fmt.Sprintf("%v", s.result); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
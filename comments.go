@@ -0,0 +1,192 @@
+package fexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExprComment represents a comment attached to the nearest parsed ExprGroup.
+type ExprComment struct {
+	// Text is the comment text without the `//` marker.
+	Text string
+
+	// Path is the index path of the commented ExprGroup in the parse
+	// result (eg. []int{1, 0} is the first item of the second group).
+	Path []int
+
+	// Trailing indicates whether the comment is placed after the
+	// commented group (aka. on the same line or at the end of the
+	// filter/group), otherwise it is placed before it.
+	Trailing bool
+}
+
+// Comments enables collecting the filter comments into dst, each one
+// attached to its nearest ExprGroup (see ExprComment).
+//
+// dst is replaced only on successful parse.
+func Comments(dst *[]ExprComment) ParseOption {
+	return func(p *parser) {
+		p.comments = dst
+	}
+}
+
+// addComment collects a single parsed comment attached to the
+// group at the specified index of the current parse path.
+func (p *parser) addComment(text string, index int, trailing bool) {
+	path := make([]int, len(p.path), len(p.path)+1)
+	copy(path, p.path)
+
+	p.collectedComments = append(p.collectedComments, ExprComment{
+		Text:     text,
+		Path:     append(path, index),
+		Trailing: trailing,
+	})
+}
+
+// commentsTracker attaches the comments of a single
+// parseFunc call to their nearest ExprGroup.
+type commentsTracker struct {
+	p *parser
+
+	// leading comments waiting for the next group
+	pending []string
+
+	// index of the last emitted group (-1 if none)
+	last int
+
+	// whether there is a new line after the last emitted group
+	lastHasNewline bool
+}
+
+// token processes a single whitespace or comment token.
+func (c *commentsTracker) token(t Token) {
+	if c.p.comments == nil {
+		return
+	}
+
+	switch t.Type {
+	case TokenWS:
+		if strings.ContainsAny(t.Literal, "\n\r") {
+			c.lastHasNewline = true
+		}
+	case TokenComment:
+		if c.last >= 0 && !c.lastHasNewline {
+			c.p.addComment(t.Literal, c.last, true)
+		} else {
+			c.pending = append(c.pending, t.Literal)
+		}
+
+		// the comment token consumes the new line
+		c.lastHasNewline = true
+	}
+}
+
+// leading attaches the pending leading comments to the group at index.
+func (c *commentsTracker) leading(index int) {
+	if c.p.comments == nil {
+		return
+	}
+
+	for _, text := range c.pending {
+		c.p.addComment(text, index, false)
+	}
+
+	c.pending = nil
+}
+
+// emitted marks the group at index as emitted.
+func (c *commentsTracker) emitted(index int) {
+	c.leading(index)
+	c.last = index
+	c.lastHasNewline = false
+}
+
+// done attaches the remaining comments to the last emitted group.
+func (c *commentsTracker) done() {
+	if c.p.comments == nil || c.last < 0 {
+		return
+	}
+
+	for _, text := range c.pending {
+		c.p.addComment(text, c.last, true)
+	}
+
+	c.pending = nil
+}
+
+// StringifyComments is similar to Stringify but also writes the
+// provided comments (usually collected with the Comments parse option)
+// around their groups, allowing a lossless parse/stringify round-trip.
+func StringifyComments(exprs []ExprGroup, comments []ExprComment) string {
+	byPath := make(map[string][]ExprComment, len(comments))
+	for _, c := range comments {
+		key := fmt.Sprint(c.Path)
+		byPath[key] = append(byPath[key], c)
+	}
+
+	var sb strings.Builder
+
+	writeCommentedGroups(&sb, exprs, nil, byPath)
+
+	return sb.String()
+}
+
+// writeCommentedGroups writes the text representation of groups and
+// their comments into sb.
+func writeCommentedGroups(sb *strings.Builder, groups []ExprGroup, path []int, byPath map[string][]ExprComment) {
+	// whether the last written line is a comment (aka. ends with a new line)
+	var afterComment bool
+
+	for i, g := range groups {
+		itemPath := append(path[:len(path):len(path)], i)
+		comments := byPath[fmt.Sprint(itemPath)]
+
+		var hasLeading bool
+		for _, c := range comments {
+			hasLeading = hasLeading || !c.Trailing
+		}
+
+		if i > 0 {
+			if !afterComment {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(string(g.Join))
+
+			// the leading comments must start on a new line
+			if hasLeading {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString(" ")
+			}
+		}
+
+		for _, c := range comments {
+			if !c.Trailing {
+				sb.WriteString("// ")
+				sb.WriteString(c.Text)
+				sb.WriteString("\n")
+			}
+		}
+
+		if nested, ok := g.Item.([]ExprGroup); ok {
+			sb.WriteString("(")
+			writeCommentedGroups(sb, nested, itemPath, byPath)
+			sb.WriteString(")")
+		} else {
+			writeItem(sb, g.Item, " ")
+		}
+
+		afterComment = false
+		for _, c := range comments {
+			if c.Trailing {
+				if !afterComment {
+					sb.WriteString(" ")
+				}
+				sb.WriteString("// ")
+				sb.WriteString(c.Text)
+				sb.WriteString("\n")
+				afterComment = true
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expected      string
+	}{
+		{`a = 1`, false, `[]`},
+		{"// test\na >", true, `[]`},
+		{"// a\na = 1 // b\n// c\n&& b = 2 // d", false, `[{a [0] false} {b [0] true} {c [1] false} {d [1] true}]`},
+		{"a = 1 &&\n// a\n(// b\nb = 2 // c\n) // d\n// e", false, `[{a [1] false} {b [1 0] false} {c [1 0] true} {d [1] true} {e [1] true}]`},
+		{"a = 1 // a\n// b", false, `[{a [0] true} {b [0] true}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			comments := []ExprComment{}
+
+			_, err := Parse(s.input, Comments(&comments))
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if v := fmt.Sprintf("%v", comments); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestCommentsMacro(t *testing.T) {
+	comments := []ExprComment{}
+
+	_, err := Parse("a = 1 || #test // b", Comments(&comments), Macro("test", "// a\nc = 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{a [1 0] false} {b [1] true}]`
+	if v := fmt.Sprintf("%v", comments); v != expected {
+		t.Fatalf("Expected %s, got %s", expected, v)
+	}
+}
+
+func TestStringifyComments(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a=1`, `a = 1`},
+		{"// a\na = 1 // b\n// c\n&& b = 2 // d", "// a\na = 1 // b\n&&\n// c\nb = 2 // d\n"},
+		{"a = 1 &&\n// a\n(// b\nb = 2 // c\n) // d\n// e", "a = 1 &&\n// a\n(// b\nb = 2 // c\n) // d\n// e\n"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			comments := []ExprComment{}
+
+			exprs, err := Parse(s.input, Comments(&comments))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := StringifyComments(exprs, comments)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+
+			// round-trip
+			roundTripComments := []ExprComment{}
+
+			roundTripExprs, err := Parse(result, Comments(&roundTripComments))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v1, v2 := fmt.Sprintf("%v %v", exprs, comments), fmt.Sprintf("%v %v", roundTripExprs, roundTripComments); v1 != v2 {
+				t.Fatalf("Expected the round-trip result to be\n%s\ngot\n%s", v1, v2)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package fexpr
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// CompletionKind represents the kind of a completion suggestion.
+type CompletionKind string
+
+// Completion suggestion kinds.
+const (
+	CompletionField    CompletionKind = "field"
+	CompletionOperator CompletionKind = "operator"
+	CompletionValue    CompletionKind = "value"
+	CompletionJoin     CompletionKind = "join"
+)
+
+// CompletionSchema describes the filter fields and values
+// available for completion.
+type CompletionSchema struct {
+	// Fields lists the known field identifiers (eg. "name", "author.email").
+	Fields []string
+
+	// Values holds the optional suggested value literals per field
+	// in their filter form (eg. `"draft"`, `10`, `true`).
+	Values map[string][]string
+}
+
+// Completion represents a single completion suggestion.
+type Completion struct {
+	Kind CompletionKind
+
+	// Text is the suggested replacement text.
+	Text string
+
+	// Span is the input byte offsets range that should be replaced with Text
+	// (aka. the partially typed token before the cursor).
+	Span Span
+}
+
+// completionState holds the parser state needed for the completion.
+type completionState struct {
+	step  int
+	field string
+	op    SignOp
+	not   bool // whether the sign operator is a partial "not" keyword operator
+}
+
+// Complete returns the suggested fields, operators, values or join
+// operators that are valid at the cursor byte offset of the input.
+//
+// A cursor outside of the input is clamped to its bounds and a cursor
+// inside a multi-byte character is moved to the character start.
+//
+// Only the input before the cursor is considered and it doesn't need to be
+// a valid filter expression. The suggestions are filtered by the partially
+// typed token before the cursor (if any) using case-insensitive prefix match.
+func Complete(input string, cursor int, schema CompletionSchema) []Completion {
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor > len(input) {
+		cursor = len(input)
+	}
+	// move a cursor inside a multi-byte character to its start
+	for cursor > 0 && cursor < len(input) && !utf8.RuneStart(input[cursor]) {
+		cursor--
+	}
+
+	return completeTokens(input[:cursor], 0, 0, false, "", schema)
+}
+
+// completeTokens returns the completion suggestions for the end of prefix,
+// shifting their spans with offset.
+//
+// depth is the prefix group nesting depth and listField is the left
+// operand of the `in` operator when prefix is its values list.
+func completeTokens(prefix string, offset int, depth int, isList bool, listField string, schema CompletionSchema) []Completion {
+	// too deeply nested to be parsed
+	if depth > defaultMaxDepth {
+		return []Completion{}
+	}
+
+	scanner := NewScanner(strings.NewReader(prefix))
+
+	state := completionState{step: stepBeforeSign}
+	prevState := state
+	var partial Span // the partial token at the end of the prefix
+
+	for {
+		t, err := scanner.Scan()
+		span := scanner.LastSpan()
+
+		if t.Type == TokenEOF || (err != nil && span.End <= span.Start) {
+			break
+		}
+
+		prevState = state
+		partial = Span{Start: span.End, End: span.End}
+
+		switch t.Type {
+		case TokenWS, TokenComment:
+			continue
+		case TokenGroup:
+			// the cursor is inside the group (aka. the group is not closed yet)
+			if err != nil && span.End == len(prefix) {
+				inList := state.step == stepAfterSign && isListSignOp(state.op)
+				return completeTokens(t.Literal, offset+span.Start+1, depth+1, inList, state.field, schema)
+			}
+
+			state.step = StepJoin
+			continue
+		case TokenSign:
+			state.op = normalizeSignOp(t.Literal)
+			state.step = stepAfterSign
+		case TokenJoin:
+			state.step = stepBeforeSign
+		case TokenIdentifier:
+			switch {
+			case isList:
+			case state.step == stepSign && isWordToken(t, "not"):
+				state.not = true
+			case state.step == stepSign && keywordSignOp(t) != "":
+				state.op = keywordSignOp(t)
+				state.not = false
+				state.step = stepAfterSign
+			case state.step == StepJoin && (isWordToken(t, "and") || isWordToken(t, "or")):
+				state.step = stepBeforeSign
+			default:
+				state.completeOperand(t.Literal)
+			}
+		default:
+			state.completeOperand(t.Literal)
+		}
+
+		// the token ends at the cursor and therefore it is considered partially typed
+		if span.End == len(prefix) {
+			partial = span
+		}
+	}
+
+	if partial.End != len(prefix) {
+		partial = Span{Start: len(prefix), End: len(prefix)}
+	}
+
+	// the partial token is replaced by the suggestions
+	// so the state before it is used
+	if partial.Start < partial.End {
+		state = prevState
+	}
+
+	typed := prefix[partial.Start:partial.End]
+	replace := Span{Start: offset + partial.Start, End: offset + partial.End}
+
+	result := []Completion{}
+	add := func(kind CompletionKind, candidates ...string) {
+		for _, c := range candidates {
+			if len(c) >= len(typed) && strings.EqualFold(c[:len(typed)], typed) {
+				result = append(result, Completion{Kind: kind, Text: c, Span: replace})
+			}
+		}
+	}
+
+	if isList {
+		add(CompletionValue, schema.Values[listField]...)
+		return result
+	}
+
+	switch state.step {
+	case stepBeforeSign:
+		add(CompletionField, schema.Fields...)
+	case stepSign:
+		if state.not {
+			for _, op := range keywordSignOps {
+				add(CompletionOperator, string(op))
+			}
+			break
+		}
+
+		add(CompletionOperator, signOperatorLiterals()...)
+		for _, op := range keywordSignOps {
+			add(CompletionOperator, string(op), "not "+string(op))
+		}
+	case stepAfterSign:
+		if isListSignOp(state.op) {
+			add(CompletionValue, "(")
+			break
+		}
+		add(CompletionValue, schema.Values[state.field]...)
+		add(CompletionField, schema.Fields...)
+	case StepJoin:
+		add(CompletionJoin, string(JoinAnd), string(JoinOr))
+	}
+
+	return result
+}
+
+// completeOperand moves the completion state after a left or right operand.
+func (s *completionState) completeOperand(literal string) {
+	if s.step == stepAfterSign {
+		s.step = StepJoin
+		return
+	}
+
+	s.field = literal
+	s.op = ""
+	s.not = false
+	s.step = stepSign
+}
@@ -0,0 +1,101 @@
+package fexpr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestComplete(t *testing.T) {
+	schema := CompletionSchema{
+		Fields: []string{"name", "status", "created"},
+		Values: map[string][]string{
+			"status": {`"draft"`, `"published"`},
+		},
+	}
+
+	scenarios := []struct {
+		input    string
+		cursor   int
+		expected string
+	}{
+		{``, 0, `[{field name {0 0}} {field status {0 0}} {field created {0 0}}]`},
+		{`  `, -1, `[{field name {0 0}} {field status {0 0}} {field created {0 0}}]`},
+		{`na`, 2, `[{field name {0 2}}]`},
+		{`NA`, 2, `[{field name {0 2}}]`},
+		{`na`, 1, `[{field name {0 1}}]`},
+		{`x`, 1, `[]`},
+		{`name >`, 6, `[{operator > {5 6}} {operator >= {5 6}}]`},
+		{`name i`, 6, `[{operator in {5 6}} {operator ilike {5 6}}]`},
+		{`name no`, 7, `[{operator not in {5 7}} {operator not like {5 7}} {operator not ilike {5 7}}]`},
+		{`name not `, 9, `[{operator in {9 9}} {operator like {9 9}} {operator ilike {9 9}}]`},
+		{`status = `, 9, `[{value "draft" {9 9}} {value "published" {9 9}} {field name {9 9}} {field status {9 9}} {field created {9 9}}]`},
+		{`status = "p`, 11, `[{value "published" {9 11}}]`},
+		{`status in `, 10, `[{value ( {10 10}}]`},
+		{`status in ("draft", `, 20, `[{value "draft" {20 20}} {value "published" {20 20}}]`},
+		{`status in ("draft", "p`, 22, `[{value "published" {20 22}}]`},
+		{`status = 1`, 100, `[]`},
+		{`status = 1 `, 11, `[{join && {11 11}} {join || {11 11}}]`},
+		{`status = 1 |`, 12, `[{join || {11 12}}]`},
+		{`status = 1 && (created > 1 || s`, 31, `[{field status {30 31}}]`},
+		{`(a = 1) && (created > 1) `, 25, `[{join && {25 25}} {join || {25 25}}]`},
+		{`(a = 1) && (created > 1) `, 24, `[{join && {24 24}} {join || {24 24}}]`},
+		// the cursor in the middle of the input
+		{`name = 1 && status = 2`, 14, `[{field status {12 14}}]`},
+		// the cursor inside a multi-byte character
+		{`status = "é`, 11, `[{value "draft" {9 10}} {value "published" {9 10}}]`},
+		{`status = é`, 10, `[{value "draft" {9 9}} {value "published" {9 9}} {field name {9 9}} {field status {9 9}} {field created {9 9}}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			result := Complete(s.input, s.cursor, schema)
+
+			if v := fmt.Sprintf("%v", result); v != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestCompleteOperators(t *testing.T) {
+	result := Complete(`a `, 2, CompletionSchema{})
+
+	ops := []string{}
+	for _, c := range result {
+		if c.Kind != CompletionOperator {
+			t.Fatalf("Expected only operator completions, got %v", c)
+		}
+		ops = append(ops, c.Text)
+	}
+
+	expected := strings.Join(signOperatorLiterals(), " ") + " in not in like not like ilike not ilike"
+	if v := strings.Join(ops, " "); v != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, v)
+	}
+}
+
+func TestCompleteMultiByteCursor(t *testing.T) {
+	input := `\\1(a@x\ //@xé`
+
+	for cursor := 0; cursor <= len(input)+1; cursor++ {
+		for _, c := range Complete(input, cursor, CompletionSchema{}) {
+			if c.Span.Start > c.Span.End || c.Span.End > len(input) || (c.Span.End < len(input) && !utf8.RuneStart(input[c.Span.End])) {
+				t.Fatalf("[%d] Invalid completion span %v", cursor, c)
+			}
+		}
+	}
+}
+
+func TestCompleteDeeplyNested(t *testing.T) {
+	schema := CompletionSchema{Fields: []string{"a"}}
+
+	if v := Complete(strings.Repeat("(", defaultMaxDepth), 2000, schema); len(v) != 1 {
+		t.Fatalf("Expected 1 completion, got %v", v)
+	}
+
+	if v := Complete(strings.Repeat("(", defaultMaxDepth+1), 2000, schema); len(v) != 0 {
+		t.Fatalf("Expected no completions, got %v", v)
+	}
+}
@@ -0,0 +1,90 @@
+package fexpr
+
+// Complexity returns the estimated evaluation cost score of the
+// provided parsed filter, usually used to reject pathological
+// user filters (see also the MaxComplexity parse option).
+//
+// The score is the sum of:
+//   - 1 for each expression
+//   - 1 for each like/contains operator (pattern matching)
+//   - 1 for each array/any or array/all operator (multi-value matching)
+//   - 1 for each `in` list item after the first one
+//   - 1 for each custom unary operator (see UnaryExpr)
+//   - the nesting depth of each nested group (eg. 1 for `(a = 1)`)
+func Complexity(exprs []ExprGroup) int {
+	var total int
+
+	for _, g := range exprs {
+		total += itemComplexity(g.Item, 0)
+	}
+
+	return total
+}
+
+// itemComplexity returns the cost score of a single ExprGroup.Item
+// placed at the specified nesting depth.
+func itemComplexity(item interface{}, depth int) int {
+	switch v := item.(type) {
+	case Expr:
+		return exprComplexity(v)
+	case []ExprGroup:
+		total := depth + 1
+
+		for _, g := range v {
+			total += itemComplexity(g.Item, depth+1)
+		}
+
+		return total
+	case UnaryExpr:
+		return 1 + itemComplexity(v.Item, depth)
+	default:
+		return 0
+	}
+}
+
+// exprComplexity returns the cost score of a single expression.
+func exprComplexity(expr Expr) int {
+	total := 1
+
+	switch expr.Op {
+	case SignLike, SignNlike, SignSQLLike, SignSQLNlike, SignSQLIlike, SignSQLNilike:
+		total++
+	case SignAnyEq, SignAnyNeq, SignAnyLt, SignAnyLte, SignAnyGt, SignAnyGte,
+		SignAllEq, SignAllNeq, SignAllLt, SignAllLte, SignAllGt, SignAllGte:
+		total++
+	case SignAnyLike, SignAnyNlike, SignAllLike, SignAllNlike:
+		total += 2
+	case SignIn, SignNotIn:
+		if items, err := SplitList(expr.Right.Literal); err == nil && len(items) > 1 {
+			total += len(items) - 1
+		}
+	}
+
+	return total
+}
+
+// MaxComplexity limits the maximum Complexity score of the parsed
+// filter, returning an error as soon as the limit is exceeded.
+//
+// Non-positive max disables the limit.
+func MaxComplexity(max int) ParseOption {
+	return func(p *parser) {
+		p.maxComplexity = max
+	}
+}
+
+// checkComplexity adds the cost of a top-level item to the
+// parser's complexity total and checks it against the limit.
+func (p *parser) checkComplexity(item interface{}) error {
+	if p.maxComplexity <= 0 || len(p.path) > 0 {
+		return nil
+	}
+
+	p.complexity += itemComplexity(item, 0)
+
+	if p.complexity > p.maxComplexity {
+		return newLimitError(ErrMaxComplexity, "filter complexity exceeds the maximum allowed %d", p.maxComplexity, p.complexity)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestComplexity(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected int
+	}{
+		{`a = 1`, 1},
+		{`a = 1 && b > 2 || c != 3`, 3},
+		{`a ~ 1`, 2},
+		{`a like "b%"`, 2},
+		{`a ?= 1 || a *!= 1`, 4},
+		{`a ?~ 1`, 3},
+		{`a in (1)`, 1},
+		{`a not in (1, 2, 3)`, 3},
+		{`(a = 1)`, 2},
+		{`((a = 1))`, 4},
+		{`a = 1 && (b = 2 || (c ~ 3 && d = 4))`, 1 + 1 + 1 + 2 + 2 + 1},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := Complexity(exprs); v != s.expected {
+				t.Fatalf("Expected %d, got %d", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestMaxComplexity(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		max           int
+		expectedError bool
+	}{
+		{`a = 1`, 0, false},
+		{`a = 1 && b = 2 && c = 3`, -1, false},
+		{`a = 1`, 1, false},
+		{`a ~ 1`, 1, true},
+		{`a = 1 && b = 2 && c = 3`, 3, false},
+		{`a = 1 && b = 2 && c = 3`, 2, true},
+		{`(a = 1 && b = 2) && c = 3`, 3, true},
+		{`(a = 1 && b = 2) && c = 3`, 4, false},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			_, err := Parse(s.input, MaxComplexity(s.max))
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMaxComplexityParseFunc(t *testing.T) {
+	var calls int
+
+	err := ParseFunc(`a = 1 && b = 2 && c = 3`, func(g ExprGroup) error {
+		calls++
+		return nil
+	}, MaxComplexity(2))
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	// the limit should be checked before emitting the exceeding group
+	if calls != 2 {
+		t.Fatalf("Expected 2 fn calls, got %d", calls)
+	}
+}
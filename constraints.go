@@ -0,0 +1,195 @@
+package fexpr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FieldConstraints represents the equality and range constraints
+// of a single identifier implied by a filter.
+type FieldConstraints struct {
+	// Equals holds the allowed values of the identifier (from the `=`
+	// and `in` expressions) or nil if its values are not constrained.
+	//
+	// An empty non-nil Equals means that the constraints are contradictory
+	// (eg. `a = 1 && a = 2`).
+	Equals []Token
+
+	// Lower is the strictest lower bound of the identifier (nil if missing).
+	Lower *Bound
+
+	// Upper is the strictest upper bound of the identifier (nil if missing).
+	Upper *Bound
+}
+
+// Bound represents a single range constraint bound.
+type Bound struct {
+	Value     Token
+	Inclusive bool
+}
+
+// Constraints returns the equality and range constraints of each
+// identifier implied by the `&&` structure of the provided parsed filter
+// (eg. `a = 1 && b > 2 && (b <= 10 && c ~ "x")`).
+//
+// The nested groups with `||` operands are ignored, and an empty map is
+// returned if the filter itself has top-level `||` operands. The OR
+// branches could be handled separately by calling Constraints for each
+// ToDNF term.
+//
+// Only the expressions with identifier and literal (number, text or list)
+// operands are considered (eg. `5 < a` is treated as `a > 5`).
+// The numeric bounds are compared by their value and the text bounds
+// lexicographically.
+func Constraints(exprs []ExprGroup) map[string]FieldConstraints {
+	result := map[string]FieldConstraints{}
+
+	if len(splitOr(exprs)) == 1 {
+		collectConstraints(result, orientExprs(exprs))
+	}
+
+	return result
+}
+
+// collectConstraints adds the constraints of the `&&` joined groups into result.
+func collectConstraints(result map[string]FieldConstraints, groups []ExprGroup) {
+	for _, g := range groups {
+		// the UnaryExpr items are skipped since the custom
+		// unary operators semantics is unknown
+		switch v := g.Item.(type) {
+		case []ExprGroup:
+			if len(splitOr(v)) == 1 {
+				collectConstraints(result, v)
+			}
+		case Expr:
+			if v.Left.Type != TokenIdentifier {
+				continue
+			}
+
+			c := result[v.Left.Literal]
+
+			switch v.Op {
+			case SignEq:
+				if isLiteralToken(v.Right) {
+					c.Equals = intersectTokens(c.Equals, []Token{v.Right})
+				}
+			case SignIn:
+				if values, err := SplitList(v.Right.Literal); err == nil && v.Right.Type == TokenList && areLiteralTokens(values) {
+					c.Equals = intersectTokens(c.Equals, values)
+				}
+			case SignGt, SignGte:
+				if isLiteralToken(v.Right) {
+					c.Lower = stricterBound(c.Lower, &Bound{Value: v.Right, Inclusive: v.Op == SignGte}, true)
+				}
+			case SignLt, SignLte:
+				if isLiteralToken(v.Right) {
+					c.Upper = stricterBound(c.Upper, &Bound{Value: v.Right, Inclusive: v.Op == SignLte}, false)
+				}
+			default:
+				continue
+			}
+
+			if c.Equals != nil || c.Lower != nil || c.Upper != nil {
+				result[v.Left.Literal] = c
+			}
+		}
+	}
+}
+
+// isLiteralToken checks if t is a number or text token.
+func isLiteralToken(t Token) bool {
+	return t.Type == TokenNumber || t.Type == TokenText
+}
+
+// areLiteralTokens checks if all tokens are number or text tokens.
+func areLiteralTokens(tokens []Token) bool {
+	for _, t := range tokens {
+		if !isLiteralToken(t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// intersectTokens returns the tokens of b that are also in a
+// (or all b tokens if a is nil).
+//
+// The tokens are compared by their value (see compareLiterals),
+// aka. `1` and `1.0` are the same value.
+func intersectTokens(a []Token, b []Token) []Token {
+	result := []Token{}
+
+	for _, t := range b {
+		if a == nil || containsLiteral(a, t) {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// containsLiteral checks if tokens contain a token with the same value as t.
+func containsLiteral(tokens []Token, t Token) bool {
+	for _, other := range tokens {
+		if cmp, ok := compareLiterals(other, t); ok && cmp == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stricterBound returns the stricter of the current and candidate bounds.
+//
+// The current bound is kept if the bounds values are not comparable.
+func stricterBound(current *Bound, candidate *Bound, lower bool) *Bound {
+	if current == nil {
+		return candidate
+	}
+
+	cmp, ok := compareLiterals(candidate.Value, current.Value)
+	if !ok {
+		return current
+	}
+
+	if cmp == 0 {
+		if current.Inclusive && !candidate.Inclusive {
+			return candidate
+		}
+		return current
+	}
+
+	if (lower && cmp > 0) || (!lower && cmp < 0) {
+		return candidate
+	}
+
+	return current
+}
+
+// compareLiterals compares two number or text tokens
+// and reports whether they are comparable.
+func compareLiterals(a Token, b Token) (int, bool) {
+	if a.Type != b.Type {
+		return 0, false
+	}
+
+	if a.Type == TokenText {
+		return strings.Compare(a.Literal, b.Literal), true
+	}
+
+	x, errX := strconv.ParseFloat(a.Literal, 64)
+	y, errY := strconv.ParseFloat(b.Literal, 64)
+	if errX != nil || errY != nil {
+		return 0, false
+	}
+
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
@@ -0,0 +1,69 @@
+package fexpr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestConstraints(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1 || b = 2`, ``},
+		{`a = b && c ~ 1 && a ?= 1 && 1 = 2`, ``},
+		{`a = 1`, `a: [{number 1}] <nil> <nil>`},
+		{`a = 1 && a = 2`, `a: [] <nil> <nil>`},
+		{`a in (1, 2, "x") && "x" = a`, `a: [{text x}] <nil> <nil>`},
+		{`a in (1, 2) && (b = 1 || c = 2) && (a in (2, 3) && 5 < d)`, "a: [{number 2}] <nil> <nil>\nd: [] &{{number 5} false} <nil>"},
+		{`a > 1 && a >= 5 && a <= 10 && 10 > a`, `a: [] &{{number 5} true} &{{number 10} false}`},
+		{`a >= 1 && a > 1`, `a: [] &{{number 1} false} <nil>`},
+		{`a > "2024-01-01" && a > "2023-01-01" && a < "2025"`, `a: [] &{{text 2024-01-01} false} &{{text 2025} false}`},
+		{`a > 1 && a > "x"`, `a: [] &{{number 1} false} <nil>`},
+		{`a = 1 && a = 1.0`, `a: [{number 1.0}] <nil> <nil>`},
+		{`a in (1, 2) && a = 2.0`, `a: [{number 2.0}] <nil> <nil>`},
+		{`a = 1 && a = "1"`, `a: [] <nil> <nil>`},
+		{`a in (b, 1)`, ``},
+		{`a = 1 && a in (b, 2)`, `a: [{number 1}] <nil> <nil>`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := Constraints(exprs)
+
+			lines := []string{}
+			for field, c := range result {
+				lines = append(lines, fmt.Sprintf("%s: %v %v %v", field, c.Equals, c.Lower, c.Upper))
+			}
+			sort.Strings(lines)
+
+			if v := strings.Join(lines, "\n"); v != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestConstraintsContradiction(t *testing.T) {
+	exprs, err := Parse(`a = 1 && a in (2, 3) && b > 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := Constraints(exprs)
+
+	if v := result["a"].Equals; v == nil || len(v) != 0 {
+		t.Fatalf("Expected empty non-nil Equals, got %#v", v)
+	}
+
+	if v := result["b"].Equals; v != nil {
+		t.Fatalf("Expected nil Equals, got %#v", v)
+	}
+}
@@ -0,0 +1,139 @@
+package fexpr
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// fieldPath returns the `.` separated segments of a plain field
+// identifier operand (eg. "author.name" => "author", "name").
+//
+// An error is returned if t is not an identifier or it has special
+// segments (eg. `@request.id`, `items[0]`, `tags.*`, `a:length`),
+// which usually cannot be expressed in the converters target syntax.
+func fieldPath(t Token) ([]string, error) {
+	if t.Type != TokenIdentifier {
+		return nil, errorf(ErrInvalidOperand, "expected field identifier, got %q (%s)", t.Literal, t.Type)
+	}
+
+	segments := SplitIdentifier(t.Literal)
+	result := make([]string, 0, len(segments))
+
+	for _, s := range segments {
+		if (s.Separator != 0 && s.Separator != '.') || s.IsWildcard() || !isPlainFieldName(s.Literal) {
+			return nil, errorf(ErrInvalidOperand, "unsupported field identifier %q", t.Literal)
+		}
+
+		result = append(result, s.Literal)
+	}
+
+	return result, nil
+}
+
+// isPlainFieldName checks if name consists only of letters,
+// digits and `_` and doesn't start with a digit.
+func isPlainFieldName(name string) bool {
+	if name == "" || isDigitRune(rune(name[0])) {
+		return false
+	}
+
+	for _, ch := range name {
+		if !isLetterRune(ch) && !isDigitRune(ch) && ch != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// literalValue returns the Go value of a literal operand token
+// and whether t is a literal:
+//   - number - int64 or float64 (or json.Number if the token has Number, see PreciseNumbers)
+//   - text - string
+//   - `null`, `true` and `false` identifiers - nil, true and false
+//   - null and bool - nil, true and false (see EnableFeatures)
+//   - list - []interface{} with the values of its items
+//
+// All other identifiers are not literals (aka. field references).
+func literalValue(t Token) (interface{}, bool) {
+	switch t.Type {
+	case TokenNumber:
+		// preserve the precise number literal as it is
+		if t.Number != nil && isNumber(t.Literal) {
+			return jsonNumber(t.Literal), true
+		}
+		if v, err := strconv.ParseInt(t.Literal, 10, 64); err == nil {
+			return v, true
+		}
+		v, err := strconv.ParseFloat(t.Literal, 64)
+		return v, err == nil
+	case TokenText:
+		return t.Literal, true
+	case TokenNull:
+		return nil, true
+	case TokenBool:
+		return t.Literal == "true", true
+	case TokenIdentifier:
+		switch {
+		case isWordToken(t, "null"):
+			return nil, true
+		case isWordToken(t, "true"):
+			return true, true
+		case isWordToken(t, "false"):
+			return false, true
+		}
+	case TokenList:
+		items, err := SplitList(t.Literal)
+		if err != nil {
+			return nil, false
+		}
+
+		values := make([]interface{}, len(items))
+		for i, item := range items {
+			v, ok := literalValue(item)
+			if !ok {
+				return nil, false
+			}
+			values[i] = v
+		}
+
+		return values, true
+	}
+
+	return nil, false
+}
+
+// jsonNumber returns the json.Number of a valid number literal
+// without its redundant leading zeros (eg. "-007.50" => "-7.50").
+func jsonNumber(literal string) json.Number {
+	sign := ""
+	if strings.HasPrefix(literal, "-") {
+		sign = "-"
+		literal = literal[1:]
+	}
+
+	literal = strings.TrimLeft(literal, "0")
+	if literal == "" || literal[0] == '.' {
+		literal = "0" + literal
+	}
+
+	return json.Number(sign + literal)
+}
+
+// containsPattern returns the LIKE pattern of a `~` operator text
+// operand, aka. the text wrapped with `%` if it doesn't contain
+// explicit `%` wildcards (eg. "test" => "%test%").
+func containsPattern(text string) string {
+	if strings.Contains(text, "%") {
+		return text
+	}
+
+	return "%" + text + "%"
+}
+
+// unsupportedSignOpError returns an error for a sign operator
+// that cannot be converted to the specified target.
+func unsupportedSignOpError(op SignOp, target string) error {
+	return errorf(ErrUnsupportedSignOp, "sign operator %q is not supported by %s", op, target)
+}
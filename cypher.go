@@ -0,0 +1,210 @@
+package fexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cypherComparators maps the sign operators to their Cypher comparison operators.
+var cypherComparators = map[SignOp]string{
+	SignEq:  "=",
+	SignNeq: "<>",
+	SignLt:  "<",
+	SignLte: "<=",
+	SignGt:  ">",
+	SignGte: ">=",
+}
+
+// ToCypher converts the provided parsed filter into a Neo4j Cypher
+// WHERE clause condition for the properties of the specified node
+// or relationship variable, for example `age > 18 && name ~ "john"`
+// with variable "n" becomes `n.age > $p0 AND n.name =~ $p1`
+// with params {"p0": 18, "p1": "(?is).*john.*"}.
+//
+// The literal operands are converted to `$p*` query parameters and the
+// identifier operands must be plain field identifiers (nested fields
+// are converted to nested map property access).
+//
+// The `~` and `!~` operators are converted to case-insensitive contains
+// regex matches, the `like` and `ilike` patterns to regex matches,
+// the `= null` and `!= null` comparisons to `IS NULL` and `IS NOT NULL`
+// and the array/any and array/all operators to any() and all() list predicates.
+func ToCypher(exprs []ExprGroup, variable string) (string, map[string]interface{}, error) {
+	c := &cypherConverter{
+		variable: variable,
+		params:   map[string]interface{}{},
+	}
+
+	var sb strings.Builder
+
+	if err := c.writeGroups(&sb, exprs); err != nil {
+		return "", nil, err
+	}
+
+	return sb.String(), c.params, nil
+}
+
+// cypherConverter holds the ToCypher state.
+type cypherConverter struct {
+	// variable is the node or relationship variable of the field properties
+	variable string
+
+	// params holds the registered query parameters
+	params map[string]interface{}
+}
+
+// writeGroups writes the Cypher condition of the groups slice.
+func (c *cypherConverter) writeGroups(sb *strings.Builder, groups []ExprGroup) error {
+	for i, g := range groups {
+		if i > 0 {
+			if g.Join == JoinOr {
+				sb.WriteString(" OR ")
+			} else {
+				sb.WriteString(" AND ")
+			}
+		}
+
+		switch v := g.Item.(type) {
+		case Expr:
+			if err := c.writeExpr(sb, v); err != nil {
+				return err
+			}
+		case []ExprGroup:
+			sb.WriteString("(")
+			if err := c.writeGroups(sb, v); err != nil {
+				return err
+			}
+			sb.WriteString(")")
+		default:
+			return errorf(ErrUnsupportedItem, "unsupported expression group item %T", g.Item)
+		}
+	}
+
+	return nil
+}
+
+// writeExpr writes the Cypher condition of a single expression.
+func (c *cypherConverter) writeExpr(sb *strings.Builder, expr Expr) error {
+	left, err := c.operand(expr.Left)
+	if err != nil {
+		return err
+	}
+
+	op := expr.Op
+
+	// array/any and array/all operators
+	if strings.HasPrefix(string(op), "?") || strings.HasPrefix(string(op), "*") {
+		quantifier := "any"
+		if op[0] == '*' {
+			quantifier = "all"
+		}
+
+		// the list element variable must not shadow the fields variable
+		item := "x"
+		if c.variable == item {
+			item = "x0"
+		}
+
+		condition, err := c.condition(item, op[1:], expr.Right)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(sb, "%s(%s IN %s WHERE %s)", quantifier, item, left, condition)
+
+		return nil
+	}
+
+	condition, err := c.condition(left, op, expr.Right)
+	if err != nil {
+		return err
+	}
+
+	sb.WriteString(condition)
+
+	return nil
+}
+
+// condition returns the Cypher condition comparing the left value with
+// the right operand token using the specified (non-array) sign operator.
+func (c *cypherConverter) condition(left string, op SignOp, right Token) (string, error) {
+	if comparator, ok := cypherComparators[op]; ok {
+		if isNullToken(right) && op == SignEq {
+			return left + " IS NULL", nil
+		}
+
+		if isNullToken(right) && op == SignNeq {
+			return left + " IS NOT NULL", nil
+		}
+
+		value, err := c.operand(right)
+		if err != nil {
+			return "", err
+		}
+
+		return left + " " + comparator + " " + value, nil
+	}
+
+	var regex string
+	var negate bool
+
+	switch op {
+	case SignIn, SignNotIn:
+		value, ok := literalValue(right)
+		if !ok {
+			return "", errorf(ErrInvalidOperand, "expected literal values list, got %q (%s)", right.Literal, right.Type)
+		}
+
+		if op == SignNotIn {
+			return "NOT " + left + " IN " + c.param(value), nil
+		}
+
+		return left + " IN " + c.param(value), nil
+	case SignLike, SignNlike:
+		regex = "(?is)" + likeRegexp(containsPattern(right.Literal))
+		negate = op == SignNlike
+	case SignSQLLike, SignSQLNlike:
+		regex = "(?s)" + likeRegexp(right.Literal)
+		negate = op == SignSQLNlike
+	case SignSQLIlike, SignSQLNilike:
+		regex = "(?is)" + likeRegexp(right.Literal)
+		negate = op == SignSQLNilike
+	default:
+		return "", unsupportedSignOpError(op, "Cypher")
+	}
+
+	if right.Type != TokenText {
+		return "", errorf(ErrInvalidOperand, "expected text right operand for %q, got %q (%s)", op, right.Literal, right.Type)
+	}
+
+	result := left + " =~ " + c.param(regex)
+	if negate {
+		result = "NOT " + result
+	}
+
+	return result, nil
+}
+
+// operand converts a single operand token into a Cypher
+// query parameter or variable property access.
+func (c *cypherConverter) operand(t Token) (string, error) {
+	if value, ok := literalValue(t); ok && t.Type != TokenList {
+		return c.param(value), nil
+	}
+
+	path, err := fieldPath(t)
+	if err != nil {
+		return "", err
+	}
+
+	return c.variable + "." + strings.Join(path, "."), nil
+}
+
+// param registers a new query parameter and returns its reference.
+func (c *cypherConverter) param(value interface{}) string {
+	key := "p" + strconv.Itoa(len(c.params))
+	c.params[key] = value
+
+	return "$" + key
+}
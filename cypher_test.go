@@ -0,0 +1,108 @@
+package fexpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestToCypher(t *testing.T) {
+	scenarios := []struct {
+		input             string
+		expectedError     bool
+		expectedCondition string
+		expectedParams    string
+	}{
+		{`items[0] = 1`, true, ``, ``},
+		{`a = @request.id`, true, ``, ``},
+		{`a ~ b`, true, ``, ``},
+		{`a like 1`, true, ``, ``},
+		{`a in (1, b)`, true, ``, ``},
+		{`tags ?= b[0]`, true, ``, ``},
+		{`a = 1`, false, `n.a = $p0`, `{"p0":1}`},
+		{
+			`a.b != "x" && 1.5 < c || d >= e.f && g <= null`,
+			false,
+			`n.a.b <> $p0 AND $p1 < n.c OR n.d >= n.e.f AND n.g <= $p2`,
+			`{"p0":"x","p1":1.5,"p2":null}`,
+		},
+		{
+			`age > 18 && (a = null || b != null)`,
+			false,
+			`n.age > $p0 AND (n.a IS NULL OR n.b IS NOT NULL)`,
+			`{"p0":18}`,
+		},
+		{
+			`a ~ "john" && b !~ "x%.y"`,
+			false,
+			`n.a =~ $p0 AND NOT n.b =~ $p1`,
+			`{"p0":"(?is).*john.*","p1":"(?is)x.*\\.y"}`,
+		},
+		{
+			`a like "x_" && b not ilike "%y"`,
+			false,
+			`n.a =~ $p0 AND NOT n.b =~ $p1`,
+			`{"p0":"(?s)x.","p1":"(?is).*y"}`,
+		},
+		{
+			`a in (1, "x") && b not in (true)`,
+			false,
+			`n.a IN $p0 AND NOT n.b IN $p1`,
+			`{"p0":[1,"x"],"p1":[true]}`,
+		},
+		{
+			`tags ?= "x" && scores *> min && tags ?!~ "y" && tags *!= null`,
+			false,
+			`any(x IN n.tags WHERE x = $p0) AND all(x IN n.scores WHERE x > n.min) AND any(x IN n.tags WHERE NOT x =~ $p1) AND all(x IN n.tags WHERE x IS NOT NULL)`,
+			`{"p0":"x","p1":"(?is).*y.*"}`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			condition, params, err := ToCypher(exprs, "n")
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if s.expectedError {
+				return
+			}
+
+			if condition != s.expectedCondition {
+				t.Fatalf("Expected condition %s, got %s", s.expectedCondition, condition)
+			}
+
+			encoded, _ := json.Marshal(params)
+			if v := string(encoded); v != s.expectedParams {
+				t.Fatalf("Expected params %s, got %s", s.expectedParams, v)
+			}
+		})
+	}
+}
+
+func TestToCypherListVariable(t *testing.T) {
+	exprs, err := Parse(`tags ?= min`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition, _, err := ToCypher(exprs, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `any(x0 IN x.tags WHERE x0 = x.min)`; condition != expected {
+		t.Fatalf("Expected condition %s, got %s", expected, condition)
+	}
+}
@@ -0,0 +1,170 @@
+package fexpr
+
+import "strings"
+
+// DiffResult represents the semantic difference between two parsed filters.
+//
+// The Added and Removed conditions are ExprGroup.Item values,
+// aka. Expr or []ExprGroup.
+type DiffResult struct {
+	// Added holds the conditions that exist only in the new filter.
+	Added []interface{}
+
+	// Removed holds the conditions that exist only in the old filter.
+	Removed []interface{}
+
+	// Changed holds the expressions that have the same left operand
+	// but different sign operator or right operand.
+	Changed []ChangedExpr
+}
+
+// ChangedExpr represents a single changed filter expression.
+type ChangedExpr struct {
+	From Expr
+	To   Expr
+}
+
+// IsEmpty checks if the compared filters have no semantic difference.
+func (d DiffResult) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String returns the human readable representation of the difference
+// with one condition per line, prefixed with "+" for the added, "-" for
+// the removed and "~" for the changed conditions (eg. `~ a > 1 => a >= 2`).
+func (d DiffResult) String() string {
+	lines := make([]string, 0, len(d.Added)+len(d.Removed)+len(d.Changed))
+
+	for _, item := range d.Added {
+		lines = append(lines, "+ "+itemString(item))
+	}
+
+	for _, item := range d.Removed {
+		lines = append(lines, "- "+itemString(item))
+	}
+
+	for _, c := range d.Changed {
+		lines = append(lines, "~ "+itemString(c.From)+" => "+itemString(c.To))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Diff reports the added, removed and changed conditions between
+// the old filter a and the new filter b.
+//
+// Both filters are compared in their Normalize form, so reordering
+// the conditions or adding redundant groups is not reported as a change.
+//
+// The compared conditions are the `&&` operands of the filters,
+// or the `||` operands if a filter has top-level `||` joins.
+// An added and a removed expression are reported as changed
+// if they are the only ones with the same left operand.
+func Diff(a, b []ExprGroup) DiffResult {
+	aItems, aKeys := diffConditions(a)
+	bItems, bKeys := diffConditions(b)
+
+	result := DiffResult{
+		Removed: diffItems(aItems, aKeys, bKeys),
+		Added:   diffItems(bItems, bKeys, aKeys),
+	}
+
+	removedExprs := diffExprsByLeft(result.Removed)
+	addedExprs := diffExprsByLeft(result.Added)
+
+	if len(removedExprs) == 0 || len(addedExprs) == 0 {
+		return result
+	}
+
+	// pair the added and removed expressions with unique matching left operand
+	removed := result.Removed[:0:0]
+	for _, item := range result.Removed {
+		from, ok := item.(Expr)
+		if ok && len(removedExprs[from.Left]) == 1 && len(addedExprs[from.Left]) == 1 {
+			result.Changed = append(result.Changed, ChangedExpr{From: from, To: addedExprs[from.Left][0]})
+			continue
+		}
+
+		removed = append(removed, item)
+	}
+
+	added := result.Added[:0:0]
+	for _, item := range result.Added {
+		to, ok := item.(Expr)
+		if ok && len(removedExprs[to.Left]) == 1 && len(addedExprs[to.Left]) == 1 {
+			continue
+		}
+
+		added = append(added, item)
+	}
+
+	result.Removed = removed
+	result.Added = added
+
+	return result
+}
+
+// diffConditions returns the normalized conditions of a filter and their keys.
+func diffConditions(exprs []ExprGroup) ([]interface{}, []string) {
+	parts := splitOr(Normalize(exprs))
+
+	items := []interface{}{}
+
+	if len(parts) == 1 {
+		for _, g := range parts[0] {
+			items = append(items, g.Item)
+		}
+	} else {
+		for _, part := range parts {
+			if len(part) == 1 {
+				items = append(items, part[0].Item)
+				continue
+			}
+
+			conjunction := make([]ExprGroup, len(part))
+			copy(conjunction, part)
+			conjunction[0].Join = JoinAnd
+
+			items = append(items, conjunction)
+		}
+	}
+
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = itemString(item)
+	}
+
+	return items, keys
+}
+
+// diffItems returns the items which keys are not in the excluded keys.
+func diffItems(items []interface{}, keys []string, excluded []string) []interface{} {
+	excludedSet := make(map[string]struct{}, len(excluded))
+	for _, k := range excluded {
+		excludedSet[k] = struct{}{}
+	}
+
+	result := []interface{}{}
+
+	for i, item := range items {
+		if _, ok := excludedSet[keys[i]]; !ok {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// diffExprsByLeft groups the Expr items by their left operand.
+func diffExprsByLeft(items []interface{}) map[Token][]Expr {
+	result := map[Token][]Expr{}
+
+	for _, item := range items {
+		if expr, ok := item.(Expr); ok {
+			key := Token{Type: expr.Left.Type, Literal: expr.Left.Literal} // without Number
+			result[key] = append(result[key], expr)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,65 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	scenarios := []struct {
+		a        string
+		b        string
+		expected string
+	}{
+		{`a = 1`, `a = 1`, ``},
+		{`a = 1 && b = 2`, `(b = 2) && a = 1 && a = 1`, ``},
+		{`a = 1`, `a = 1 && archived = false`, `+ archived = false`},
+		{`a = 1 && archived = false`, `a = 1`, `- archived = false`},
+		{`a = 1 && b > 2`, `a = 1 && b >= 3`, `~ b > 2 => b >= 3`},
+		{`a = 1 && b = 2`, `a = 2 && b = 2 && c = 3`, "+ c = 3\n~ a = 1 => a = 2"},
+		// ambiguous left operand
+		{`a = 1 && a = 2`, `a = 3`, "+ a = 3\n- a = 1\n- a = 2"},
+		// non-expression conditions
+		{`a = 1 || b = 2`, `a = 1 || b = 3 || (c = 1 && d = 2)`, "+ (c = 1 && d = 2)\n~ b = 2 => b = 3"},
+		{`a = 1 && (b = 1 || c = 1)`, `a = 1 && (b = 1 || c = 2)`, "+ (b = 1 || c = 2)\n- (b = 1 || c = 1)"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s_%s", i, s.a, s.b), func(t *testing.T) {
+			a, err := Parse(s.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := Parse(s.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := Diff(a, b)
+
+			if v := result.String(); v != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, v)
+			}
+
+			if result.IsEmpty() != (s.expected == "") {
+				t.Fatalf("Expected IsEmpty %v", s.expected == "")
+			}
+		})
+	}
+}
+
+func TestDiffEmpty(t *testing.T) {
+	exprs, err := Parse(`a = 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := Diff(nil, exprs).String(); v != "+ a = 1" {
+		t.Fatalf("Expected added condition, got %q", v)
+	}
+
+	if v := Diff(exprs, nil).String(); v != "- a = 1" {
+		t.Fatalf("Expected removed condition, got %q", v)
+	}
+}
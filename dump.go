@@ -0,0 +1,54 @@
+package fexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dump returns a human readable indented ASCII tree representation
+// of the provided parsed filter, intended for debugging and tests.
+//
+// Each group is rendered on its own line with its join operator
+// followed either by the expression operands and sign operator
+// or by "group" for the nested groups, for example:
+//
+//	|-- && identifier "a" = number "1"
+//	`-- || group
+//	    |-- && identifier "b" > text "c"
+//	    `-- && identifier "d" in list "1, 2"
+func Dump(exprs []ExprGroup) string {
+	var sb strings.Builder
+
+	writeDump(&sb, exprs, "")
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// writeDump writes the tree lines of groups into sb
+// prefixing each line with indent.
+func writeDump(sb *strings.Builder, groups []ExprGroup, indent string) {
+	for i, g := range groups {
+		branch, childIndent := "|-- ", "|   "
+		if i == len(groups)-1 {
+			branch, childIndent = "`-- ", "    "
+		}
+
+		sb.WriteString(indent)
+		sb.WriteString(branch)
+		sb.WriteString(string(g.Join))
+		sb.WriteString(" ")
+
+		switch v := g.Item.(type) {
+		case Expr:
+			fmt.Fprintf(sb, "%s %q %s %s %q\n", v.Left.Type, v.Left.Literal, v.Op, v.Right.Type, v.Right.Literal)
+		case []ExprGroup:
+			sb.WriteString("group\n")
+			writeDump(sb, v, indent+childIndent)
+		case UnaryExpr:
+			fmt.Fprintf(sb, "unary %s\n", v.Op)
+			writeDump(sb, unaryOperand(v), indent+childIndent)
+		default:
+			fmt.Fprintf(sb, "unknown %T\n", g.Item)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1`, `` + "`" + `-- && identifier "a" = number "1"`},
+		{
+			`a = 1 || (b > "c" && (d in (1, 2))) && e != f`,
+			"|-- && identifier \"a\" = number \"1\"\n" +
+				"|-- || group\n" +
+				"|   |-- && identifier \"b\" > text \"c\"\n" +
+				"|   `-- && group\n" +
+				"|       `-- && identifier \"d\" in list \"1, 2\"\n" +
+				"`-- && identifier \"e\" != identifier \"f\"",
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := Dump(exprs); v != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestDumpEmpty(t *testing.T) {
+	if v := Dump(nil); v != "" {
+		t.Fatalf("Expected empty string, got %q", v)
+	}
+
+	if v := Dump([]ExprGroup{{Join: JoinAnd, Item: 123}}); v != "`-- && unknown int" {
+		t.Fatalf("Unexpected dump %q", v)
+	}
+}
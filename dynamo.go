@@ -0,0 +1,242 @@
+package fexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dynamoComparators maps the sign operators to their DynamoDB
+// condition expression comparators.
+var dynamoComparators = map[SignOp]string{
+	SignEq:  "=",
+	SignNeq: "<>",
+	SignLt:  "<",
+	SignLte: "<=",
+	SignGt:  ">",
+	SignGte: ">=",
+}
+
+// DynamoExpression represents a DynamoDB filter (or condition) expression
+// together with its expression attribute names and values placeholders.
+type DynamoExpression struct {
+	// Filter is the FilterExpression string (eg. `#n0 = :v0 AND #n1 > :v1`).
+	Filter string
+
+	// Names holds the ExpressionAttributeNames (eg. "#n0" => "status").
+	Names map[string]string
+
+	// Values holds the ExpressionAttributeValues
+	// (eg. ":v0" => "active") as plain Go values
+	// (int64, float64, string, bool or nil).
+	Values map[string]interface{}
+}
+
+// ToDynamo converts the provided parsed filter into a DynamoDB
+// FilterExpression, for example `status = "active" && (age > 18 || name ~ "john")`
+// becomes `#n0 = :v0 AND (#n1 > :v1 OR contains(#n2, :v2))`.
+//
+// All attribute names are replaced with `#n*` placeholders (so that
+// reserved words like "status" and "name" are always accepted) and all
+// literal values with `:v*` placeholders.
+//
+// The left operands must be plain field identifiers (nested fields are
+// converted to nested attribute paths) and the right operands must be literals.
+// The `~` and `?=` operators are converted to `contains()`, the `like` (and `~`
+// with explicit wildcards) patterns with a single trailing `%` wildcard to
+// `begins_with()` and the `= null`
+// and `!= null` comparisons to `attribute_not_exists()` and `attribute_exists()`.
+//
+// An error is returned for all other operators.
+func ToDynamo(exprs []ExprGroup) (DynamoExpression, error) {
+	c := &dynamoConverter{
+		names:    map[string]string{},
+		nameKeys: map[string]string{},
+		values:   map[string]interface{}{},
+	}
+
+	var sb strings.Builder
+
+	if err := c.writeGroups(&sb, exprs); err != nil {
+		return DynamoExpression{}, err
+	}
+
+	return DynamoExpression{
+		Filter: sb.String(),
+		Names:  c.names,
+		Values: c.values,
+	}, nil
+}
+
+// dynamoConverter holds the ToDynamo placeholders state.
+type dynamoConverter struct {
+	// names holds the registered attribute name placeholders
+	names map[string]string
+
+	// nameKeys holds the placeholders keyed by their attribute name
+	nameKeys map[string]string
+
+	// values holds the registered attribute value placeholders
+	values map[string]interface{}
+}
+
+// writeGroups writes the condition expression of the groups slice.
+func (c *dynamoConverter) writeGroups(sb *strings.Builder, groups []ExprGroup) error {
+	for i, g := range groups {
+		if i > 0 {
+			if g.Join == JoinOr {
+				sb.WriteString(" OR ")
+			} else {
+				sb.WriteString(" AND ")
+			}
+		}
+
+		switch v := g.Item.(type) {
+		case Expr:
+			if err := c.writeExpr(sb, v); err != nil {
+				return err
+			}
+		case []ExprGroup:
+			sb.WriteString("(")
+			if err := c.writeGroups(sb, v); err != nil {
+				return err
+			}
+			sb.WriteString(")")
+		default:
+			return errorf(ErrUnsupportedItem, "unsupported expression group item %T", g.Item)
+		}
+	}
+
+	return nil
+}
+
+// writeExpr writes the condition expression of a single expression.
+func (c *dynamoConverter) writeExpr(sb *strings.Builder, expr Expr) error {
+	path, err := fieldPath(expr.Left)
+	if err != nil {
+		return err
+	}
+
+	value, ok := literalValue(expr.Right)
+	if !ok {
+		return errorf(ErrInvalidOperand, "expected literal right operand, got %q (%s)", expr.Right.Literal, expr.Right.Type)
+	}
+
+	name := c.name(path)
+
+	if comparator, ok := dynamoComparators[expr.Op]; ok {
+		switch {
+		case value == nil && expr.Op == SignEq:
+			fmt.Fprintf(sb, "attribute_not_exists(%s)", name)
+		case value == nil && expr.Op == SignNeq:
+			fmt.Fprintf(sb, "attribute_exists(%s)", name)
+		default:
+			fmt.Fprintf(sb, "%s %s %s", name, comparator, c.value(value))
+		}
+
+		return nil
+	}
+
+	switch expr.Op {
+	case SignIn, SignNotIn:
+		items := value.([]interface{})
+		placeholders := make([]string, len(items))
+		for i, item := range items {
+			placeholders[i] = c.value(item)
+		}
+
+		if expr.Op == SignNotIn {
+			sb.WriteString("NOT ")
+		}
+		fmt.Fprintf(sb, "%s IN (%s)", name, strings.Join(placeholders, ", "))
+	case SignLike, SignNlike, SignAnyEq:
+		// the `~` patterns with explicit wildcards are not contains checks
+		if text, ok := value.(string); ok && expr.Op != SignAnyEq && strings.Contains(text, "%") {
+			return c.writeBeginsWith(sb, name, expr, expr.Op == SignNlike)
+		}
+
+		if expr.Op == SignNlike {
+			sb.WriteString("NOT ")
+		}
+		fmt.Fprintf(sb, "contains(%s, %s)", name, c.value(value))
+	case SignSQLLike, SignSQLNlike:
+		return c.writeBeginsWith(sb, name, expr, expr.Op == SignSQLNlike)
+	default:
+		return unsupportedSignOpError(expr.Op, "DynamoDB")
+	}
+
+	return nil
+}
+
+// writeBeginsWith writes the begins_with() condition of a like
+// expression with a single trailing `%` wildcard pattern.
+func (c *dynamoConverter) writeBeginsWith(sb *strings.Builder, name string, expr Expr, negate bool) error {
+	value, _ := literalValue(expr.Right)
+
+	prefix, ok := likePrefix(value)
+	if !ok {
+		return errorf(ErrInvalidOperand, "only prefix patterns (eg. \"abc%%\") are supported by DynamoDB, got %q", expr.Right.Literal)
+	}
+
+	if negate {
+		sb.WriteString("NOT ")
+	}
+	fmt.Fprintf(sb, "begins_with(%s, %s)", name, c.value(prefix))
+
+	return nil
+}
+
+// name returns the `.` joined attribute name placeholders of path
+// registering the ones that are not registered yet.
+func (c *dynamoConverter) name(path []string) string {
+	placeholders := make([]string, len(path))
+
+	for i, segment := range path {
+		key, ok := c.nameKeys[segment]
+		if !ok {
+			key = "#n" + strconv.Itoa(len(c.names))
+			c.nameKeys[segment] = key
+			c.names[key] = segment
+		}
+
+		placeholders[i] = key
+	}
+
+	return strings.Join(placeholders, ".")
+}
+
+// value registers a new attribute value placeholder and returns its name.
+func (c *dynamoConverter) value(value interface{}) string {
+	key := ":v" + strconv.Itoa(len(c.values))
+	c.values[key] = value
+
+	return key
+}
+
+// likePrefix returns the unescaped prefix of a `like` text pattern
+// that has only a single trailing `%` wildcard (eg. "abc%" => "abc").
+func likePrefix(value interface{}) (string, bool) {
+	text, ok := value.(string)
+	if !ok || !strings.HasSuffix(text, "%") {
+		return "", false
+	}
+
+	var sb strings.Builder
+
+	rest := text[:len(text)-1]
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '%', '_':
+			return "", false
+		case '\\':
+			if i+1 >= len(rest) {
+				return "", false
+			}
+			i++
+		}
+
+		sb.WriteByte(rest[i])
+	}
+
+	return sb.String(), true
+}
@@ -0,0 +1,102 @@
+package fexpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestToDynamo(t *testing.T) {
+	scenarios := []struct {
+		input          string
+		expectedError  bool
+		expectedFilter string
+		expectedNames  string
+		expectedValues string
+	}{
+		{`a = b`, true, ``, ``, ``},
+		{`1 = a`, true, ``, ``, ``},
+		{`items[0] = 1`, true, ``, ``, ``},
+		{`a ?> 1`, true, ``, ``, ``},
+		{`a ilike "x%"`, true, ``, ``, ``},
+		{`a like "%x"`, true, ``, ``, ``},
+		{`a like "x_%"`, true, ``, ``, ``},
+		{`a like "x\%"`, true, ``, ``, ``},
+		{`a ~ "%x"`, true, ``, ``, ``},
+		{`a !~ "%x%"`, true, ``, ``, ``},
+		{`a = 1`, false, `#n0 = :v0`, `{"#n0":"a"}`, `{":v0":1}`},
+		{`a ~ 'x%' && b !~ "y%"`, false, `begins_with(#n0, :v0) AND NOT begins_with(#n1, :v1)`, `{"#n0":"a","#n1":"b"}`, `{":v0":"x",":v1":"y"}`},
+		{
+			`status = "active" && (age > 18 || name ~ "john")`,
+			false,
+			`#n0 = :v0 AND (#n1 > :v1 OR contains(#n2, :v2))`,
+			`{"#n0":"status","#n1":"age","#n2":"name"}`,
+			`{":v0":"active",":v1":18,":v2":"john"}`,
+		},
+		{
+			`a.b != 1.5 && b.a <= 2 && a >= 3 && a < 4`,
+			false,
+			`#n0.#n1 <> :v0 AND #n1.#n0 <= :v1 AND #n0 >= :v2 AND #n0 < :v3`,
+			`{"#n0":"a","#n1":"b"}`,
+			`{":v0":1.5,":v1":2,":v2":3,":v3":4}`,
+		},
+		{
+			`a = null || b != null || c = true`,
+			false,
+			`attribute_not_exists(#n0) OR attribute_exists(#n1) OR #n2 = :v0`,
+			`{"#n0":"a","#n1":"b","#n2":"c"}`,
+			`{":v0":true}`,
+		},
+		{
+			`a in (1, "x") && b not in (2) && c !~ "y" && d ?= "z"`,
+			false,
+			`#n0 IN (:v0, :v1) AND NOT #n1 IN (:v2) AND NOT contains(#n2, :v3) AND contains(#n3, :v4)`,
+			`{"#n0":"a","#n1":"b","#n2":"c","#n3":"d"}`,
+			`{":v0":1,":v1":"x",":v2":2,":v3":"y",":v4":"z"}`,
+		},
+		{
+			`a like "x\_y%" && b not like "%"`,
+			false,
+			`begins_with(#n0, :v0) AND NOT begins_with(#n1, :v1)`,
+			`{"#n0":"a","#n1":"b"}`,
+			`{":v0":"x_y",":v1":""}`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := ToDynamo(exprs)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if s.expectedError {
+				return
+			}
+
+			if result.Filter != s.expectedFilter {
+				t.Fatalf("Expected filter %s, got %s", s.expectedFilter, result.Filter)
+			}
+
+			names, _ := json.Marshal(result.Names)
+			if v := string(names); v != s.expectedNames {
+				t.Fatalf("Expected names %s, got %s", s.expectedNames, v)
+			}
+
+			values, _ := json.Marshal(result.Values)
+			if v := string(values); v != s.expectedValues {
+				t.Fatalf("Expected values %s, got %s", s.expectedValues, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package fexpr
+
+import "strings"
+
+// mirroredSignOps holds the sign operators that could be applied
+// with swapped operands (aka. `1 < a` is the same as `a > 1`).
+var mirroredSignOps = map[SignOp]SignOp{
+	SignEq:  SignEq,
+	SignNeq: SignNeq,
+	SignLt:  SignGt,
+	SignGt:  SignLt,
+	SignLte: SignGte,
+	SignGte: SignLte,
+}
+
+// Equivalent checks if the provided parsed filters are logically
+// equivalent ignoring the conditions order, redundant groups,
+// duplicated conditions and the sides of the swappable expressions
+// (eg. `1 < a && b = 2` is equivalent to `(b = 2) && a > 1`).
+//
+// The check is structural and doesn't try to prove equivalence of
+// different but logically related conditions (eg. `a > 1` and `a >= 2`).
+func Equivalent(a, b []ExprGroup) bool {
+	return Stringify(Normalize(orientExprs(a))) == Stringify(Normalize(orientExprs(b)))
+}
+
+// orientExprs returns a copy of the provided groups with the
+// swappable expressions operands placed in a canonical order,
+// aka. the identifier operands first.
+func orientExprs(exprs []ExprGroup) []ExprGroup {
+	result := make([]ExprGroup, len(exprs))
+
+	for i, g := range exprs {
+		result[i] = g
+
+		switch v := g.Item.(type) {
+		case Expr:
+			result[i].Item = orientExpr(v)
+		case []ExprGroup:
+			result[i].Item = orientExprs(v)
+		case UnaryExpr:
+			result[i].Item, _ = mapUnaryOperand(v, orientExprs)
+		}
+	}
+
+	return result
+}
+
+// orientExpr swaps the operands of a mirrorable expression if its
+// right operand is an identifier and the left one is not, or if
+// both are of the same kind and the right literal sorts first.
+func orientExpr(expr Expr) Expr {
+	op, ok := mirroredSignOps[expr.Op]
+	if !ok {
+		return expr
+	}
+
+	leftIsIdentifier := expr.Left.Type == TokenIdentifier
+	rightIsIdentifier := expr.Right.Type == TokenIdentifier
+
+	if leftIsIdentifier && !rightIsIdentifier {
+		return expr
+	}
+
+	if leftIsIdentifier == rightIsIdentifier && tokenString(expr.Left) <= tokenString(expr.Right) {
+		return expr
+	}
+
+	return Expr{Left: expr.Right, Op: op, Right: expr.Left}
+}
+
+// tokenString returns the text representation of a single operand token.
+func tokenString(t Token) string {
+	var sb strings.Builder
+
+	writeToken(&sb, t)
+
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEquivalent(t *testing.T) {
+	scenarios := []struct {
+		a        string
+		b        string
+		expected bool
+	}{
+		{`a = 1`, `a = 1`, true},
+		{`a = 1`, `a = 2`, false},
+		{`a = 1 && b = 2`, `b = 2 && a = 1`, true},
+		{`a = 1 || b = 2`, `(b = 2) || a = 1 || a = 1`, true},
+		{`a = 1 || b = 2`, `a = 1 && b = 2`, false},
+		{`1 < a`, `a > 1`, true},
+		{`1 <= a && "x" != b`, `b != "x" && a >= 1`, true},
+		{`1 < a`, `a < 1`, false},
+		{`a = b`, `b = a`, true},
+		{`a < b`, `b > a`, true},
+		{`1 = 2`, `2 = 1`, true},
+		{`(c = 1 || 2 = d) && a = 1`, `a = 1 && (d = 2 || c = 1)`, true},
+		// non-mirrorable operators
+		{`"x" ~ a`, `a ~ "x"`, false},
+		{`1 ?= a`, `a ?= 1`, false},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s_%s", i, s.a, s.b), func(t *testing.T) {
+			a, err := Parse(s.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := Parse(s.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := Equivalent(a, b); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+
+			if v := Equivalent(b, a); v != s.expected {
+				t.Fatalf("Expected %v for the swapped arguments, got %v", s.expected, v)
+			}
+		})
+	}
+}
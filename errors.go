@@ -0,0 +1,337 @@
+package fexpr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The failure kinds of the returned errors that could be checked
+// with errors.Is (eg. `errors.Is(err, fexpr.ErrInvalidNumber)`).
+//
+// The returned errors keep their own descriptive messages, so the
+// sentinel errors text is used only when they are returned as they are.
+var (
+	// scan errors
+	ErrUnexpectedCharacter = errors.New("unexpected character")
+	ErrInvalidIdentifier   = errors.New("invalid identifier")
+	ErrInvalidNumber       = errors.New("invalid number")
+	ErrUnterminatedText    = errors.New("unterminated quoted text")
+	ErrInvalidEscape       = errors.New("invalid text escape sequence")
+	ErrUnterminatedGroup   = errors.New("unterminated group")
+	ErrInvalidComment      = errors.New("invalid comment")
+	ErrInvalidLiteral      = errors.New("invalid custom literal")
+	ErrInvalidSignOp       = errors.New("invalid sign operator")
+	ErrInvalidJoinOp       = errors.New("invalid join operator")
+	ErrUnsupportedEncoding = errors.New("unsupported input encoding")
+	ErrMaxTextLength       = errors.New("maximum text length exceeded")
+	ErrMaxInputLength      = errors.New("maximum input length exceeded")
+
+	// parse errors (see also ErrEmpty and ErrIncomplete)
+	ErrUnexpectedToken    = errors.New("unexpected token")
+	ErrInvalidList        = errors.New("invalid list")
+	ErrStrictMode         = errors.New("not allowed in strict mode")
+	ErrMaxDepth           = errors.New("maximum nesting depth exceeded")
+	ErrMaxComplexity      = errors.New("maximum complexity exceeded")
+	ErrRecursiveMacro     = errors.New("recursive macro")
+	ErrInvalidMacro       = errors.New("invalid macro")
+	ErrUnknownPlaceholder = errors.New("unknown placeholder")
+	ErrInvalidPlaceholder = errors.New("invalid placeholder")
+	ErrInvalidQuery       = errors.New("invalid query")
+
+	// conversion and helpers errors
+	ErrUnsupportedSignOp = errors.New("unsupported sign operator")
+	ErrUnsupportedJoinOp = errors.New("unsupported join operator")
+	ErrUnsupportedItem   = errors.New("unsupported expression group item")
+	ErrInvalidOperand    = errors.New("invalid operand")
+	ErrInvalidSExpr      = errors.New("invalid s-expression")
+	ErrInvalidProto      = errors.New("invalid proto message")
+	ErrInvalidPlainGroup = errors.New("invalid plain group")
+)
+
+// kindError represents an error of a specific failure kind
+// (aka. one of the sentinel errors above).
+type kindError struct {
+	kind   error
+	format string
+	args   []interface{}
+	err    error
+	fix    *FixIt
+}
+
+// errorf formats an error of the specified failure kind
+// (the format could also wrap another error with %w).
+func errorf(kind error, format string, args ...interface{}) error {
+	return &kindError{kind: kind, format: format, args: args, err: fmt.Errorf(format, args...)}
+}
+
+// errorMessage implements the messageError interface.
+func (e *kindError) errorMessage() ErrorMessage {
+	return ErrorMessage{Code: errorCode(e.kind), Template: e.format, Args: e.args}
+}
+
+// Error implements the error interface.
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+// Is checks if target is the error failure kind.
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Unwrap returns the formatted error (aka. the %w wrapped error if any).
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+// OperatorError represents an invalid sign or join operator error.
+type OperatorError struct {
+	// Type is the invalid operator token type (TokenSign or TokenJoin).
+	Type TokenType
+
+	// Literal is the invalid operator literal.
+	Literal string
+
+	// Suggestion is the closest valid operator alternative
+	// (empty if there is no close enough alternative).
+	Suggestion string
+
+	// fix is the suggested operator replacement (see ErrorFixIt)
+	fix *FixIt
+}
+
+// Error implements the error interface.
+func (e *OperatorError) Error() string {
+	msg := fmt.Sprintf("invalid %s operator %q", e.Type, e.Literal)
+
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", e.Suggestion)
+	}
+
+	return msg
+}
+
+// Is checks if target is ErrInvalidSignOp or ErrInvalidJoinOp
+// (depending on the operator type).
+func (e *OperatorError) Is(target error) bool {
+	if e.Type == TokenJoin {
+		return target == ErrInvalidJoinOp
+	}
+
+	return target == ErrInvalidSignOp
+}
+
+// withSuggestionFixIt attaches the Suggestion replacement FixIt
+// of the operator that starts at the start input offset (if any).
+func (e *OperatorError) withSuggestionFixIt(start int) *OperatorError {
+	if e.Suggestion != "" {
+		e.fix = &FixIt{Span: Span{Start: start, End: start + len(e.Literal)}, Text: e.Suggestion}
+	}
+
+	return e
+}
+
+// errorMessage implements the messageError interface.
+func (e *OperatorError) errorMessage() ErrorMessage {
+	msg := ErrorMessage{
+		Code:     errorCode(e),
+		Template: "invalid %s operator %q",
+		Args:     []interface{}{e.Type, e.Literal},
+	}
+
+	if e.Suggestion != "" {
+		msg.Template += " (did you mean %q?)"
+		msg.Args = append(msg.Args, e.Suggestion)
+	}
+
+	return msg
+}
+
+// newOperatorError creates a new OperatorError for the specified
+// invalid operator literal with the closest valid suggestion.
+func newOperatorError(tokenType TokenType, literal string) *OperatorError {
+	var candidates []string
+	if tokenType == TokenJoin {
+		candidates = []string{string(JoinAnd), string(JoinOr)}
+	} else {
+		candidates = signOperatorLiterals()
+	}
+
+	return &OperatorError{
+		Type:       tokenType,
+		Literal:    literal,
+		Suggestion: suggestOperator(literal, candidates),
+	}
+}
+
+// commonOperatorTypos holds the suggestions for the most common
+// operator typos (usually coming from other languages syntax).
+var commonOperatorTypos = map[string]string{
+	"===": string(SignEq),
+	"!==": string(SignNeq),
+	"=>":  string(SignGte),
+	"=<":  string(SignLte),
+	"=!":  string(SignNeq),
+	"=~":  string(SignLike),
+	"~=":  string(SignLike),
+	"!":   string(SignNeq),
+	"&":   string(JoinAnd),
+	"|":   string(JoinOr),
+}
+
+// suggestOperator returns the closest operator alternative
+// of literal from the specified candidates.
+//
+// Returns an empty string if there is no close enough candidate.
+func suggestOperator(literal string, candidates []string) string {
+	if suggestion, ok := commonOperatorTypos[literal]; ok {
+		for _, c := range candidates {
+			if c == suggestion {
+				return suggestion
+			}
+		}
+	}
+
+	var suggestion string
+	minDistance := 2 // max allowed distance + 1
+
+	for _, c := range candidates {
+		if d := editDistance(literal, c); d < minDistance {
+			minDistance = d
+			suggestion = c
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// minInt returns the smallest of the provided integers.
+func minInt(first int, rest ...int) int {
+	result := first
+
+	for _, v := range rest {
+		if v < result {
+			result = v
+		}
+	}
+
+	return result
+}
+
+// ExprError represents a parsed expression error
+// with its input position (see OnExpr).
+type ExprError struct {
+	// Span is the expression input byte offsets range.
+	Span Span
+
+	// Err is the original error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("%v (at %d:%d)", e.Err, e.Span.Start, e.Span.End)
+}
+
+// Unwrap returns the original error.
+func (e *ExprError) Unwrap() error {
+	return e.Err
+}
+
+// errorMessage implements the messageError interface.
+func (e *ExprError) errorMessage() ErrorMessage {
+	return ErrorMessage{Code: ErrorCode(e.Err), Template: "%v (at %d:%d)", Args: []interface{}{e.Err, e.Span.Start, e.Span.End}}
+}
+
+// ParseError represents a Parse error with the input position
+// of the token where the parsing failed (see ErrorSnippet).
+type ParseError struct {
+	// Span is the failed token input byte offsets range.
+	Span Span
+
+	// Err is the original error.
+	Err error
+}
+
+// Error implements the error interface.
+//
+// The original error message is returned as it is
+// (use Span or ErrorSnippet for the error position).
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// LimitError represents a configurable limit exceeded error
+// (eg. MaxInputLength or MaxDepth), so that the API layers could
+// report it differently from the invalid filter errors.
+type LimitError struct {
+	// Kind is the exceeded limit sentinel error (ErrMaxInputLength,
+	// ErrMaxTextLength, ErrMaxDepth or ErrMaxComplexity).
+	Kind error
+
+	// Max is the configured limit.
+	Max int
+
+	// Actual is the observed value when the limit was exceeded.
+	//
+	// Note that the input is not processed after the limit is exceeded,
+	// so Actual could be smaller than the input total (eg. Max + 1 bytes).
+	Actual int
+
+	// format is the error message format with Max as argument
+	format string
+}
+
+// newLimitError creates a new LimitError with the specified message format.
+func newLimitError(kind error, format string, max int, actual int) *LimitError {
+	return &LimitError{Kind: kind, Max: max, Actual: actual, format: format}
+}
+
+// Error implements the error interface.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf(e.format, e.Max)
+}
+
+// Is checks if target is the exceeded limit Kind.
+func (e *LimitError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// errorMessage implements the messageError interface.
+func (e *LimitError) errorMessage() ErrorMessage {
+	return ErrorMessage{Code: errorCode(e.Kind), Template: e.format, Args: []interface{}{e.Max}}
+}
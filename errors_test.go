@@ -0,0 +1,242 @@
+package fexpr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOperatorErrorSuggestion(t *testing.T) {
+	scenarios := []struct {
+		input              string
+		expectedType       TokenType
+		expectedSuggestion string
+	}{
+		{`a => 1`, TokenSign, ">="},
+		{`a =< 1`, TokenSign, "<="},
+		{`a === 1`, TokenSign, "="},
+		{`a !== 1`, TokenSign, "!="},
+		{`a =! 1`, TokenSign, "!="},
+		{`a =~ 1`, TokenSign, "~"},
+		{`a ! 1`, TokenSign, "!="},
+		{`a ?!! 1`, TokenSign, "?!="},
+		{`a ?=~ 1`, TokenSign, "?="},
+		{`a =?!~<> 1`, TokenSign, ""},
+		{`a = 1 & b = 2`, TokenJoin, "&&"},
+		{`a = 1 | b = 2`, TokenJoin, "||"},
+		{`a = 1 &&& b = 2`, TokenJoin, "&&"},
+		{`a = 1 &|&| b = 2`, TokenJoin, ""},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			_, err := Parse(s.input)
+
+			var opErr *OperatorError
+			if !errors.As(err, &opErr) {
+				t.Fatalf("Expected OperatorError, got %v", err)
+			}
+
+			if opErr.Type != s.expectedType {
+				t.Fatalf("Expected type %q, got %q", s.expectedType, opErr.Type)
+			}
+
+			if opErr.Suggestion != s.expectedSuggestion {
+				t.Fatalf("Expected suggestion %q, got %q", s.expectedSuggestion, opErr.Suggestion)
+			}
+
+			hasHint := strings.Contains(err.Error(), "did you mean")
+			if hasHint != (s.expectedSuggestion != "") {
+				t.Fatalf("Unexpected error message %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	scenarios := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"abc", "abc", 0},
+		{"=>", ">=", 2},
+		{"?!!", "?!=", 1},
+		{"ж=", "=", 1},
+	}
+
+	for _, s := range scenarios {
+		if d := editDistance(s.a, s.b); d != s.expected {
+			t.Errorf("Expected distance %d between %q and %q, got %d", s.expected, s.a, s.b, d)
+		}
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		opts     []ParseOption
+		expected error
+	}{
+		{`a = 1.`, nil, ErrInvalidNumber},
+		{`a = "b`, nil, ErrUnterminatedText},
+		{`a = "b\d"`, []ParseOption{ScannerOptions(TextUnescape(UnescapeStrict))}, ErrInvalidEscape},
+		{`a = (b`, nil, ErrUnterminatedGroup},
+		{`a = b@c`, nil, ErrInvalidIdentifier},
+		{`a = b[1`, nil, ErrInvalidIdentifier},
+		{`a = ^`, nil, ErrUnexpectedCharacter},
+		{`a => 1`, nil, ErrInvalidSignOp},
+		{`a = 1 & b = 2`, nil, ErrInvalidJoinOp},
+		{`a = "abc"`, []ParseOption{ScannerOptions(MaxTextLength(2))}, ErrMaxTextLength},
+		{`a = "abc"`, []ParseOption{ScannerOptions(MaxInputLength(3))}, ErrMaxInputLength},
+		{`a = 1 b = 2`, nil, ErrUnexpectedToken},
+		{`a in ()`, nil, ErrInvalidList},
+		{`a == 1`, []ParseOption{Strict()}, ErrStrictMode},
+		{`((a = 1))`, []ParseOption{MaxDepth(1)}, ErrMaxDepth},
+		{`a = 1 && b = 2`, []ParseOption{MaxComplexity(1)}, ErrMaxComplexity},
+		{`#m`, []ParseOption{Macro("m", "#m")}, ErrRecursiveMacro},
+		{`#m`, []ParseOption{Macro("m", "a =")}, ErrInvalidMacro},
+		{`#m`, []ParseOption{Macro("m", "a =")}, ErrIncomplete},
+		{`a = @b`, []ParseOption{Placeholders(PlaceholderMap{})}, ErrInvalidPlaceholder},
+		{`a = @b`, []ParseOption{Placeholders(PlaceholderMap{})}, ErrUnknownPlaceholder},
+		{``, nil, ErrEmpty},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			_, err := Parse(s.input, s.opts...)
+
+			if !errors.Is(err, s.expected) {
+				t.Fatalf("Expected %q error, got %v", s.expected, err)
+			}
+		})
+	}
+}
+
+func TestSentinelErrorsMessage(t *testing.T) {
+	_, err := Parse(`a = 1.`)
+
+	// the descriptive error message should be preserved
+	if expected := `invalid number "1."`; err == nil || err.Error() != expected {
+		t.Fatalf("Expected error %q, got %v", expected, err)
+	}
+
+	if errors.Is(err, ErrUnterminatedText) {
+		t.Fatal("Expected the error to not be ErrUnterminatedText")
+	}
+}
+
+func TestSentinelErrorsConverters(t *testing.T) {
+	parse := func(input string) []ExprGroup {
+		result, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", input, err)
+		}
+		return result
+	}
+
+	scenarios := []struct {
+		name     string
+		convert  func() error
+		expected error
+	}{
+		{"dynamo field operand", func() error {
+			_, err := ToDynamo(parse(`1 = 2`))
+			return err
+		}, ErrInvalidOperand},
+		{"prometheus or join", func() error {
+			_, err := ToPrometheusMatchers(parse(`a = 1 || b = 2`))
+			return err
+		}, ErrUnsupportedJoinOp},
+		{"not empty", func() error {
+			_, err := Not(nil)
+			return err
+		}, ErrEmpty},
+		{"sexpr unclosed", func() error {
+			_, err := ParseSExpr(`(= a`)
+			return err
+		}, ErrInvalidSExpr},
+		{"sexpr depth", func() error {
+			_, err := ParseSExpr(strings.Repeat("(", defaultMaxDepth+2))
+			return err
+		}, ErrMaxDepth},
+		{"proto invalid", func() error {
+			_, err := UnmarshalProto([]byte{0xff})
+			return err
+		}, ErrInvalidProto},
+		{"plain empty group", func() error {
+			_, err := FromPlain([]PlainExprGroup{{}})
+			return err
+		}, ErrInvalidPlainGroup},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.name), func(t *testing.T) {
+			err := s.convert()
+
+			if !errors.Is(err, s.expected) {
+				t.Fatalf("Expected %q error, got %v", s.expected, err)
+			}
+
+			if code := ErrorCode(err); code == "" {
+				t.Fatalf("Expected non-empty error code for %v", err)
+			}
+		})
+	}
+}
+
+func TestLimitError(t *testing.T) {
+	scenarios := []struct {
+		input          string
+		opts           []ParseOption
+		expectedKind   error
+		expectedMax    int
+		expectedActual int
+	}{
+		{`a = "abcd"`, []ParseOption{ScannerOptions(MaxTextLength(2))}, ErrMaxTextLength, 2, 3},
+		{`a = "abcd"`, []ParseOption{ScannerOptions(MaxInputLength(3))}, ErrMaxInputLength, 3, 4},
+		{`a = "ą"`, []ParseOption{ScannerOptions(MaxInputLength(5))}, ErrMaxInputLength, 5, 7},
+		{`a <=> 1`, []ParseOption{ScannerOptions(RegisterSignOp("<=>", SignOpOptions{}), MaxInputLength(4))}, ErrMaxInputLength, 4, 5},
+		{`((a = 1))`, []ParseOption{MaxDepth(1)}, ErrMaxDepth, 1, 2},
+		{`a = 1 && b in (1, 2, 3)`, []ParseOption{MaxComplexity(2)}, ErrMaxComplexity, 2, 4},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			_, err := Parse(s.input, s.opts...)
+
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("Expected LimitError, got %v", err)
+			}
+
+			if !errors.Is(err, s.expectedKind) || limitErr.Kind != s.expectedKind {
+				t.Fatalf("Expected %q kind, got %v", s.expectedKind, limitErr.Kind)
+			}
+
+			if limitErr.Max != s.expectedMax {
+				t.Fatalf("Expected max %d, got %d", s.expectedMax, limitErr.Max)
+			}
+
+			if limitErr.Actual != s.expectedActual {
+				t.Fatalf("Expected actual %d, got %d", s.expectedActual, limitErr.Actual)
+			}
+		})
+	}
+}
+
+func TestLimitErrorFields(t *testing.T) {
+	_, err := ParseFields(strings.Repeat("a(", defaultMaxDepth+1) + "b" + strings.Repeat(")", defaultMaxDepth+1))
+
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Kind != ErrMaxDepth {
+		t.Fatalf("Expected ErrMaxDepth LimitError, got %v", err)
+	}
+
+	if limitErr.Actual != defaultMaxDepth || limitErr.Max != defaultMaxDepth {
+		t.Fatalf("Expected %d actual and max, got %d and %d", defaultMaxDepth, limitErr.Actual, limitErr.Max)
+	}
+}
@@ -31,6 +31,22 @@ func ExampleScanner_Scan() {
 	// {number 123}
 }
 
+func ExampleScanner_Reset() {
+	s := fexpr.NewScanner(strings.NewReader("a"))
+
+	for _, filter := range []string{"id", "name"} {
+		s.Reset(strings.NewReader(filter))
+
+		t, _ := s.Scan()
+
+		fmt.Println(t)
+	}
+
+	// Output:
+	// {identifier id}
+	// {identifier name}
+}
+
 func ExampleParse() {
 	result, _ := fexpr.Parse("id > 123")
 
@@ -39,3 +55,141 @@ func ExampleParse() {
 	// Output:
 	// [{&& {{identifier id} > {number 123}}}]
 }
+
+func ExampleTokenize() {
+	tokens, _ := fexpr.Tokenize("id > 123 // test", fexpr.TokenWS)
+
+	fmt.Println(tokens)
+
+	// Output:
+	// [{identifier id} {sign >} {number 123} {comment test}]
+}
+
+func ExampleAnd() {
+	userFilter, _ := fexpr.Parse("a = 1 || b = 2")
+	tenantFilter, _ := fexpr.Parse("tenant = 123")
+
+	fmt.Println(fexpr.And(userFilter, tenantFilter))
+
+	// Output:
+	// [{&& [{&& {{identifier a} = {number 1}}} {|| {{identifier b} = {number 2}}}]} {&& [{&& {{identifier tenant} = {number 123}}}]}]
+}
+
+func ExampleNot() {
+	exprs, _ := fexpr.Parse("a = 1 || b > 2")
+
+	result, _ := fexpr.Not(exprs)
+
+	fmt.Println(result)
+
+	// Output:
+	// [{&& {{identifier a} != {number 1}}} {&& {{identifier b} <= {number 2}}}]
+}
+
+func ExampleNormalize() {
+	a, _ := fexpr.Parse("b = 2 && a = 1 && b = 2")
+	b, _ := fexpr.Parse("(a = 1)   &&   b = 2")
+
+	fmt.Println(fexpr.Stringify(fexpr.Normalize(a)))
+	fmt.Println(fexpr.Stringify(fexpr.Normalize(b)))
+
+	// Output:
+	// a = 1 && b = 2
+	// a = 1 && b = 2
+}
+
+func ExampleFormat() {
+	result, _ := fexpr.Format("id > 1 && (status = 'active' || total < 10) // test", fexpr.FormatOptions{})
+
+	fmt.Println(result)
+
+	// Output:
+	// id > 1 &&
+	// (
+	//     status = "active" ||
+	//     total  < 10
+	// ) // test
+}
+
+func ExampleSplitIdentifier() {
+	for _, s := range fexpr.SplitIdentifier("@request.auth.id") {
+		fmt.Println(s.Literal, s.Start, s.End)
+	}
+
+	// Output:
+	// @request 0 8
+	// auth 9 13
+	// id 14 16
+}
+
+func ExampleMacro() {
+	result, _ := fexpr.Parse(
+		"#mine && status = 'active'",
+		fexpr.Macro("mine", "owner = @request.auth.id"),
+	)
+
+	fmt.Println(result)
+
+	// Output:
+	// [{&& [{&& {{identifier owner} = {identifier @request.auth.id}}}]} {&& {{identifier status} = {text active}}}]
+}
+
+func ExamplePlaceholders() {
+	result, err := fexpr.Parse(
+		"created < @now && owner = @request.auth.id",
+		fexpr.Placeholders(fexpr.PlaceholderMap{
+			"@now":             {Type: fexpr.TokenText, Literal: "2022-01-01 00:00:00"},
+			"@request.auth.id": {Type: fexpr.TokenText, Literal: "abc"},
+		}),
+	)
+
+	fmt.Println(result, err)
+
+	_, err = fexpr.Parse("owner = @unknown", fexpr.Placeholders(fexpr.PlaceholderMap{}))
+
+	fmt.Println(err)
+
+	// Output:
+	// [{&& {{identifier created} < {text 2022-01-01 00:00:00}}} {&& {{identifier owner} = {text abc}}}] <nil>
+	// invalid placeholder "@unknown": unknown placeholder "@unknown"
+}
+
+func ExampleKeywords() {
+	result, _ := fexpr.Parse("status = 'active' and not (a > 1 or b = 2)", fexpr.Keywords())
+
+	fmt.Println(result)
+
+	// Output:
+	// [{&& {{identifier status} = {text active}}} {&& [{&& {{identifier a} <= {number 1}}} {&& {{identifier b} != {number 2}}}]}]
+}
+
+func ExampleSplitList() {
+	result, _ := fexpr.Parse(`status in ("draft", 'pending')`)
+
+	expr := result[0].Item.(fexpr.Expr)
+
+	items, _ := fexpr.SplitList(expr.Right.Literal)
+
+	fmt.Println(expr.Op, items)
+
+	// Output:
+	// in [{text draft} {text pending}]
+}
+
+func ExampleSkipWhitespace() {
+	s := fexpr.NewScanner(strings.NewReader("id > 123 // test"), fexpr.SkipWhitespace(), fexpr.SkipComments())
+
+	for {
+		t, err := s.Scan()
+		if t.Type == fexpr.TokenEOF || err != nil {
+			break
+		}
+
+		fmt.Println(t)
+	}
+
+	// Output:
+	// {identifier id}
+	// {sign >}
+	// {number 123}
+}
@@ -0,0 +1,69 @@
+package fexpr
+
+import "strings"
+
+// Features represents a set of opt-in syntax features (see EnableFeatures).
+//
+// New syntax that could change how the already stored filters are parsed
+// is never enabled by default, so that upgrading the package doesn't
+// silently change the meaning of the existing filters.
+type Features uint64
+
+// supported syntax features
+const (
+	// FeatureKeywords enables the `and`, `or` and `not` keyword operators
+	// (the same as the Keywords option).
+	FeatureKeywords Features = 1 << iota
+
+	// FeatureNullLiteral parses the case-insensitive `null` identifier
+	// operands as TokenNull tokens with "null" literal.
+	FeatureNullLiteral
+
+	// FeatureBoolLiterals parses the case-insensitive `true` and `false`
+	// identifier operands as TokenBool tokens with "true" or "false" literal.
+	FeatureBoolLiterals
+)
+
+// syntax feature versions
+const (
+	// FeaturesV1 is the original syntax (aka. the default parse behavior).
+	FeaturesV1 Features = 0
+
+	// FeaturesV2 is the original syntax with the typed null and bool literals.
+	FeaturesV2 = FeaturesV1 | FeatureNullLiteral | FeatureBoolLiterals
+)
+
+// Has checks if all of the specified features are enabled.
+func (f Features) Has(features Features) bool {
+	return f&features == features
+}
+
+// EnableFeatures enables the specified opt-in syntax features
+// (eg. `EnableFeatures(FeaturesV2)` or `EnableFeatures(FeatureNullLiteral)`).
+//
+// Multiple EnableFeatures options are combined.
+func EnableFeatures(features Features) ParseOption {
+	return func(p *parser) {
+		p.features |= features
+	}
+}
+
+// literalOperand returns the typed null or bool literal token of
+// an identifier operand t if the related feature is enabled
+// (otherwise t is returned as it is).
+func (p *parser) literalOperand(t Token) Token {
+	switch {
+	case p.features.Has(FeatureNullLiteral) && isWordToken(t, "null"):
+		return Token{Type: TokenNull, Literal: "null"}
+	case p.features.Has(FeatureBoolLiterals) && (isWordToken(t, "true") || isWordToken(t, "false")):
+		return Token{Type: TokenBool, Literal: strings.ToLower(t.Literal)}
+	}
+
+	return t
+}
+
+// isNullToken checks if t is a null literal, aka. a TokenNull token
+// or a case-insensitive `null` identifier (see FeatureNullLiteral).
+func isNullToken(t Token) bool {
+	return t.Type == TokenNull || isWordToken(t, "null")
+}
@@ -0,0 +1,74 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEnableFeatures(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		features Features
+		expected string
+	}{
+		{`a = null && b != TRUE`, FeaturesV1, `[{&& {{identifier a} = {identifier null}}} {&& {{identifier b} != {identifier TRUE}}}]`},
+		{`a = null && b != TRUE`, FeaturesV2, `[{&& {{identifier a} = {null null}}} {&& {{identifier b} != {bool true}}}]`},
+		{`NULL = false`, FeatureNullLiteral, `[{&& {{null null} = {identifier false}}}]`},
+		{`NULL = false`, FeatureBoolLiterals, `[{&& {{identifier NULL} = {bool false}}}]`},
+		{`a = nullable || (b = true)`, FeaturesV2, `[{&& {{identifier a} = {identifier nullable}}} {|| [{&& {{identifier b} = {bool true}}}]}]`},
+		{`a = "null" && b in (null, 1)`, FeaturesV2, `[{&& {{identifier a} = {text null}}} {&& {{identifier b} in {list null, 1}}}]`},
+		{`not a = true`, FeatureKeywords | FeatureBoolLiterals, `[{&& {{identifier a} != {bool true}}}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := Parse(s.input, EnableFeatures(s.features))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, vPrint)
+			}
+		})
+	}
+}
+
+func TestFeaturesHas(t *testing.T) {
+	scenarios := []struct {
+		features Features
+		check    Features
+		expected bool
+	}{
+		{FeaturesV1, FeatureNullLiteral, false},
+		{FeaturesV2, FeatureNullLiteral, true},
+		{FeaturesV2, FeatureNullLiteral | FeatureBoolLiterals, true},
+		{FeaturesV2, FeatureNullLiteral | FeatureKeywords, false},
+		{FeaturesV2 | FeatureKeywords, FeatureKeywords, true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%d_%d", i, s.features, s.check), func(t *testing.T) {
+			if has := s.features.Has(s.check); has != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, has)
+			}
+		})
+	}
+}
+
+func TestFeaturesLiteralValues(t *testing.T) {
+	v, err := Parse(`a = null && b = FALSE`, EnableFeatures(FeaturesV2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	where, err := ToGraphQLWhere(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `map[_and:[map[a:map[_is_null:true]] map[b:map[_eq:false]]]]`
+	if wherePrint := fmt.Sprintf("%v", where); wherePrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, wherePrint)
+	}
+}
@@ -0,0 +1,97 @@
+package fexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field represents a single parsed field selection.
+type Field struct {
+	// Name is the selected field identifier (eg. "profile.avatar")
+	// or "*" for selecting all fields.
+	Name string
+
+	// Children holds the nested field selection (eg. `items(id, name)`)
+	// and it is nil if the field doesn't have a nested selection.
+	Children []Field
+}
+
+// ParseFields parses a comma separated field selection expression
+// (eg. `id, name, profile.avatar, items(*)`) into a tree of fields.
+//
+// The field names are scanned and validated with the same Scanner
+// and rules as the filter identifiers.
+func ParseFields(text string, opts ...ScannerOption) ([]Field, error) {
+	return parseFields(text, 0, opts)
+}
+
+// parseFields parses the field selection text at the specified nesting depth.
+func parseFields(text string, depth int, opts []ScannerOption) ([]Field, error) {
+	if depth >= defaultMaxDepth {
+		return nil, newLimitError(ErrMaxDepth, "the maximum allowed nesting depth of %d is exceeded", defaultMaxDepth, depth)
+	}
+
+	result := []Field{}
+
+	scanner := NewScanner(strings.NewReader(text), opts...)
+
+	expectField := true
+
+	for {
+		t, err := scanner.Scan()
+
+		// the scanner doesn't have dedicated comma and wildcard tokens
+		isComma := t.Type == TokenUnexpected && t.Literal == ","
+		isWildcard := t.Type == TokenSign && t.Literal == "*"
+
+		if err != nil && !isComma && !isWildcard {
+			return nil, err
+		}
+
+		if t.Type == TokenWS || t.Type == TokenComment {
+			continue
+		}
+
+		if t.Type == TokenEOF {
+			break
+		}
+
+		if expectField {
+			if t.Type != TokenIdentifier && !isWildcard {
+				return nil, errorf(ErrUnexpectedToken, "expected field identifier, got %q (%s)", t.Literal, t.Type)
+			}
+
+			result = append(result, Field{Name: t.Literal})
+			expectField = false
+			continue
+		}
+
+		if isComma {
+			expectField = true
+			continue
+		}
+
+		last := &result[len(result)-1]
+
+		if t.Type != TokenGroup || last.Children != nil || last.Name == "*" {
+			return nil, errorf(ErrUnexpectedToken, "expected fields separator \",\", got %q (%s)", t.Literal, t.Type)
+		}
+
+		children, err := parseFields(t.Literal, depth+1, opts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q fields: %w", last.Name, err)
+		}
+
+		last.Children = children
+	}
+
+	if len(result) == 0 {
+		return nil, errorf(ErrInvalidList, "empty fields list")
+	}
+
+	if expectField {
+		return nil, errorf(ErrInvalidList, "missing field after the trailing \",\"")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package fexpr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{``, true, `[]`},
+		{`  `, true, `[]`},
+		{`a,`, true, `[]`},
+		{`,a`, true, `[]`},
+		{`a,,b`, true, `[]`},
+		{`a b`, true, `[]`},
+		{`a = 1`, true, `[]`},
+		{`"a"`, true, `[]`},
+		{`1`, true, `[]`},
+		{`a.`, true, `[]`},
+		{`a()`, true, `[]`},
+		{`a(b)(c)`, true, `[]`},
+		{`*(a)`, true, `[]`},
+		{`a(b,`, true, `[]`},
+		{`a`, false, `[{a []}]`},
+		{`*`, false, `[{* []}]`},
+		{`id, name, profile.avatar, items(*)`, false, `[{id []} {name []} {profile.avatar []} {items [{* []}]}]`},
+		{"a ( b , c(d) ) // test\n, e", false, `[{a [{b []} {c [{d []}]}]} {e []}]`},
+		{`items.*, data["key"]`, false, `[{items.* []} {data["key"] []}]`},
+		{strings.Repeat("a(", defaultMaxDepth) + "a" + strings.Repeat(")", defaultMaxDepth), true, `[]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := ParseFields(s.input)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestParseFieldsScannerOptions(t *testing.T) {
+	v, err := ParseFields("a, b # test", CommentMarkers("#"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vPrint := fmt.Sprintf("%v", v); vPrint != `[{a []} {b []}]` {
+		t.Fatalf("Unexpected result %s", vPrint)
+	}
+}
@@ -0,0 +1,62 @@
+package fexpr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// maskedLiteral is the literal of the masked Fingerprint operands.
+const maskedLiteral = "?"
+
+// Fingerprint returns a stable hex encoded SHA-256 hash of the
+// provided parsed filter, suitable for caching and grouping filters.
+//
+// The hash is calculated from the canonical form of the filter, so the
+// Equivalent filters have the same fingerprint.
+//
+// If maskLiterals is set, the number, text and list operands are
+// replaced with a placeholder before hashing, aka. filters that differ
+// only by their values (eg. `age > 18` and `age > 21`) have the same fingerprint.
+func Fingerprint(exprs []ExprGroup, maskLiterals bool) string {
+	canonical := orientExprs(exprs)
+
+	if maskLiterals {
+		canonical = maskExprs(canonical)
+	}
+
+	hash := sha256.Sum256([]byte(Stringify(Normalize(canonical))))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// maskExprs returns a copy of the provided groups with
+// their literal operands replaced with maskedLiteral.
+func maskExprs(exprs []ExprGroup) []ExprGroup {
+	result := make([]ExprGroup, len(exprs))
+
+	for i, g := range exprs {
+		result[i] = g
+
+		switch v := g.Item.(type) {
+		case Expr:
+			v.Left = maskToken(v.Left)
+			v.Right = maskToken(v.Right)
+			result[i].Item = v
+		case []ExprGroup:
+			result[i].Item = maskExprs(v)
+		case UnaryExpr:
+			result[i].Item, _ = mapUnaryOperand(v, maskExprs)
+		}
+	}
+
+	return result
+}
+
+// maskToken replaces the literal of a non-identifier operand with maskedLiteral.
+func maskToken(t Token) Token {
+	if t.Type == TokenIdentifier {
+		return t
+	}
+
+	return Token{Type: t.Type, Literal: maskedLiteral}
+}
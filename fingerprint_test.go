@@ -0,0 +1,65 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	scenarios := []struct {
+		a             string
+		b             string
+		maskLiterals  bool
+		expectedEqual bool
+	}{
+		{`a = 1`, `a = 1`, false, true},
+		{`a = 1 && b = 2`, `(b = 2) && 1 = a`, false, true},
+		{`a = 1`, `a = 2`, false, false},
+		{`a = 1`, `a = "1"`, false, false},
+		{`a = 1`, `a = 2`, true, true},
+		{`a = 1 && b in (1, 2)`, `b in ("x") && 5 = a`, true, true},
+		{`a = 1`, `a = "1"`, true, false},
+		{`a = 1`, `b = 1`, true, false},
+		{`a = 1`, `a > 1`, true, false},
+		{`a = 1 || b = 1`, `a = 1 && b = 1`, true, false},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s_%s", i, s.a, s.b), func(t *testing.T) {
+			a, err := Parse(s.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := Parse(s.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fa := Fingerprint(a, s.maskLiterals)
+			fb := Fingerprint(b, s.maskLiterals)
+
+			if len(fa) != 64 {
+				t.Fatalf("Expected 64 characters hex hash, got %q", fa)
+			}
+
+			if (fa == fb) != s.expectedEqual {
+				t.Fatalf("Expected equal %v, got %q and %q", s.expectedEqual, fa, fb)
+			}
+		})
+	}
+}
+
+func TestFingerprintStable(t *testing.T) {
+	exprs, err := Parse(`a = 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sha256("a = 1")
+	expected := "b5bc1ffd90912fb18bef6e7d80909192c7a6492896320156d67fbaf104c6544a"
+
+	if v := Fingerprint(exprs, false); v != expected {
+		t.Fatalf("Expected %s, got %s", expected, v)
+	}
+}
@@ -0,0 +1,117 @@
+package fexpr
+
+import "strings"
+
+// firestoreOperators maps the sign operators to their Firestore where operators.
+var firestoreOperators = map[SignOp]string{
+	SignEq:    "==",
+	SignNeq:   "!=",
+	SignLt:    "<",
+	SignLte:   "<=",
+	SignGt:    ">",
+	SignGte:   ">=",
+	SignIn:    "in",
+	SignNotIn: "not-in",
+	SignAnyEq: "array-contains",
+}
+
+// FirestoreConstraint represents a single Firestore query where constraint.
+type FirestoreConstraint struct {
+	// Field is the dot separated document field path.
+	Field string
+
+	// Op is the Firestore where operator (eg. "==", "array-contains", "not-in").
+	Op string
+
+	// Value is the constraint value (int64, float64, string, bool, nil or []interface{}).
+	Value interface{}
+}
+
+// FirestoreUnsupported represents a filter condition
+// that cannot be expressed as Firestore constraint.
+type FirestoreUnsupported struct {
+	// Item is the unsupported condition (Expr or []ExprGroup).
+	Item interface{}
+
+	// Err describes why the condition is not supported.
+	Err error
+}
+
+// FirestoreQuery represents the Firestore constraints of a filter.
+type FirestoreQuery struct {
+	// Constraints holds the `&&` joined conditions that
+	// could be applied as Firestore where constraints.
+	Constraints []FirestoreConstraint
+
+	// Unsupported holds the `&&` joined conditions that cannot be
+	// expressed as Firestore constraints and have to be applied separately.
+	Unsupported []FirestoreUnsupported
+}
+
+// ToFirestore maps the provided parsed filter onto Firestore query
+// where constraints (aka. field/operator/value triples).
+//
+// The filter is first reduced with Optimize so that for example
+// `a = 1 || a = 2` is mapped to a single `in` constraint.
+//
+// Only `&&` joined conditions could be mapped, so if the reduced filter
+// still has top-level `||` operands it is reported as a single unsupported
+// condition. The `?=` operator is mapped to "array-contains" and the
+// like, array/all and other array/any operators are not supported.
+func ToFirestore(exprs []ExprGroup) FirestoreQuery {
+	result := FirestoreQuery{
+		Constraints: []FirestoreConstraint{},
+		Unsupported: []FirestoreUnsupported{},
+	}
+
+	optimized := Optimize(exprs)
+
+	if len(splitOr(optimized)) > 1 {
+		result.Unsupported = append(result.Unsupported, FirestoreUnsupported{
+			Item: optimized,
+			Err:  errorf(ErrUnsupportedJoinOp, "the top-level || conditions are not supported"),
+		})
+		return result
+	}
+
+	for _, g := range optimized {
+		expr, ok := g.Item.(Expr)
+		if !ok {
+			result.Unsupported = append(result.Unsupported, FirestoreUnsupported{
+				Item: g.Item,
+				Err:  errorf(ErrUnsupportedJoinOp, "the nested || conditions are not supported"),
+			})
+			continue
+		}
+
+		constraint, err := firestoreConstraint(expr)
+		if err != nil {
+			result.Unsupported = append(result.Unsupported, FirestoreUnsupported{Item: expr, Err: err})
+			continue
+		}
+
+		result.Constraints = append(result.Constraints, constraint)
+	}
+
+	return result
+}
+
+// firestoreConstraint converts a single expression into a Firestore constraint.
+func firestoreConstraint(expr Expr) (FirestoreConstraint, error) {
+	path, err := fieldPath(expr.Left)
+	if err != nil {
+		return FirestoreConstraint{}, err
+	}
+
+	op, ok := firestoreOperators[expr.Op]
+	if !ok {
+		return FirestoreConstraint{}, unsupportedSignOpError(expr.Op, "Firestore")
+	}
+
+	value, ok := literalValue(expr.Right)
+	if !ok {
+		return FirestoreConstraint{}, errorf(ErrInvalidOperand, "expected literal right operand, got %q (%s)", expr.Right.Literal, expr.Right.Type)
+	}
+
+	return FirestoreConstraint{Field: strings.Join(path, "."), Op: op, Value: value}, nil
+}
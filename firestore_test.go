@@ -0,0 +1,53 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToFirestore(t *testing.T) {
+	scenarios := []struct {
+		input               string
+		expectedConstraints string
+		expectedUnsupported string
+	}{
+		{`a = 1`, `[{a == 1}]`, `[]`},
+		{
+			`a.b != "x" && c >= 1.5 && d < 2 && e ?= "tag" && f not in (1, 2) && g = null`,
+			`[{a.b != x} {c >= 1.5} {d < 2} {e array-contains tag} {f not-in [1 2]} {g == <nil>}]`,
+			`[]`,
+		},
+		{`a = 1 || a = 2 || a = 3`, `[{a in [1 2 3]}]`, `[]`},
+		{`age > 5 && age > 10`, `[{age > 10}]`, `[]`},
+		{`a = 1 || b = 2`, `[]`, `[(a = 1 || b = 2): the top-level || conditions are not supported]`},
+		{
+			`a = 1 && (b = 2 || c = 3) && d ~ "x" && e = f && g[0] = 1`,
+			`[{a == 1}]`,
+			`[(b = 2 || c = 3): the nested || conditions are not supported d ~ "x": sign operator "~" is not supported by Firestore e = f: expected literal right operand, got "f" (identifier) g[0] = 1: unsupported field identifier "g[0]"]`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := ToFirestore(exprs)
+
+			if v := fmt.Sprintf("%v", result.Constraints); v != s.expectedConstraints {
+				t.Fatalf("Expected constraints %s, got %s", s.expectedConstraints, v)
+			}
+
+			unsupported := make([]string, len(result.Unsupported))
+			for i, u := range result.Unsupported {
+				unsupported[i] = itemString(u.Item) + ": " + u.Err.Error()
+			}
+
+			if v := fmt.Sprintf("%v", unsupported); v != s.expectedUnsupported {
+				t.Fatalf("Expected unsupported %s, got %s", s.expectedUnsupported, v)
+			}
+		})
+	}
+}
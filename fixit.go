@@ -0,0 +1,91 @@
+package fexpr
+
+import "errors"
+
+// FixIt represents a suggested input edit that fixes
+// a recoverable error (eg. an unterminated quoted text).
+type FixIt struct {
+	// Span is the input byte offsets range that should be replaced
+	// with Text (Start == End for a plain insertion).
+	Span Span
+
+	// Text is the replacement text (empty for a removal).
+	Text string
+}
+
+// Apply returns the input with the suggested edit applied.
+func (f FixIt) Apply(input string) string {
+	start := clampOffset(f.Span.Start, input)
+	end := clampOffset(maxInt(f.Span.Start, f.Span.End), input)
+
+	return input[:start] + f.Text + input[end:]
+}
+
+// ErrorFixIt returns the suggested input edit of err (if any).
+//
+// Fix-its are currently suggested for:
+//   - unterminated quoted text - the missing closing quote
+//   - missing group closing brackets - the missing `)`
+//   - trailing `&&` or `||` - the join operator removal
+//   - invalid operators with close alternative - the suggested operator
+//     (see OperatorError.Suggestion)
+//
+// Note that fixing an error could reveal another one
+// (eg. a missing bracket after the missing closing quote).
+func ErrorFixIt(err error) (FixIt, bool) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if f, ok := e.(fixItError); ok && f.fixIt() != nil {
+			return *f.fixIt(), true
+		}
+	}
+
+	return FixIt{}, false
+}
+
+// fixItError is implemented by the errors that could have a FixIt.
+type fixItError interface {
+	fixIt() *FixIt
+}
+
+// fixIt implements the fixItError interface.
+func (e *kindError) fixIt() *FixIt {
+	return e.fix
+}
+
+// fixIt implements the fixItError interface.
+func (e *OperatorError) fixIt() *FixIt {
+	return e.fix
+}
+
+// withFixIt attaches fix to the package error err
+// (a nil fix removes the existing one).
+func withFixIt(err error, fix *FixIt) error {
+	switch v := err.(type) {
+	case *kindError:
+		v.fix = fix
+	case *OperatorError:
+		v.fix = fix
+	}
+
+	return err
+}
+
+// shiftFixIt moves the FixIt of a nested group scan error to the
+// parsed input offsets (the FixIts of the macros errors are removed
+// since they don't apply to the parsed input).
+func (p *parser) shiftFixIt(err error) error {
+	fixErr, ok := err.(fixItError)
+	if !ok || fixErr.fixIt() == nil {
+		return err
+	}
+
+	if len(p.expanding) > 0 {
+		return withFixIt(err, nil)
+	}
+
+	fix := *fixErr.fixIt()
+	fix.Span.Start += p.offset
+	fix.Span.End += p.offset
+
+	return withFixIt(err, &fix)
+}
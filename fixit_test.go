@@ -0,0 +1,67 @@
+package fexpr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorFixIt(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		opts     []ParseOption
+		expected string // the fixed input (empty for no FixIt)
+	}{
+		{`a = "b`, nil, `a = "b"`},
+		{`a = 'b c`, nil, `a = 'b c'`},
+		{`a = "b\`, nil, ``},
+		{`a = (b = 1 || (c = 2`, nil, `a = (b = 1 || (c = 2))`},
+		{`(a = 1 && (b = "c`, nil, `(a = 1 && (b = "c"`},
+		{`a = 1 &&`, nil, `a = 1`},
+		{`a = 1 || b = 2 ||  `, nil, `a = 1 || b = 2  `},
+		{`(a = 1 && b = 2 &&) && c = 3`, nil, `(a = 1 && b = 2) && c = 3`},
+		{`a = 1 and`, []ParseOption{Keywords()}, `a = 1`},
+		{`a = 1 && not`, []ParseOption{Keywords()}, ``},
+		{`a =`, nil, ``},
+		{`a => 1`, nil, `a >= 1`},
+		{`a = 1 && (b ! 2)`, nil, `a = 1 && (b != 2)`},
+		{`a = 1 & b = 2`, nil, `a = 1 && b = 2`},
+		{`a =?!~<> 1`, nil, ``},
+		{`#m`, []ParseOption{Macro("m", `a = "b`)}, ``},
+		{`#m`, []ParseOption{Macro("m", `a = 1 &&`)}, ``},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			_, err := Parse(s.input, s.opts...)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			fix, ok := ErrorFixIt(err)
+			if ok != (s.expected != "") {
+				t.Fatalf("Expected FixIt %v, got %v (%v)", s.expected != "", ok, fix)
+			}
+
+			if !ok {
+				return
+			}
+
+			if fixed := fix.Apply(s.input); fixed != s.expected {
+				t.Fatalf("Expected fixed input %q, got %q", s.expected, fixed)
+			}
+		})
+	}
+}
+
+func TestErrorFixItKeepsErrorKind(t *testing.T) {
+	_, err := Parse(`a = 1 &&`)
+
+	if !errors.Is(err, ErrIncomplete) {
+		t.Fatalf("Expected ErrIncomplete, got %v", err)
+	}
+
+	if err.Error() != ErrIncomplete.Error() {
+		t.Fatalf("Expected %q message, got %q", ErrIncomplete.Error(), err.Error())
+	}
+}
@@ -0,0 +1,224 @@
+package fexpr
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatOptions defines the Format output options.
+type FormatOptions struct {
+	// Indent is the string used for a single nested group indentation level
+	// (default to 4 spaces).
+	Indent string
+
+	// DisableAlignment disables the sign operators alignment
+	// of the expressions in the same group.
+	DisableAlignment bool
+}
+
+// formatEntry represents a single formatted line (or block of lines
+// in case of a nested group) of a filter expression.
+type formatEntry struct {
+	left    string
+	op      string
+	right   string
+	group   []*formatEntry
+	comment string // standalone comment line
+
+	isGroup   bool
+	isComment bool
+
+	// join operator placed after the entry
+	join string
+
+	// comments placed at the end of the entry line
+	trailing []string
+}
+
+// Format reformats the provided filter expression placing each
+// expression on its own line, indenting the nested groups and
+// aligning the expressions sign operators.
+//
+// Unlike Stringify, Format operates on the text tokens and
+// preserves the comments.
+//
+// An error is returned if input is not a valid filter expression.
+func Format(input string, opts FormatOptions) (string, error) {
+	if _, err := Parse(input); err != nil {
+		return "", err
+	}
+
+	if opts.Indent == "" {
+		opts.Indent = "    "
+	}
+
+	entries, err := formatEntries(input)
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{}
+	writeFormatEntries(&lines, entries, 0, opts)
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// formatEntries tokenizes text and groups its tokens into format entries.
+func formatEntries(text string) ([]*formatEntry, error) {
+	tokens, err := Tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*formatEntry{}
+
+	var pending *formatEntry // incomplete expression
+	var last *formatEntry    // the last completed entry
+	var lastHasNewline bool  // whether there is a new line after the last entry
+
+	for _, t := range tokens {
+		switch t.Type {
+		case TokenWS:
+			if strings.ContainsAny(t.Literal, "\n\r") {
+				lastHasNewline = true
+			}
+		case TokenComment:
+			comment := strings.TrimSpace("// " + t.Literal)
+
+			if pending != nil {
+				pending.trailing = append(pending.trailing, comment)
+			} else if last != nil && !lastHasNewline {
+				last.trailing = append(last.trailing, comment)
+			} else {
+				entries = append(entries, &formatEntry{isComment: true, comment: comment})
+			}
+
+			// the comment token consumes the new line
+			lastHasNewline = true
+		case TokenGroup:
+			// the values list of the in operators, aka. a in (1, 2)
+			if pending != nil && isListSignOp(SignOp(pending.op)) {
+				list, err := newParser(nil).parseList(t.Literal)
+				if err != nil {
+					return nil, err
+				}
+
+				var sb strings.Builder
+				writeToken(&sb, list)
+
+				pending.right = sb.String()
+				last = pending
+				pending = nil
+				entries = append(entries, last)
+				lastHasNewline = false
+				continue
+			}
+
+			group, err := formatEntries(t.Literal)
+			if err != nil {
+				return nil, err
+			}
+
+			last = &formatEntry{isGroup: true, group: group}
+			entries = append(entries, last)
+			lastHasNewline = false
+		case TokenJoin:
+			if last != nil {
+				last.join = t.Literal
+			}
+			lastHasNewline = false
+		case TokenSign:
+			if pending != nil {
+				pending.op = string(normalizeSignOp(t.Literal))
+			}
+		default: // operand
+			// the keyword operators, aka. in, not like, etc.
+			if pending != nil && pending.op == "" && isWordToken(t, "not") {
+				pending.op = "not"
+				continue
+			}
+			if op := keywordSignOp(t); pending != nil && (pending.op == "" || pending.op == "not") && op != "" {
+				pending.op = strings.TrimSpace(pending.op + " " + string(op))
+				continue
+			}
+
+			operand := t.Literal
+			if t.Type == TokenText {
+				operand = quoteText(t.Literal)
+			}
+
+			if pending == nil {
+				pending = &formatEntry{left: operand}
+				continue
+			}
+
+			pending.right = operand
+			last = pending
+			pending = nil
+			entries = append(entries, last)
+			lastHasNewline = false
+		}
+	}
+
+	return entries, nil
+}
+
+// writeFormatEntries appends the formatted entries lines to lines.
+func writeFormatEntries(lines *[]string, entries []*formatEntry, depth int, opts FormatOptions) {
+	indent := strings.Repeat(opts.Indent, depth)
+
+	var leftWidth int
+	if !opts.DisableAlignment {
+		for _, e := range entries {
+			if w := utf8.RuneCountInString(e.left); !e.isGroup && !e.isComment && w > leftWidth {
+				leftWidth = w
+			}
+		}
+	}
+
+	for _, e := range entries {
+		switch {
+		case e.isComment:
+			*lines = append(*lines, indent+e.comment)
+		case e.isGroup:
+			// inline single expression groups, aka. (a = 1)
+			if len(e.group) == 1 && !e.group[0].isGroup && !e.group[0].isComment && len(e.group[0].trailing) == 0 {
+				*lines = append(*lines, indent+"("+formatExpr(e.group[0], 0)+")"+formatLineSuffix(e))
+				continue
+			}
+
+			*lines = append(*lines, indent+"(")
+			writeFormatEntries(lines, e.group, depth+1, opts)
+			*lines = append(*lines, indent+")"+formatLineSuffix(e))
+		default:
+			*lines = append(*lines, indent+formatExpr(e, leftWidth)+formatLineSuffix(e))
+		}
+	}
+}
+
+// formatExpr returns the formatted expression entry with
+// its left operand padded to leftWidth.
+func formatExpr(e *formatEntry, leftWidth int) string {
+	left := e.left
+	if pad := leftWidth - utf8.RuneCountInString(left); pad > 0 {
+		left += strings.Repeat(" ", pad)
+	}
+
+	return left + " " + e.op + " " + e.right
+}
+
+// formatLineSuffix returns the entry's join operator
+// and trailing comments line suffix.
+func formatLineSuffix(e *formatEntry) string {
+	var suffix string
+
+	if e.join != "" {
+		suffix += " " + e.join
+	}
+
+	if len(e.trailing) > 0 {
+		suffix += " " + strings.Join(e.trailing, " ")
+	}
+
+	return suffix
+}
@@ -0,0 +1,93 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		opts          FormatOptions
+		expectedError bool
+		expected      string
+	}{
+		{``, FormatOptions{}, true, ``},
+		{`a >`, FormatOptions{}, true, ``},
+		{`a=1`, FormatOptions{}, false, `a = 1`},
+		{`a=1 && abc   !=   'te"st'||(c>2)`, FormatOptions{}, false, "" +
+			"a   = 1 &&\n" +
+			"abc != 'te\"st' ||\n" +
+			"(c > 2)",
+		},
+		{`a==1 && b<>2`, FormatOptions{}, false, "" +
+			"a = 1 &&\n" +
+			"b != 2",
+		},
+		{`a=1 && abc!=2`, FormatOptions{DisableAlignment: true}, false, "" +
+			"a = 1 &&\n" +
+			"abc != 2",
+		},
+		{`a=1 && (b=2 || (cc=3 && d=4)) || e=5`, FormatOptions{Indent: "\t"}, false, "" +
+			"a = 1 &&\n" +
+			"(\n" +
+			"\tb = 2 ||\n" +
+			"\t(\n" +
+			"\t\tcc = 3 &&\n" +
+			"\t\td  = 4\n" +
+			"\t)\n" +
+			") ||\n" +
+			"e = 5",
+		},
+		{`status not in ('a',"b") && id in (1)`, FormatOptions{}, false, "" +
+			"status not in (\"a\", \"b\") &&\n" +
+			"id     in (1)",
+		},
+		{"// leading\na = 1 && // after join\n// standalone\nb = // inner\n2 // trailing", FormatOptions{}, false, "" +
+			"// leading\n" +
+			"a = 1 && // after join\n" +
+			"// standalone\n" +
+			"b = 2 // inner // trailing",
+		},
+		{"(\n// test\na = 1) && b = 2", FormatOptions{}, false, "" +
+			"(\n" +
+			"    // test\n" +
+			"    a = 1\n" +
+			") &&\n" +
+			"b = 2",
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			result, err := Format(s.input, s.opts)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, result)
+			}
+
+			if err != nil {
+				return
+			}
+
+			// the formatted filter should be parsed into the same AST
+			original, _ := Parse(s.input)
+			formatted, err := Parse(result)
+			if err != nil {
+				t.Fatalf("Failed to parse the formatted filter: %v", err)
+			}
+
+			if a, b := fmt.Sprintf("%v", original), fmt.Sprintf("%v", formatted); a != b {
+				t.Fatalf("Expected the formatted filter AST to be %s, got %s", a, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package fexpr
+
+// graphQLOperators maps the sign operators to their GraphQL where-input
+// comparison operators.
+var graphQLOperators = map[SignOp]string{
+	SignEq:        "_eq",
+	SignNeq:       "_neq",
+	SignLt:        "_lt",
+	SignLte:       "_lte",
+	SignGt:        "_gt",
+	SignGte:       "_gte",
+	SignLike:      "_ilike",
+	SignNlike:     "_nilike",
+	SignIn:        "_in",
+	SignNotIn:     "_nin",
+	SignSQLLike:   "_like",
+	SignSQLNlike:  "_nlike",
+	SignSQLIlike:  "_ilike",
+	SignSQLNilike: "_nilike",
+}
+
+// ToGraphQLWhere converts the provided parsed filter into the nested
+// where-input object convention of the Hasura/Prisma-style GraphQL APIs,
+// for example `age > 18 && (role = "admin" || author.name ~ "john")` becomes:
+//
+//	{"_and": [
+//	    {"age": {"_gt": 18}},
+//	    {"_or": [
+//	        {"role": {"_eq": "admin"}},
+//	        {"author": {"name": {"_ilike": "%john%"}}}
+//	    ]}
+//	]}
+//
+// The left operands must be plain field identifiers (nested fields are
+// converted to nested objects) and the right operands must be literals.
+// The `~` and `!~` operators are converted to case-insensitive contains
+// patterns and the `= null` and `!= null` comparisons to `_is_null`.
+//
+// An error is returned for the unsupported array/any and array/all operators.
+func ToGraphQLWhere(exprs []ExprGroup) (map[string]interface{}, error) {
+	if len(exprs) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	disjuncts := []interface{}{}
+
+	for _, conjunction := range splitOr(exprs) {
+		items := make([]interface{}, 0, len(conjunction))
+
+		for _, g := range conjunction {
+			item, err := graphQLItem(g.Item)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+
+		if len(items) == 1 {
+			disjuncts = append(disjuncts, items[0])
+		} else {
+			disjuncts = append(disjuncts, map[string]interface{}{"_and": items})
+		}
+	}
+
+	if len(disjuncts) == 1 {
+		return disjuncts[0].(map[string]interface{}), nil
+	}
+
+	return map[string]interface{}{"_or": disjuncts}, nil
+}
+
+// graphQLItem converts a single ExprGroup.Item into a where-input object.
+func graphQLItem(item interface{}) (map[string]interface{}, error) {
+	switch v := item.(type) {
+	case Expr:
+		return graphQLExpr(v)
+	case []ExprGroup:
+		return ToGraphQLWhere(v)
+	default:
+		return nil, errorf(ErrUnsupportedItem, "unsupported expression group item %T", item)
+	}
+}
+
+// graphQLExpr converts a single expression into a where-input object.
+func graphQLExpr(expr Expr) (map[string]interface{}, error) {
+	path, err := fieldPath(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := literalValue(expr.Right)
+	if !ok {
+		return nil, errorf(ErrInvalidOperand, "expected literal right operand, got %q (%s)", expr.Right.Literal, expr.Right.Type)
+	}
+
+	operator, ok := graphQLOperators[expr.Op]
+	if !ok {
+		return nil, unsupportedSignOpError(expr.Op, "GraphQL")
+	}
+
+	switch {
+	case value == nil && (expr.Op == SignEq || expr.Op == SignNeq):
+		operator = "_is_null"
+		value = expr.Op == SignEq
+	case expr.Op == SignLike || expr.Op == SignNlike:
+		text, ok := value.(string)
+		if !ok {
+			return nil, errorf(ErrInvalidOperand, "expected text right operand for %q, got %q (%s)", expr.Op, expr.Right.Literal, expr.Right.Type)
+		}
+		value = containsPattern(text)
+	}
+
+	// wrap the comparison in the nested field objects
+	result := map[string]interface{}{operator: value}
+	for i := len(path) - 1; i >= 0; i-- {
+		result = map[string]interface{}{path[i]: result}
+	}
+
+	return result, nil
+}
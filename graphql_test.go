@@ -0,0 +1,76 @@
+package fexpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestToGraphQLWhere(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedJSON  string
+	}{
+		{`a ?= 1`, true, ``},
+		{`a = b`, true, ``},
+		{`1 = a`, true, ``},
+		{`@request.id = 1`, true, ``},
+		{`items[0] = 1`, true, ``},
+		{`a ~ 1`, true, ``},
+		{`a = 1`, false, `{"a":{"_eq":1}}`},
+		{`a.b_c != "x" && c > 1.5`, false, `{"_and":[{"a":{"b_c":{"_neq":"x"}}},{"c":{"_gt":1.5}}]}`},
+		{`a = null || b != NULL || c = true`, false, `{"_or":[{"a":{"_is_null":true}},{"b":{"_is_null":false}},{"c":{"_eq":true}}]}`},
+		{`a ~ "x" && a !~ "y%"`, false, `{"_and":[{"a":{"_ilike":"%x%"}},{"a":{"_nilike":"y%"}}]}`},
+		{`a in (1, "b") && a not in (2) && a like "x" && a not ilike "y"`, false, `{"_and":[{"a":{"_in":[1,"b"]}},{"a":{"_nin":[2]}},{"a":{"_like":"x"}},{"a":{"_nilike":"y"}}]}`},
+		{
+			`age > 18 && (role = "admin" || author.name ~ "john")`,
+			false,
+			`{"_and":[{"age":{"_gt":18}},{"_or":[{"role":{"_eq":"admin"}},{"author":{"name":{"_ilike":"%john%"}}}]}]}`,
+		},
+		{`a < 1 && b <= 2 || c >= 3`, false, `{"_or":[{"_and":[{"a":{"_lt":1}},{"b":{"_lte":2}}]},{"c":{"_gte":3}}]}`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := ToGraphQLWhere(exprs)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if s.expectedError {
+				return
+			}
+
+			raw, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(raw) != s.expectedJSON {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expectedJSON, raw)
+			}
+		})
+	}
+}
+
+func TestToGraphQLWhereEmpty(t *testing.T) {
+	result, err := ToGraphQLWhere(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 0 {
+		t.Fatalf("Expected empty object, got %v", result)
+	}
+}
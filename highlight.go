@@ -0,0 +1,149 @@
+package fexpr
+
+import "strings"
+
+// HighlightCategory represents the semantic category of a highlighted token.
+type HighlightCategory string
+
+// Highlight categories.
+const (
+	HighlightField       HighlightCategory = "field"
+	HighlightOperator    HighlightCategory = "operator"
+	HighlightString      HighlightCategory = "string"
+	HighlightNumber      HighlightCategory = "number"
+	HighlightComment     HighlightCategory = "comment"
+	HighlightPunctuation HighlightCategory = "punctuation"
+	HighlightError       HighlightCategory = "error"
+)
+
+// HighlightSpan represents a single categorized input token byte offsets range.
+type HighlightSpan struct {
+	Span     Span
+	Category HighlightCategory
+}
+
+// Highlight splits the input into semantically categorized token spans,
+// intended to be used for syntax highlighting (eg. in an editor mode).
+//
+// Highlight is tolerant of partial and invalid input - the invalid
+// tokens are reported with HighlightError and the scanning continues
+// with the rest of the input. The group brackets and the list commas
+// are reported as HighlightPunctuation and the whitespaces are skipped.
+//
+// The keyword operators (eg. `in`, `not like`) are categorized based
+// on their position in the expression, so `in = 1` is still highlighted
+// as a field.
+func Highlight(input string, opts ...ScannerOption) []HighlightSpan {
+	result := []HighlightSpan{}
+
+	highlightTokens(&result, input, 0, 0, false, opts)
+
+	return result
+}
+
+// highlightTokens appends the highlight spans of input to result,
+// shifting each span with offset.
+//
+// depth is the input group nesting depth and isList indicates
+// that input is an `in` operator values list.
+func highlightTokens(result *[]HighlightSpan, input string, offset int, depth int, isList bool, opts []ScannerOption) {
+	scanner := NewScanner(strings.NewReader(input), opts...)
+	step := stepBeforeSign
+	var op SignOp
+
+	add := func(start int, end int, category HighlightCategory) {
+		*result = append(*result, HighlightSpan{
+			Span:     Span{Start: offset + start, End: offset + end},
+			Category: category,
+		})
+	}
+
+	// operand moves the state machine after a left or right operand
+	operand := func() {
+		if step == stepAfterSign {
+			step = StepJoin
+		} else {
+			step = stepSign
+		}
+	}
+
+	for {
+		t, err := scanner.Scan()
+		span := scanner.LastSpan()
+
+		if err != nil && t.Type != TokenGroup {
+			if isList && t.Literal == "," {
+				add(span.Start, span.End, HighlightPunctuation)
+				continue
+			}
+
+			// nothing was consumed and therefore no progress is possible
+			if span.End <= span.Start {
+				return
+			}
+
+			add(span.Start, span.End, HighlightError)
+			continue
+		}
+
+		switch t.Type {
+		case TokenEOF:
+			return
+		case TokenWS:
+			// skip
+		case TokenComment:
+			add(span.Start, span.End, HighlightComment)
+		case TokenGroup:
+			// too deeply nested to be parsed
+			if depth >= defaultMaxDepth {
+				add(span.Start, span.End, HighlightError)
+				step = StepJoin
+				continue
+			}
+
+			add(span.Start, span.Start+1, HighlightPunctuation)
+
+			highlightTokens(result, t.Literal, offset+span.Start+1, depth+1, step == stepAfterSign && scanner.isListSignOp(op), opts)
+
+			// unclosed groups have no end bracket
+			if err == nil {
+				add(span.End-1, span.End, HighlightPunctuation)
+			}
+
+			step = StepJoin
+		case TokenSign:
+			add(span.Start, span.End, HighlightOperator)
+			op = normalizeSignOp(t.Literal)
+			step = stepAfterSign
+		case TokenJoin:
+			add(span.Start, span.End, HighlightOperator)
+			step = stepBeforeSign
+		case TokenNumber:
+			add(span.Start, span.End, HighlightNumber)
+			operand()
+		case TokenText:
+			add(span.Start, span.End, HighlightString)
+			operand()
+		case TokenIdentifier:
+			switch {
+			case isList:
+				add(span.Start, span.End, HighlightField)
+			case step == stepSign && isWordToken(t, "not"):
+				// the next token is expected to be the negated keyword operator
+				add(span.Start, span.End, HighlightOperator)
+			case step == stepSign && keywordSignOp(t) != "":
+				add(span.Start, span.End, HighlightOperator)
+				op = keywordSignOp(t)
+				step = stepAfterSign
+			case step == StepJoin && (isWordToken(t, "and") || isWordToken(t, "or")):
+				add(span.Start, span.End, HighlightOperator)
+				step = stepBeforeSign
+			default:
+				add(span.Start, span.End, HighlightField)
+				operand()
+			}
+		default:
+			add(span.Start, span.End, HighlightError)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package fexpr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{``, `[]`},
+		{`   `, `[]`},
+		{
+			`a = 1`,
+			`[{{0 1} field} {{2 3} operator} {{4 5} number}]`,
+		},
+		{
+			`a.b ?!= "c" || d>=@e // test`,
+			`[{{0 3} field} {{4 7} operator} {{8 11} string} {{12 14} operator} {{15 16} field} {{16 18} operator} {{18 20} field} {{21 28} comment}]`,
+		},
+		{
+			`(a = 1 && b ~ 'x')`,
+			`[{{0 1} punctuation} {{1 2} field} {{3 4} operator} {{5 6} number} {{7 9} operator} {{10 11} field} {{12 13} operator} {{14 17} string} {{17 18} punctuation}]`,
+		},
+		{
+			`a not in (1, "b", c)`,
+			`[{{0 1} field} {{2 5} operator} {{6 8} operator} {{9 10} punctuation} {{10 11} number} {{11 12} punctuation} {{13 16} string} {{16 17} punctuation} {{18 19} field} {{19 20} punctuation}]`,
+		},
+		{
+			`in like "x%" and not = 1`,
+			`[{{0 2} field} {{3 7} operator} {{8 12} string} {{13 16} operator} {{17 20} field} {{21 22} operator} {{23 24} number}]`,
+		},
+		// partial and invalid input
+		{
+			`a =! 1 $ b`,
+			`[{{0 1} field} {{2 4} error} {{5 6} number} {{7 8} error} {{9 10} field}]`,
+		},
+		{
+			`a = "unterminated`,
+			`[{{0 1} field} {{2 3} operator} {{4 17} error}]`,
+		},
+		{
+			`(a = 1 && (b`,
+			`[{{0 1} punctuation} {{1 2} field} {{3 4} operator} {{5 6} number} {{7 9} operator} {{10 11} punctuation} {{11 12} field}]`,
+		},
+		{
+			`a = 'ы' && б = 1`,
+			`[{{0 1} field} {{2 3} operator} {{4 8} string} {{9 11} operator} {{12 14} error} {{15 16} operator} {{17 18} number}]`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			result := Highlight(s.input)
+
+			if v := fmt.Sprintf("%v", result); v != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestHighlightScannerOptions(t *testing.T) {
+	result := Highlight("a = 1 # test", CommentMarkers("#"))
+
+	expected := `[{{0 1} field} {{2 3} operator} {{4 5} number} {{6 12} comment}]`
+
+	if v := fmt.Sprintf("%v", result); v != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, v)
+	}
+}
+
+func TestHighlightDeeplyNested(t *testing.T) {
+	input := strings.Repeat("(", defaultMaxDepth+1) + "a = 1"
+
+	result := Highlight(input)
+
+	last := result[len(result)-1]
+	if last.Category != HighlightError || last.Span.End != len(input) {
+		t.Fatalf("Expected the too deeply nested group to be highlighted as error, got %v", last)
+	}
+}
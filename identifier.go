@@ -0,0 +1,266 @@
+package fexpr
+
+import "strings"
+
+// IdentifierSegment represents a single `.` or `:` separated part,
+// bracket index (eg. `[0]` or `["key"]`) or JSON arrow key
+// (eg. `->"key"` or `->>"key"`) of an identifier.
+type IdentifierSegment struct {
+	// Literal is the segment text (the first segment includes
+	// the identifier's special start character, eg. "@request").
+	//
+	// For bracket index and JSON arrow segments Literal is
+	// the key number or the unquoted key text.
+	Literal string
+
+	// Separator is the character preceding the segment
+	// ('.', ':', '[' for bracket index segments, '-' for JSON arrow
+	// segments and 0 for the first segment).
+	Separator rune
+
+	// Arrow is the JSON arrow operator of the segment
+	// ("->" or "->>") and empty for all other segments.
+	Arrow string
+
+	// IndexType is the key type of the bracket index and JSON arrow
+	// segments (TokenNumber or TokenText) and empty for all other segments.
+	IndexType TokenType
+
+	// Start and End are the segment byte offsets in the identifier literal.
+	//
+	// For bracket index and JSON arrow segments the offsets include
+	// the brackets/arrow, otherwise literal[Start:End] == Literal.
+	Start int
+	End   int
+}
+
+// IsWildcard reports whether the segment is a `*` wildcard path segment
+// matching any element or key (eg. the second segment of "tags.*").
+func (s IdentifierSegment) IsWildcard() bool {
+	return s.IndexType == "" && s.Literal == "*"
+}
+
+// SplitIdentifier splits an identifier literal into its `.` and `:`
+// separated segments (eg. "@request.auth.id" => "@request", "auth", "id"),
+// bracket indexes (eg. `items[0]` => "items", "0") and JSON arrow keys
+// (eg. `data->"a"->>"b"` => "data", "a", "b").
+//
+// Empty segments (eg. between two consecutive separators) are preserved.
+func SplitIdentifier(literal string) []IdentifierSegment {
+	if literal == "" {
+		return nil
+	}
+
+	result := []IdentifierSegment{}
+
+	var separator rune
+	var start int
+	var afterIndex bool
+
+	flush := func(end int) {
+		// no plain segment between an index and the next separator
+		if afterIndex && start == end {
+			return
+		}
+
+		result = append(result, IdentifierSegment{
+			Literal:   literal[start:end],
+			Separator: separator,
+			Start:     start,
+			End:       end,
+		})
+	}
+
+	for i := 0; i < len(literal); i++ {
+		switch ch := literal[i]; ch {
+		case '.', ':':
+			flush(i)
+			separator = rune(ch)
+			start = i + 1
+			afterIndex = false
+		case '[':
+			end := identifierIndexEnd(literal, i)
+			if end < 0 {
+				continue // malformed index - leave it as part of the segment
+			}
+
+			flush(i)
+
+			segment := IdentifierSegment{
+				Literal:   literal[i+1 : end],
+				Separator: '[',
+				IndexType: TokenNumber,
+				Start:     i,
+				End:       end + 1,
+			}
+
+			if isTextStartRune(rune(segment.Literal[0])) {
+				segment.Literal = unquoteText(segment.Literal)
+				segment.IndexType = TokenText
+			}
+
+			result = append(result, segment)
+
+			i = end
+			start = end + 1
+			afterIndex = true
+		case '-':
+			arrow := identifierArrow(literal[i:])
+			if arrow == "" {
+				continue
+			}
+
+			keyStart := i + len(arrow)
+			keyEnd := identifierKeyEnd(literal, keyStart)
+			if keyEnd < 0 {
+				continue // malformed key - leave it as part of the segment
+			}
+
+			flush(i)
+
+			segment := IdentifierSegment{
+				Literal:   literal[keyStart:keyEnd],
+				Separator: '-',
+				Arrow:     arrow,
+				IndexType: TokenNumber,
+				Start:     i,
+				End:       keyEnd,
+			}
+
+			if isTextStartRune(rune(segment.Literal[0])) {
+				segment.Literal = unquoteText(segment.Literal)
+				segment.IndexType = TokenText
+			}
+
+			result = append(result, segment)
+
+			i = keyEnd - 1
+			start = keyEnd
+			afterIndex = true
+		}
+	}
+
+	flush(len(literal))
+
+	return result
+}
+
+// identifierIndexEnd returns the position of the closing bracket of the
+// identifier index starting at literal[start] or -1 if there is no valid index.
+func identifierIndexEnd(literal string, start int) int {
+	i := identifierKeyEnd(literal, start+1)
+
+	if i < 0 || i >= len(literal) || literal[i] != ']' {
+		return -1
+	}
+
+	return i
+}
+
+// identifierKeyEnd returns the end position of the number or quoted text
+// identifier key starting at literal[start] or -1 if there is no valid key.
+func identifierKeyEnd(literal string, start int) int {
+	i := start
+	if i >= len(literal) {
+		return -1
+	}
+
+	if quote := literal[i]; isTextStartRune(rune(quote)) {
+		for i++; i < len(literal); i++ {
+			if literal[i] == quote && literal[i-1] != '\\' {
+				return i + 1
+			}
+		}
+
+		return -1
+	}
+
+	for i < len(literal) && isDigitRune(rune(literal[i])) {
+		i++
+	}
+
+	if i == start {
+		return -1
+	}
+
+	return i
+}
+
+// identifierArrow returns the JSON arrow operator ("->" or "->>")
+// at the start of str or an empty string if there is none.
+func identifierArrow(str string) string {
+	if strings.HasPrefix(str, "->>") {
+		return "->>"
+	}
+
+	if strings.HasPrefix(str, "->") {
+		return "->"
+	}
+
+	return ""
+}
+
+// Identifier represents a parsed identifier literal split into its
+// base name, its `:modifier` suffixes and its optional `::type` cast.
+type Identifier struct {
+	// Name is the identifier without the modifiers and cast (eg. "author.name").
+	Name string
+
+	// Modifiers are the ordered `:` suffixes of the identifier's last
+	// path segment (eg. []string{"lower", "length"} for "name:lower:length").
+	Modifiers []string
+
+	// Cast is the explicit type conversion of the identifier value
+	// (eg. "int" for "meta.count::int") or empty if there is none.
+	//
+	// The cast type is not validated and it is up to the consumer
+	// to decide which types are supported.
+	Cast string
+}
+
+// ParseIdentifier splits an identifier literal into its base name,
+// its ordered `:modifier` suffixes (eg. "author.name:length" => "author.name", ["length"])
+// and its trailing `::type` cast (eg. "meta.count::int" => "meta.count", "int").
+//
+// Only the `:` separators after the last `.` are considered modifiers,
+// so that `:` could still be used in the middle of the path (eg. "@collection.users:u.name").
+func ParseIdentifier(literal string) (Identifier, error) {
+	if !isIdentifier(literal) {
+		return Identifier{}, errorf(ErrInvalidIdentifier, "invalid identifier %q", literal)
+	}
+
+	result := Identifier{Name: literal}
+
+	segments := SplitIdentifier(literal)
+
+	// trailing cast, aka. the "", "int" segments of "a::int"
+	if n := len(segments); n > 2 &&
+		segments[n-1].Separator == ':' &&
+		segments[n-2].Separator == ':' && segments[n-2].Literal == "" {
+		result.Cast = segments[n-1].Literal
+		result.Name = literal[:segments[n-2].Start-1]
+		segments = segments[:n-2]
+	}
+
+	// find the first modifier segment of the last path segment
+	first := len(segments)
+	for i := len(segments) - 1; i > 0 && segments[i].Separator == ':'; i-- {
+		first = i
+	}
+
+	if first == len(segments) {
+		return result, nil
+	}
+
+	result.Name = literal[:segments[first].Start-1]
+
+	for _, s := range segments[first:] {
+		if s.Literal == "" {
+			return Identifier{}, errorf(ErrInvalidIdentifier, "empty modifier in identifier %q", literal)
+		}
+
+		result.Modifiers = append(result.Modifiers, s.Literal)
+	}
+
+	return result, nil
+}
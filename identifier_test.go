@@ -0,0 +1,121 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSplitIdentifier(t *testing.T) {
+	scenarios := []struct {
+		literal  string
+		expected string
+	}{
+		{``, `[]`},
+		{`id`, `[{id 0   0 2}]`},
+		{`@request.auth.id`, `[{@request 0   0 8} {auth 46   9 13} {id 46   14 16}]`},
+		{`#a:b.c`, `[{#a 0   0 2} {b 58   3 4} {c 46   5 6}]`},
+		{`a::b`, `[{a 0   0 1} { 58   2 2} {b 58   3 4}]`},
+		{`a.`, `[{a 0   0 1} { 46   2 2}]`},
+		{`items[0]`, `[{items 0   0 5} {0 91  number 5 8}]`},
+		{`items[10][2].name:length`, `[{items 0   0 5} {10 91  number 5 9} {2 91  number 9 12} {name 46   13 17} {length 58   18 24}]`},
+		{`data["weird.key:\"[0]"].a`, `[{data 0   0 4} {weird.key:"[0] 91  text 4 23} {a 46   24 25}]`},
+		{`data['a'`, `[{data['a' 0   0 8}]`},
+		{`addresses.*.city`, `[{addresses 0   0 9} {* 46   10 11} {city 46   12 16}]`},
+		{`data[]`, `[{data[] 0   0 6}]`},
+		{`data->"profile"->>'name'`, `[{data 0   0 4} {profile 45 -> text 4 15} {name 45 ->> text 15 24}]`},
+		{`items->0->>"a.b".c`, `[{items 0   0 5} {0 45 -> number 5 8} {a.b 45 ->> text 8 16} {c 46   17 18}]`},
+		{`a->b`, `[{a->b 0   0 4}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.literal), func(t *testing.T) {
+			segments := SplitIdentifier(s.literal)
+
+			if v := fmt.Sprintf("%v", segments); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+
+			for _, seg := range segments {
+				if seg.IndexType != "" {
+					continue
+				}
+
+				if v := s.literal[seg.Start:seg.End]; v != seg.Literal {
+					t.Fatalf("Expected the literal at [%d:%d] to be %q, got %q", seg.Start, seg.End, seg.Literal, v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseIdentifier(t *testing.T) {
+	scenarios := []struct {
+		literal       string
+		expectedError bool
+		expected      string
+	}{
+		{``, true, `{ [] }`},
+		{`a.`, true, `{ [] }`},
+		{`a::`, true, `{ [] }`},
+		{`a:::b`, true, `{ [] }`},
+		{`a::b:c`, true, `{ [] }`},
+		{`id`, false, `{id [] }`},
+		{`@request.auth.id`, false, `{@request.auth.id [] }`},
+		{`author.name:length`, false, `{author.name [length] }`},
+		{`name:lower:length`, false, `{name [lower length] }`},
+		{`@collection.users:u.name`, false, `{@collection.users:u.name [] }`},
+		{`@collection.users:u.name:each`, false, `{@collection.users:u.name [each] }`},
+		{`meta.count::int`, false, `{meta.count [] int}`},
+		{`name:lower::text`, false, `{name [lower] text}`},
+		{`items[0]::float`, false, `{items[0] [] float}`},
+		{`data->>"n"::int`, false, `{data->>"n" [] int}`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.literal), func(t *testing.T) {
+			result, err := ParseIdentifier(s.literal)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if v := fmt.Sprintf("%v", result); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestParseIdentifierIndex(t *testing.T) {
+	result, err := ParseIdentifier(`items[0]:length`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{items[0] [length] }`
+	if v := fmt.Sprintf("%v", result); v != expected {
+		t.Fatalf("Expected %s, got %s", expected, v)
+	}
+}
+
+func TestIdentifierSegmentIsWildcard(t *testing.T) {
+	scenarios := []struct {
+		segment  IdentifierSegment
+		expected bool
+	}{
+		{IdentifierSegment{}, false},
+		{IdentifierSegment{Literal: "a"}, false},
+		{IdentifierSegment{Literal: "*", Separator: '.'}, true},
+		{IdentifierSegment{Literal: "*", Separator: '[', IndexType: TokenText}, false},
+	}
+
+	for i, s := range scenarios {
+		if v := s.segment.IsWildcard(); v != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, v)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package fexpr
+
+import "sort"
+
+// Report represents a summary of the parsed filter building blocks.
+//
+// All report slices are sorted and without duplicates.
+type Report struct {
+	// Identifiers are the literals of all identifier operands
+	// (including the ones in the `in` values lists).
+	//
+	// The `null`, `true` and `false` identifiers are literals
+	// (see LiteralTypes) and they are not included.
+	Identifiers []string
+
+	// Operators are the used expression sign operators.
+	Operators []SignOp
+
+	// Joins are the used join operators.
+	Joins []JoinOp
+
+	// LiteralTypes are the token types of the non-identifier operands
+	// (eg. TokenText, TokenNumber) including the `in` values list items.
+	//
+	// The `null`, `true` and `false` identifiers are reported
+	// as TokenNull and TokenBool.
+	LiteralTypes []TokenType
+
+	// UnaryOperators are the used custom unary operators (see RegisterUnaryOp).
+	UnaryOperators []UnaryOp
+}
+
+// Inspect returns a Report summarizing the identifiers, operators
+// (including the custom unary ones) and literal types used in the provided parsed filter, usually needed for
+// security reviews and policy enforcement.
+func Inspect(exprs []ExprGroup) Report {
+	identifiers := map[string]struct{}{}
+	operators := map[string]struct{}{}
+	joins := map[string]struct{}{}
+	literalTypes := map[string]struct{}{}
+	unaryOperators := map[string]struct{}{}
+
+	addOperand := func(t Token) {
+		switch {
+		case isNullToken(t):
+			literalTypes[string(TokenNull)] = struct{}{}
+		case isWordToken(t, "true") || isWordToken(t, "false"):
+			literalTypes[string(TokenBool)] = struct{}{}
+		case t.Type == TokenIdentifier:
+			identifiers[t.Literal] = struct{}{}
+		default:
+			literalTypes[string(t.Type)] = struct{}{}
+		}
+	}
+
+	var inspect func(groups []ExprGroup)
+	inspect = func(groups []ExprGroup) {
+		for i, g := range groups {
+			// the first group join is not an actual operator
+			if i > 0 {
+				joins[string(g.Join)] = struct{}{}
+			}
+
+			switch v := g.Item.(type) {
+			case Expr:
+				operators[string(v.Op)] = struct{}{}
+
+				for _, t := range []Token{v.Left, v.Right} {
+					if t.Type != TokenList {
+						addOperand(t)
+						continue
+					}
+
+					items, _ := SplitList(t.Literal)
+					for _, item := range items {
+						addOperand(item)
+					}
+				}
+			case []ExprGroup:
+				inspect(v)
+			case UnaryExpr:
+				unaryOperators[string(v.Op)] = struct{}{}
+				inspect(unaryOperand(v))
+			}
+		}
+	}
+
+	inspect(exprs)
+
+	report := Report{}
+
+	for _, v := range sortedKeys(identifiers) {
+		report.Identifiers = append(report.Identifiers, v)
+	}
+
+	for _, v := range sortedKeys(operators) {
+		report.Operators = append(report.Operators, SignOp(v))
+	}
+
+	for _, v := range sortedKeys(joins) {
+		report.Joins = append(report.Joins, JoinOp(v))
+	}
+
+	for _, v := range sortedKeys(literalTypes) {
+		report.LiteralTypes = append(report.LiteralTypes, TokenType(v))
+	}
+
+	for _, v := range sortedKeys(unaryOperators) {
+		report.UnaryOperators = append(report.UnaryOperators, UnaryOp(v))
+	}
+
+	return report
+}
+
+// sortedKeys returns the sorted keys of the provided string set.
+func sortedKeys(set map[string]struct{}) []string {
+	result := make([]string, 0, len(set))
+
+	for k := range set {
+		result = append(result, k)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,55 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1`, `{[a] [=] [] [number] []}`},
+		{`1 = 1`, `{[] [=] [] [number] []}`},
+		{
+			`a = 1 && (b.c ~ "x" || a > d) && e in (f, 'g', 2)`,
+			`{[a b.c d e f] [= > in ~] [&& ||] [number text] []}`,
+		},
+		{`a = null && b != true || c in (false, 1)`, `{[a b c] [!= = in] [&& ||] [bool null number] []}`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := fmt.Sprintf("%v", Inspect(exprs)); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestInspectUnary(t *testing.T) {
+	exprs, err := Parse(`!!(b = 1) && ~~ c = "x"`, ScannerOptions(
+		RegisterUnaryOp("!!", UnaryOpOptions{}),
+		RegisterUnaryOp("~~", UnaryOpOptions{}),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{[b c] [=] [&&] [number text] [!! ~~]}`
+	if v := fmt.Sprintf("%v", Inspect(exprs)); v != expected {
+		t.Fatalf("Expected %s, got %s", expected, v)
+	}
+}
+
+func TestInspectEmpty(t *testing.T) {
+	if v := fmt.Sprintf("%v", Inspect(nil)); v != `{[] [] [] [] []}` {
+		t.Fatalf("Expected empty report, got %s", v)
+	}
+}
@@ -0,0 +1,223 @@
+package fexpr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jqComparators maps the sign operators to their jq comparison operators.
+var jqComparators = map[SignOp]string{
+	SignEq:  "==",
+	SignNeq: "!=",
+	SignLt:  "<",
+	SignLte: "<=",
+	SignGt:  ">",
+	SignGte: ">=",
+}
+
+// ToJQ converts the provided parsed filter into an equivalent jq
+// select() expression, for example `age > 18 && (role = "admin" || name ~ "john")`
+// becomes:
+//
+//	select(.age > 18 and (.role == "admin" or (.name | test("^.*john.*$"; "i"))))
+//
+// The identifier operands must be plain field identifiers (nested fields
+// are converted to `.a.b` paths) and the `null`, `true` and `false`
+// identifiers are converted to their jq literals.
+//
+// The `~` and `!~` operators are converted to case-insensitive contains
+// regex tests, the `like` and `ilike` patterns to anchored regex tests,
+// the `in` lists to IN() and the array/any and array/all operators to
+// any() and all() checks of the array field elements.
+func ToJQ(exprs []ExprGroup) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("select(")
+	if len(exprs) == 0 {
+		sb.WriteString("true")
+	} else if err := writeJQGroups(&sb, exprs); err != nil {
+		return "", err
+	}
+	sb.WriteString(")")
+
+	return sb.String(), nil
+}
+
+// writeJQGroups writes the jq condition of the groups slice.
+func writeJQGroups(sb *strings.Builder, groups []ExprGroup) error {
+	for i, g := range groups {
+		if i > 0 {
+			if g.Join == JoinOr {
+				sb.WriteString(" or ")
+			} else {
+				sb.WriteString(" and ")
+			}
+		}
+
+		switch v := g.Item.(type) {
+		case Expr:
+			condition, err := jqCondition(v)
+			if err != nil {
+				return err
+			}
+			sb.WriteString(condition)
+		case []ExprGroup:
+			sb.WriteString("(")
+			if err := writeJQGroups(sb, v); err != nil {
+				return err
+			}
+			sb.WriteString(")")
+		default:
+			return errorf(ErrUnsupportedItem, "unsupported expression group item %T", g.Item)
+		}
+	}
+
+	return nil
+}
+
+// jqCondition converts a single expression into a jq condition.
+func jqCondition(expr Expr) (string, error) {
+	left, err := jqOperand(expr.Left)
+	if err != nil {
+		return "", err
+	}
+
+	op := expr.Op
+
+	switch op {
+	case SignIn, SignNotIn:
+		right, err := jqListItems(expr.Right)
+		if err != nil {
+			return "", err
+		}
+
+		if op == SignNotIn {
+			return fmt.Sprintf("(%s | IN(%s) | not)", left, right), nil
+		}
+
+		return fmt.Sprintf("(%s | IN(%s))", left, right), nil
+	}
+
+	// array/any and array/all operators
+	var quantifier string
+	if strings.HasPrefix(string(op), "?") {
+		quantifier = "any"
+		op = op[1:]
+	} else if strings.HasPrefix(string(op), "*") {
+		quantifier = "all"
+		op = op[1:]
+	}
+
+	right, err := jqOperand(expr.Right)
+	if err != nil {
+		return "", err
+	}
+
+	predicate, err := jqPredicate(op, expr.Right)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case quantifier != "" && predicate == "":
+		// bind the right operand since the `.` context is changed in any()/all()
+		return fmt.Sprintf("(%s as $v | %s(%s[]; . %s $v))", right, quantifier, left, jqComparators[op]), nil
+	case quantifier != "":
+		return fmt.Sprintf("%s(%s[]; %s)", quantifier, left, predicate), nil
+	case predicate == "":
+		return fmt.Sprintf("%s %s %s", left, jqComparators[op], right), nil
+	default:
+		return fmt.Sprintf("(%s | %s)", left, predicate), nil
+	}
+}
+
+// jqPredicate returns the jq pattern test of `.` for the like sign operators
+// or an empty string for the jqComparators operators.
+func jqPredicate(op SignOp, right Token) (string, error) {
+	if _, ok := jqComparators[op]; ok {
+		return "", nil
+	}
+
+	var pattern string
+	var flags string
+	var negate bool
+
+	switch op {
+	case SignLike, SignNlike:
+		pattern = containsPattern(right.Literal)
+		flags = "i"
+		negate = op == SignNlike
+	case SignSQLLike, SignSQLNlike:
+		pattern = right.Literal
+		negate = op == SignSQLNlike
+	case SignSQLIlike, SignSQLNilike:
+		pattern = right.Literal
+		flags = "i"
+		negate = op == SignSQLNilike
+	default:
+		return "", unsupportedSignOpError(op, "jq")
+	}
+
+	if right.Type != TokenText {
+		return "", errorf(ErrInvalidOperand, "expected text right operand for %q, got %q (%s)", op, right.Literal, right.Type)
+	}
+
+	result := "test(" + jqLiteral("^"+likeRegexp(pattern)+"$")
+	if flags != "" {
+		result += "; " + jqLiteral(flags)
+	}
+	result += ")"
+
+	if negate {
+		result += " | not"
+	}
+
+	return result, nil
+}
+
+// jqOperand converts a single operand token into a jq value expression.
+func jqOperand(t Token) (string, error) {
+	if value, ok := literalValue(t); ok && t.Type != TokenList {
+		return jqLiteral(value), nil
+	}
+
+	path, err := fieldPath(t)
+	if err != nil {
+		return "", err
+	}
+
+	return "." + strings.Join(path, "."), nil
+}
+
+// jqListItems converts the items of a list token into
+// comma separated jq value expressions.
+func jqListItems(t Token) (string, error) {
+	items, err := SplitList(t.Literal)
+	if err != nil {
+		return "", err
+	}
+
+	result := make([]string, len(items))
+	for i, item := range items {
+		if result[i], err = jqOperand(item); err != nil {
+			return "", err
+		}
+	}
+
+	return strings.Join(result, ", "), nil
+}
+
+// jqLiteral returns the JSON (aka. jq literal) representation of value.
+func jqLiteral(value interface{}) string {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "null" // the literal values are always encodable
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,55 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToJQ(t *testing.T) {
+	scenarios := []struct {
+		input          string
+		expectedError  bool
+		expectedResult string
+	}{
+		{`items[0] = 1`, true, ``},
+		{`a = @request.id`, true, ``},
+		{`a ~ b`, true, ``},
+		{`a like 1`, true, ``},
+		{`a in (1, b[0])`, true, ``},
+		{`a = 1`, false, `select(.a == 1)`},
+		{`a.b != "x&y" && 1.5 < c || d >= e.f && g <= null`, false, `select(.a.b != "x&y" and 1.5 < .c or .d >= .e.f and .g <= null)`},
+		{
+			`age > 18 && (role = "admin" || name ~ "john")`,
+			false,
+			`select(.age > 18 and (.role == "admin" or (.name | test("^.*john.*$"; "i"))))`,
+		},
+		{`a !~ "x%.y"`, false, `select((.a | test("^x.*\\.y$"; "i") | not))`},
+		{`a like "x_" && b not ilike "%y"`, false, `select((.a | test("^x.$")) and (.b | test("^.*y$"; "i") | not))`},
+		{`a in (1, "x", b) && c not in (true)`, false, `select((.a | IN(1, "x", .b)) and (.c | IN(true) | not))`},
+		{`tags ?= "x" && scores *> min`, false, `select(("x" as $v | any(.tags[]; . == $v)) and (.min as $v | all(.scores[]; . > $v)))`},
+		{`tags ?~ "x" && tags *!~ "y"`, false, `select(any(.tags[]; test("^.*x.*$"; "i")) and all(.tags[]; test("^.*y.*$"; "i") | not))`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := ToJQ(exprs)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if result != s.expectedResult {
+				t.Fatalf("Expected %s, got %s", s.expectedResult, result)
+			}
+		})
+	}
+}
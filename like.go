@@ -0,0 +1,39 @@
+package fexpr
+
+import (
+	"regexp"
+	"strings"
+)
+
+// likeEscaper escapes the SQL like wildcards and the default escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the `%` and `_` wildcard characters in text with
+// the default `\` escape character so that it could be safely used as
+// a literal part of a `like` or `ilike` operator pattern
+// (eg. `"name like " + quoted("%" + EscapeLike(userInput) + "%")`).
+func EscapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
+
+// likeRegexp converts a `like` pattern into an equivalent unanchored
+// regular expression body (eg. `a\_b%` => `a_b.*`).
+func likeRegexp(pattern string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; {
+		case ch == '%':
+			sb.WriteString(".*")
+		case ch == '_':
+			sb.WriteString(".")
+		case ch == '\\' && i+1 < len(pattern):
+			i++
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEscapeLike(t *testing.T) {
+	scenarios := []struct {
+		text     string
+		expected string
+	}{
+		{``, ``},
+		{`abc`, `abc`},
+		{`100%_done\`, `100\%\_done\\`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			if v := EscapeLike(s.text); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestParseLike(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{`a like`, true, `[]`},
+		{`a not`, true, `[]`},
+		{`a not ilike`, true, `[]`},
+		{`a not not like "b"`, true, `[]`},
+		{`a like "jo%"`, false, `[{&& {{identifier a} like {text jo%}}}]`},
+		{`a NOT Like 'j_'`, false, `[{&& {{identifier a} not like {text j_}}}]`},
+		{`a ilike b && c not ilike "%\%"`, false, `[{&& {{identifier a} ilike {identifier b}}} {&& {{identifier c} not ilike {text %\%}}}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := Parse(s.input)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestLikeRegexp(t *testing.T) {
+	scenarios := []struct {
+		pattern  string
+		expected string
+	}{
+		{``, ``},
+		{`abc`, `abc`},
+		{`a.b%`, `a\.b.*`},
+		{`_x%y_`, `.x.*y.`},
+		{`100\%\_\\`, `100%_\\`},
+		{`trailing\`, `trailing\\`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.pattern), func(t *testing.T) {
+			if v := likeRegexp(s.pattern); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package fexpr
+
+import (
+	"fmt"
+)
+
+// LintWarning represents a single non-fatal filter expression issue.
+type LintWarning struct {
+	Expr    Expr
+	Message string
+}
+
+// Lint inspects the provided parsed filter for semantic issues
+// that are valid syntax but are most likely a mistake, such as:
+//   - comparing a quoted number (eg. `total > "10"`)
+//   - always constant comparisons (eg. `1 = 1`, `a = a`)
+//   - like operators with an empty string (eg. `a ~ ""`)
+//   - duplicated conditions (eg. `a = 1 && a = 1`)
+func Lint(exprs []ExprGroup) []LintWarning {
+	result := []LintWarning{}
+
+	lintExprGroups(exprs, &result)
+
+	return result
+}
+
+// lintExprGroups appends the groups issues to result.
+func lintExprGroups(groups []ExprGroup, result *[]LintWarning) {
+	seenDisjuncts := map[string]struct{}{}
+
+	for _, conjunction := range splitOr(groups) {
+		seen := map[string]struct{}{}
+
+		for _, g := range conjunction {
+			switch v := g.Item.(type) {
+			case Expr:
+				lintExpr(v, result)
+
+				key := itemString(v)
+
+				if _, ok := seen[key]; ok {
+					*result = append(*result, LintWarning{Expr: v, Message: "duplicated && condition"})
+				}
+				seen[key] = struct{}{}
+
+				if len(conjunction) == 1 {
+					if _, ok := seenDisjuncts[key]; ok {
+						*result = append(*result, LintWarning{Expr: v, Message: "duplicated || condition"})
+					}
+					seenDisjuncts[key] = struct{}{}
+				}
+			case []ExprGroup:
+				lintExprGroups(v, result)
+			case UnaryExpr:
+				lintExprGroups(unaryOperand(v), result)
+			}
+		}
+	}
+}
+
+// lintExpr appends the single expression issues to result.
+func lintExpr(expr Expr, result *[]LintWarning) {
+	for _, t := range []Token{expr.Left, expr.Right} {
+		if t.Type == TokenText && isNumber(t.Literal) {
+			*result = append(*result, LintWarning{
+				Expr:    expr,
+				Message: fmt.Sprintf("quoted number %q is compared as text", t.Literal),
+			})
+		}
+	}
+
+	if isConstantOperand(expr.Left) && isConstantOperand(expr.Right) {
+		*result = append(*result, LintWarning{
+			Expr:    expr,
+			Message: "comparison between two literals has a constant result",
+		})
+	} else if expr.Left.Type == expr.Right.Type && expr.Left.Literal == expr.Right.Literal {
+		*result = append(*result, LintWarning{
+			Expr:    expr,
+			Message: fmt.Sprintf("%q is compared with itself", expr.Left.Literal),
+		})
+	}
+
+	switch expr.Op {
+	case SignLike, SignNlike, SignAnyLike, SignAnyNlike, SignAllLike, SignAllNlike:
+		if (expr.Left.Type == TokenText && expr.Left.Literal == "") ||
+			(expr.Right.Type == TokenText && expr.Right.Literal == "") {
+			*result = append(*result, LintWarning{
+				Expr:    expr,
+				Message: fmt.Sprintf("%s operator with an empty string has a constant result", expr.Op),
+			})
+		}
+	}
+}
+
+// isConstantOperand checks if t is a literal operand or a list of literals.
+func isConstantOperand(t Token) bool {
+	if t.Type != TokenList {
+		return t.Type != TokenIdentifier
+	}
+
+	items, err := SplitList(t.Literal)
+	if err != nil {
+		return false
+	}
+
+	for _, item := range items {
+		if item.Type == TokenIdentifier {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,52 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected []string
+	}{
+		{`a = 1 && b ?~ "test" || c != d`, nil},
+		{`a = "1.5"`, []string{`quoted number "1.5" is compared as text`}},
+		{`1 = 1`, []string{`comparison between two literals has a constant result`}},
+		{`"a" = 'b'`, []string{`comparison between two literals has a constant result`}},
+		{`"a" in (b, 1) && a not in (1, 2)`, nil},
+		{`"a" in ("b", 1)`, []string{`comparison between two literals has a constant result`}},
+		{`a.b = a.b`, []string{`"a.b" is compared with itself`}},
+		{`a ~ ""`, []string{`~ operator with an empty string has a constant result`}},
+		{`"" ?!~ a`, []string{`?!~ operator with an empty string has a constant result`}},
+		{`a = 1 && b = 2 && a = 1`, []string{`duplicated && condition`}},
+		{`a = 1 || b = 2 || a = 1`, []string{`duplicated || condition`}},
+		{`a = 1 && b = 2 || a = 1 && b = 3`, nil},
+		{`c = 1 || (a = 1 && (b = "2" || b = "2"))`, []string{
+			`quoted number "2" is compared as text`,
+			`quoted number "2" is compared as text`,
+			`duplicated || condition`,
+		}},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			warnings := Lint(exprs)
+
+			if len(warnings) != len(s.expected) {
+				t.Fatalf("Expected %d warnings, got %d: %v", len(s.expected), len(warnings), warnings)
+			}
+
+			for j, w := range warnings {
+				if w.Message != s.expected[j] {
+					t.Errorf("(%d) Expected warning %q, got %q", j, s.expected[j], w.Message)
+				}
+			}
+		})
+	}
+}
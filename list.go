@@ -0,0 +1,123 @@
+package fexpr
+
+import "strings"
+
+// keywordSignOps lists the sign operators that are written as words
+// (their negated versions are prefixed with "not").
+var keywordSignOps = []SignOp{SignIn, SignSQLLike, SignSQLIlike}
+
+// keywordSignOp returns the case-insensitive keyword sign operator
+// matching t or an empty string if t is not a keyword operator.
+func keywordSignOp(t Token) SignOp {
+	for _, op := range keywordSignOps {
+		if isWordToken(t, string(op)) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+// isKeywordSignOp checks if op is written as words (eg. `in` or `not like`).
+func isKeywordSignOp(op SignOp) bool {
+	return op != "" && isLetterRune(rune(op[0]))
+}
+
+// isListSignOp checks if op is a list keyword operator (`in` or `not in`).
+func isListSignOp(op SignOp) bool {
+	return op == SignIn || op == SignNotIn
+}
+
+// SplitList splits a TokenList literal (eg. `"draft", "pending"`)
+// into its identifier, number and text operand tokens.
+func SplitList(literal string) ([]Token, error) {
+	return splitList(literal, false)
+}
+
+// parseList parses the parenthesized values list text of
+// an `in` operator and returns it as TokenList token.
+//
+// The list literal is normalized to comma and single space
+// separated operands with quoted text items.
+func (p *parser) parseList(text string) (Token, error) {
+	items, err := splitList(text, p.strict, p.scannerOpts...)
+	if err != nil {
+		return Token{}, err
+	}
+
+	var sb strings.Builder
+
+	for i, item := range items {
+		if item, err = p.resolveOperand(item); err != nil {
+			return Token{}, err
+		}
+
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		writeToken(&sb, item)
+	}
+
+	return Token{Type: TokenList, Literal: sb.String()}, nil
+}
+
+// splitList tokenizes a comma separated list of operands.
+//
+// Set noComments to return an error if the list contains comments.
+func splitList(text string, noComments bool, opts ...ScannerOption) ([]Token, error) {
+	result := []Token{}
+
+	scanner := NewScanner(strings.NewReader(text), opts...)
+
+	// the list separator is an unexpected character for the scanner
+	// and the recovery mode would consume it together with the next item
+	scanner.recoverErrors = false
+
+	expectItem := true
+
+	for {
+		t, err := scanner.Scan()
+
+		// the scanner doesn't have a dedicated comma token
+		isComma := t.Type == TokenUnexpected && t.Literal == ","
+
+		if err != nil && !isComma {
+			return nil, err
+		}
+
+		if t.Type == TokenComment && noComments {
+			return nil, errorf(ErrStrictMode, "comments are not allowed in strict mode")
+		}
+
+		if t.Type == TokenWS || t.Type == TokenComment {
+			continue
+		}
+
+		if t.Type == TokenEOF {
+			break
+		}
+
+		if expectItem {
+			if t.Type != TokenIdentifier && t.Type != TokenText && t.Type != TokenNumber {
+				return nil, errorf(ErrUnexpectedToken, "expected list item (identifier, text or number), got %q (%s)", t.Literal, t.Type)
+			}
+
+			result = append(result, t)
+		} else if !isComma {
+			return nil, errorf(ErrUnexpectedToken, "expected list separator \",\", got %q (%s)", t.Literal, t.Type)
+		}
+
+		expectItem = !expectItem
+	}
+
+	if len(result) == 0 {
+		return nil, errorf(ErrInvalidList, "empty values list")
+	}
+
+	if expectItem {
+		return nil, errorf(ErrInvalidList, "missing list item after the trailing \",\"")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	scenarios := []struct {
+		literal       string
+		expectedError bool
+		expected      string
+	}{
+		{``, true, `[]`},
+		{`,`, true, `[]`},
+		{`1,`, true, `[]`},
+		{`,1`, true, `[]`},
+		{`1 2`, true, `[]`},
+		{`1,,2`, true, `[]`},
+		{`(1)`, true, `[]`},
+		{`a = 1`, true, `[]`},
+		{`1`, false, `[{number 1}]`},
+		{` "draft" , 'pending',@a.b,-1.5 `, false, `[{text draft} {text pending} {identifier @a.b} {number -1.5}]`},
+		{"a, // comment\nb", false, `[{identifier a} {identifier b}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.literal), func(t *testing.T) {
+			v, err := SplitList(s.literal)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, vPrint)
+			}
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{`a in`, true, `[]`},
+		{`a in 1`, true, `[]`},
+		{`a in ()`, true, `[]`},
+		{`a in (1,)`, true, `[]`},
+		{`a not (1)`, true, `[]`},
+		{`a not = 1`, true, `[]`},
+		{`a in (1) in (2)`, true, `[]`},
+		{`a in (1)`, false, `[{&& {{identifier a} in {list 1}}}]`},
+		{`status IN ("draft",'pending' , b)`, false, `[{&& {{identifier status} in {list "draft", "pending", b}}}]`},
+		{`status not  In ("draft") || (id not in (1, 2) && a = 1)`, false, `[{&& {{identifier status} not in {list "draft"}}} {|| [{&& {{identifier id} not in {list 1, 2}}} {&& {{identifier a} = {number 1}}}]}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := Parse(s.input)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestParseListPlaceholders(t *testing.T) {
+	v, err := Parse(`a in (@b, 1)`, Placeholders(PlaceholderMap{"@b": {Type: TokenText, Literal: "x"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& {{identifier a} in {list "x", 1}}}]`
+	if vPrint := fmt.Sprintf("%v", v); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+}
+
+func TestParseListRecoverErrors(t *testing.T) {
+	v, err := Parse(`a in (1,b,"c")`, ScannerOptions(RecoverErrors()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& {{identifier a} in {list 1, b, "c"}}}]`
+	if vPrint := fmt.Sprintf("%v", v); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+}
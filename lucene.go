@@ -0,0 +1,218 @@
+package fexpr
+
+import "strings"
+
+// luceneEscaper escapes the Lucene/Bleve query string special characters.
+var luceneEscaper = strings.NewReplacer(
+	`\`, `\\`, `+`, `\+`, `-`, `\-`, `=`, `\=`, `&`, `\&`, `|`, `\|`,
+	`>`, `\>`, `<`, `\<`, `!`, `\!`, `(`, `\(`, `)`, `\)`, `{`, `\{`,
+	`}`, `\}`, `[`, `\[`, `]`, `\]`, `^`, `\^`, `"`, `\"`, `~`, `\~`,
+	`*`, `\*`, `?`, `\?`, `:`, `\:`, `/`, `\/`, ` `, `\ `,
+)
+
+// lucenePhraseEscaper escapes the special characters of a quoted phrase.
+var lucenePhraseEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// luceneRangeOperators maps the sign operators to their query string range prefixes.
+var luceneRangeOperators = map[SignOp]string{
+	SignLt:  "<",
+	SignLte: "<=",
+	SignGt:  ">",
+	SignGte: ">=",
+}
+
+// luceneAnySignOps maps the array/any sign operators to the equivalent
+// single value operators (the multi-valued fields match if any value matches).
+var luceneAnySignOps = map[SignOp]SignOp{
+	SignAnyEq:   SignEq,
+	SignAnyLike: SignLike,
+	SignAnyLt:   SignLt,
+	SignAnyLte:  SignLte,
+	SignAnyGt:   SignGt,
+	SignAnyGte:  SignGte,
+}
+
+// luceneClause represents a single query string clause and
+// whether it should be excluded from the results.
+type luceneClause struct {
+	text   string
+	negate bool
+}
+
+// ToLucene converts the provided parsed filter into a Lucene/Bleve query
+// string, for example `status = "active" && age > 18 && role != "guest"`
+// becomes `+status:"active" +age:>18 -role:"guest"`.
+//
+// The `&&` conditions are converted to required (`+`) or prohibited (`-`)
+// clauses and the `||` conditions to optional clauses (the conditions with
+// only prohibited clauses get an additional `*:*` match-all clause, eg.
+// `status != "x"` => `*:* -status:"x"`). Nested groups and
+// `in` lists are wrapped in parenthesis, which is supported by the Lucene
+// (and Elasticsearch) query string syntax but not by Bleve.
+//
+// The left operands must be plain field identifiers and the right operands
+// literals. The `~` and `like` patterns are converted to wildcard terms
+// (eg. `name ~ "john"` => `name:*john*`) and the `= null` and `!= null`
+// comparisons to the `field:*` existence check. The array/any operators
+// are converted to their single value equivalents and the array/all
+// operators are not supported.
+func ToLucene(exprs []ExprGroup) (string, error) {
+	clauses, err := luceneGroupClauses(exprs)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(clauses, " "), nil
+}
+
+// luceneGroupClauses returns the query string clauses of the groups slice.
+func luceneGroupClauses(groups []ExprGroup) ([]string, error) {
+	disjuncts := splitOr(groups)
+	result := make([]string, 0, len(groups))
+
+	for _, conjunction := range disjuncts {
+		clauses := make([]string, 0, len(conjunction)+1)
+		negativeOnly := true
+
+		for _, g := range conjunction {
+			clause, err := luceneItemClause(g.Item)
+			if err != nil {
+				return nil, err
+			}
+
+			if clause.negate {
+				clauses = append(clauses, "-"+clause.text)
+			} else {
+				clauses = append(clauses, "+"+clause.text)
+				negativeOnly = false
+			}
+		}
+
+		// a purely negative conjunction matches nothing without the match-all term
+		if negativeOnly {
+			clauses = append([]string{"*:*"}, clauses...)
+		}
+
+		switch {
+		case len(disjuncts) == 1:
+			result = append(result, clauses...)
+		case len(clauses) > 1:
+			result = append(result, "("+strings.Join(clauses, " ")+")")
+		default:
+			result = append(result, clauses[0][1:])
+		}
+	}
+
+	return result, nil
+}
+
+// luceneItemClause converts a single ExprGroup.Item into a query string clause.
+func luceneItemClause(item interface{}) (luceneClause, error) {
+	switch v := item.(type) {
+	case Expr:
+		return luceneExprClause(v)
+	case []ExprGroup:
+		clauses, err := luceneGroupClauses(v)
+		if err != nil {
+			return luceneClause{}, err
+		}
+		return luceneClause{text: "(" + strings.Join(clauses, " ") + ")"}, nil
+	default:
+		return luceneClause{}, errorf(ErrUnsupportedItem, "unsupported expression group item %T", item)
+	}
+}
+
+// luceneExprClause converts a single expression into a query string clause.
+func luceneExprClause(expr Expr) (luceneClause, error) {
+	path, err := fieldPath(expr.Left)
+	if err != nil {
+		return luceneClause{}, err
+	}
+
+	field := strings.Join(path, ".")
+
+	value, ok := literalValue(expr.Right)
+	if !ok {
+		return luceneClause{}, errorf(ErrInvalidOperand, "expected literal right operand, got %q (%s)", expr.Right.Literal, expr.Right.Type)
+	}
+
+	op := expr.Op
+	if singleOp, ok := luceneAnySignOps[op]; ok {
+		op = singleOp
+	}
+
+	if rangeOp, ok := luceneRangeOperators[op]; ok {
+		if value == nil {
+			return luceneClause{}, errorf(ErrInvalidOperand, "unsupported null comparison with %q", expr.Op)
+		}
+		return luceneClause{text: field + ":" + rangeOp + luceneTerm(expr.Right)}, nil
+	}
+
+	switch op {
+	case SignEq, SignNeq:
+		if value == nil {
+			return luceneClause{text: field + ":*", negate: op == SignEq}, nil
+		}
+		return luceneClause{text: field + ":" + luceneTerm(expr.Right), negate: op == SignNeq}, nil
+	case SignIn, SignNotIn:
+		items, err := SplitList(expr.Right.Literal)
+		if err != nil {
+			return luceneClause{}, err
+		}
+
+		terms := make([]string, len(items))
+		for i, item := range items {
+			terms[i] = field + ":" + luceneTerm(item)
+		}
+
+		return luceneClause{text: "(" + strings.Join(terms, " ") + ")", negate: op == SignNotIn}, nil
+	case SignLike, SignNlike, SignSQLLike, SignSQLNlike, SignSQLIlike, SignSQLNilike:
+		if expr.Right.Type != TokenText {
+			return luceneClause{}, errorf(ErrInvalidOperand, "expected text right operand for %q, got %q (%s)", expr.Op, expr.Right.Literal, expr.Right.Type)
+		}
+
+		pattern := expr.Right.Literal
+		if op == SignLike || op == SignNlike {
+			pattern = containsPattern(pattern)
+		}
+
+		negate := op == SignNlike || op == SignSQLNlike || op == SignSQLNilike
+
+		return luceneClause{text: field + ":" + luceneWildcard(pattern), negate: negate}, nil
+	default:
+		return luceneClause{}, unsupportedSignOpError(expr.Op, "Lucene")
+	}
+}
+
+// luceneTerm returns the query string term of a literal operand token
+// (aka. quoted phrase for text and the escaped literal for the rest,
+// eg. `-5` => `\-5` since a leading `-` is a query string operator).
+func luceneTerm(t Token) string {
+	if t.Type == TokenText {
+		return `"` + lucenePhraseEscaper.Replace(t.Literal) + `"`
+	}
+
+	return luceneEscaper.Replace(t.Literal)
+}
+
+// luceneWildcard converts a `like` pattern into an escaped
+// query string wildcard term (eg. `a b%_` => `a\ b*?`).
+func luceneWildcard(pattern string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; {
+		case ch == '%':
+			sb.WriteString("*")
+		case ch == '_':
+			sb.WriteString("?")
+		case ch == '\\' && i+1 < len(pattern):
+			i++
+			sb.WriteString(luceneEscaper.Replace(pattern[i : i+1]))
+		default:
+			sb.WriteString(luceneEscaper.Replace(pattern[i : i+1]))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToLucene(t *testing.T) {
+	scenarios := []struct {
+		input          string
+		expectedError  bool
+		expectedResult string
+	}{
+		{`a = b`, true, ``},
+		{`1 = a`, true, ``},
+		{`items[0] = 1`, true, ``},
+		{`a *= 1`, true, ``},
+		{`a ?!= 1`, true, ``},
+		{`a > null`, true, ``},
+		{`a ~ 1`, true, ``},
+		{`a = 1`, false, `+a:1`},
+		{`status = "active" && age > 18 && role != "guest"`, false, `+status:"active" +age:>18 -role:"guest"`},
+		{`a.b = 'say "hi"' && c <= -1.5 && d >= 2 && e < true`, false, `+a.b:"say \"hi\"" +c:<=\-1.5 +d:>=2 +e:<true`},
+		{`a = null && b != null`, false, `-a:* +b:*`},
+		{`a = 1 || b = 2 && c = 3 || d != 4`, false, `a:1 (+b:2 +c:3) (*:* -d:4)`},
+		{`a = 1 && (b = 2 || c != 3)`, false, `+a:1 +(b:2 (*:* -c:3))`},
+		{`status != "x"`, false, `*:* -status:"x"`},
+		{`age = -5`, false, `+age:\-5`},
+		{`age != -5`, false, `*:* -age:\-5`},
+		{`a in (-1, 2)`, false, `+(a:\-1 a:2)`},
+		{`a != 1 && b = null`, false, `*:* -a:1 -b:*`},
+		{`a = 1 && (b != 2 && c != 3)`, false, `+a:1 +(*:* -b:2 -c:3)`},
+		{`a != 1 && b != 2 || c = 3`, false, `(*:* -a:1 -b:2) c:3`},
+		{`a in (1, "x y") && b not in (2)`, false, `+(a:1 a:"x y") -(b:2)`},
+		{`a ~ "jo hn" && b !~ "x%:y" && c like "a_b" && d not ilike "50\%"`, false, `+a:*jo\ hn* -b:x*\:y +c:a?b -d:50%`},
+		{`tags ?= "x" && tags ?~ "y" && scores ?> 5`, false, `+tags:"x" +tags:*y* +scores:>5`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := ToLucene(exprs)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if result != s.expectedResult {
+				t.Fatalf("Expected %s, got %s", s.expectedResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package fexpr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errorCodes holds the stable machine-readable codes of the failure kinds.
+//
+// New codes must be only appended and the existing ones must never
+// be changed or reused.
+var errorCodes = []struct {
+	kind error
+	code string
+}{
+	{ErrEmpty, "FEXPR_E001"},
+	{ErrIncomplete, "FEXPR_E002"},
+	{ErrUnexpectedCharacter, "FEXPR_E003"},
+	{ErrInvalidIdentifier, "FEXPR_E004"},
+	{ErrInvalidNumber, "FEXPR_E005"},
+	{ErrUnterminatedText, "FEXPR_E006"},
+	{ErrInvalidEscape, "FEXPR_E007"},
+	{ErrUnterminatedGroup, "FEXPR_E008"},
+	{ErrInvalidComment, "FEXPR_E009"},
+	{ErrInvalidLiteral, "FEXPR_E010"},
+	{ErrInvalidSignOp, "FEXPR_E011"},
+	{ErrInvalidJoinOp, "FEXPR_E012"},
+	{ErrUnsupportedEncoding, "FEXPR_E013"},
+	{ErrMaxTextLength, "FEXPR_E014"},
+	{ErrMaxInputLength, "FEXPR_E015"},
+	{ErrUnexpectedToken, "FEXPR_E016"},
+	{ErrInvalidList, "FEXPR_E017"},
+	{ErrStrictMode, "FEXPR_E018"},
+	{ErrMaxDepth, "FEXPR_E019"},
+	{ErrMaxComplexity, "FEXPR_E020"},
+	{ErrRecursiveMacro, "FEXPR_E021"},
+	{ErrInvalidMacro, "FEXPR_E022"},
+	{ErrUnknownPlaceholder, "FEXPR_E023"},
+	{ErrInvalidPlaceholder, "FEXPR_E024"},
+	{ErrInvalidQuery, "FEXPR_E025"},
+	{ErrUnsupportedSignOp, "FEXPR_E026"},
+	{ErrUnsupportedJoinOp, "FEXPR_E027"},
+	{ErrUnsupportedItem, "FEXPR_E028"},
+	{ErrInvalidOperand, "FEXPR_E029"},
+	{ErrInvalidSExpr, "FEXPR_E030"},
+	{ErrInvalidProto, "FEXPR_E031"},
+	{ErrInvalidPlainGroup, "FEXPR_E032"},
+}
+
+// ErrorMessage represents the localizable parts of an error message.
+type ErrorMessage struct {
+	// Code is the stable machine-readable code of the error failure kind
+	// (eg. "FEXPR_E005" for ErrInvalidNumber or empty string if unknown).
+	Code string
+
+	// Template is the fmt format of the English error message
+	// (eg. `invalid number %q`) that could be used as translation key.
+	Template string
+
+	// Args are the Template arguments.
+	Args []interface{}
+}
+
+// messageError is implemented by the errors with localizable message.
+type messageError interface {
+	errorMessage() ErrorMessage
+}
+
+// ErrorCode returns the stable machine-readable code of the err
+// failure kind (see ErrorMessage.Code) or empty string if unknown.
+//
+// For wrapping errors the code of the outermost failure kind is returned
+// (eg. ErrInvalidMacro instead of the macro's own parse error).
+func ErrorCode(err error) string {
+	var msgErr messageError
+	if errors.As(err, &msgErr) {
+		if code := msgErr.errorMessage().Code; code != "" {
+			return code
+		}
+	}
+
+	return errorCode(err)
+}
+
+// errorCode returns the code of the first failure kind matching err.
+func errorCode(err error) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.kind) {
+			return c.code
+		}
+	}
+
+	return ""
+}
+
+// ErrorDetails returns the localizable message parts of err
+// (the positioned errors and the errors with dynamic message
+// have Template and Args, the sentinel errors only Template).
+//
+// Returns false for the errors that don't originate from this package.
+func ErrorDetails(err error) (ErrorMessage, bool) {
+	var msgErr messageError
+	if errors.As(err, &msgErr) {
+		return msgErr.errorMessage(), true
+	}
+
+	if code := errorCode(err); code != "" {
+		return ErrorMessage{Code: code, Template: err.Error()}, true
+	}
+
+	return ErrorMessage{}, false
+}
+
+// TranslateError renders the err message using the translated
+// templates keyed by their English Template (see ErrorDetails),
+// for example:
+//
+//	TranslateError(err, map[string]string{
+//	    "invalid number %q": "ungültige Zahl %q",
+//	})
+//
+// The wrapped error arguments are translated recursively.
+// The original error message is used for the errors without
+// translated template (and for the ones from other packages).
+func TranslateError(err error, templates map[string]string) string {
+	msg, ok := ErrorDetails(err)
+	if !ok {
+		return err.Error()
+	}
+
+	template, ok := templates[msg.Template]
+	if !ok {
+		template = msg.Template
+	}
+
+	args := make([]interface{}, len(msg.Args))
+	for i, arg := range msg.Args {
+		if argErr, ok := arg.(error); ok {
+			args[i] = TranslateError(argErr, templates)
+		} else {
+			args[i] = arg
+		}
+	}
+
+	if len(args) == 0 {
+		return template
+	}
+
+	// the %w verb is supported only by fmt.Errorf
+	return fmt.Sprintf(strings.Replace(template, "%w", "%v", -1), args...)
+}
@@ -0,0 +1,114 @@
+package fexpr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		opts     []ParseOption
+		expected string
+	}{
+		{``, nil, "FEXPR_E001"},
+		{`a =`, nil, "FEXPR_E002"},
+		{`a = 1.`, nil, "FEXPR_E005"},
+		{`a => 1`, nil, "FEXPR_E011"},
+		{`a = 1 & b = 2`, nil, "FEXPR_E012"},
+		{`a = 1 b`, nil, "FEXPR_E016"},
+		{`#m`, []ParseOption{Macro("m", "a =")}, "FEXPR_E022"},
+		{`a = b`, []ParseOption{OnExpr(func(g ExprGroup, span Span) error { return ErrStrictMode })}, "FEXPR_E018"},
+		{`a = b`, []ParseOption{OnExpr(func(g ExprGroup, span Span) error { return errors.New("test") })}, ""},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			_, err := Parse(s.input, s.opts...)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if code := ErrorCode(err); code != s.expected {
+				t.Fatalf("Expected code %q, got %q", s.expected, code)
+			}
+		})
+	}
+}
+
+func TestErrorCodesUnique(t *testing.T) {
+	codes := map[string]struct{}{}
+
+	for _, c := range errorCodes {
+		if _, ok := codes[c.code]; ok {
+			t.Fatalf("Duplicated error code %q", c.code)
+		}
+		codes[c.code] = struct{}{}
+	}
+}
+
+func TestErrorDetails(t *testing.T) {
+	_, err := Parse(`a = 1.`)
+
+	msg, ok := ErrorDetails(err)
+	if !ok {
+		t.Fatalf("Expected error details for %v", err)
+	}
+
+	expected := `{FEXPR_E005 invalid number %q [1.]}`
+	if msgPrint := fmt.Sprintf("%v", msg); msgPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, msgPrint)
+	}
+
+	if _, ok := ErrorDetails(errors.New("test")); ok {
+		t.Fatal("Expected no details for external error")
+	}
+}
+
+func TestTranslateError(t *testing.T) {
+	templates := map[string]string{
+		"invalid number %q":                         "ungültige Zahl %q",
+		"invalid macro %q: %w":                      "ungültiges Makro %q: %w",
+		"invalid %s operator %q (did you mean %q?)": "ungültiger %s-Operator %q (meinten Sie %q?)",
+		"%v (at %d:%d)":                             "%v (bei %d:%d)",
+		"unknown field":                             "unbekanntes Feld",
+		ErrIncomplete.Error():                       "unvollständiger Filter",
+	}
+
+	hook := OnExpr(func(g ExprGroup, span Span) error {
+		return errorf(ErrInvalidIdentifier, "unknown field")
+	})
+
+	scenarios := []struct {
+		input    string
+		opts     []ParseOption
+		expected string
+	}{
+		{`a = 1.`, nil, `ungültige Zahl "1."`},
+		{`a = 1 b`, nil, `expected && or ||, got "b" (identifier)`},
+		{`a =`, nil, `unvollständiger Filter`},
+		{`a => 1`, nil, `ungültiger sign-Operator "=>" (meinten Sie ">="?)`},
+		{`#m`, []ParseOption{Macro("m", "a = 1.")}, `ungültiges Makro "#m": ungültige Zahl "1."`},
+		{`a = 1`, []ParseOption{hook}, `unbekanntes Feld (bei 0:5)`},
+		{`a = 1`, []ParseOption{OnExpr(func(g ExprGroup, span Span) error { return errors.New("test") })}, `test (bei 0:5)`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			_, err := Parse(s.input, s.opts...)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if result := TranslateError(err, templates); result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+
+			// without translations the original message should be used
+			if result := TranslateError(err, nil); result != err.Error() {
+				t.Fatalf("Expected the original message %q, got %q", err.Error(), result)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package fexpr
+
+// negatedSignOps holds the logical negation of each negatable sign operator.
+var negatedSignOps = map[SignOp]SignOp{
+	SignEq:    SignNeq,
+	SignNeq:   SignEq,
+	SignLike:  SignNlike,
+	SignNlike: SignLike,
+	SignLt:    SignGte,
+	SignGte:   SignLt,
+	SignGt:    SignLte,
+	SignLte:   SignGt,
+	// !(any element matches) => all elements don't match
+	SignAnyEq:    SignAllNeq,
+	SignAnyNeq:   SignAllEq,
+	SignAnyLike:  SignAllNlike,
+	SignAnyNlike: SignAllLike,
+	SignAnyLt:    SignAllGte,
+	SignAnyLte:   SignAllGt,
+	SignAnyGt:    SignAllLte,
+	SignAnyGte:   SignAllLt,
+	SignAllEq:    SignAnyNeq,
+	SignAllNeq:   SignAnyEq,
+	SignAllLike:  SignAnyNlike,
+	SignAllNlike: SignAnyLike,
+	SignAllLt:    SignAnyGte,
+	SignAllLte:   SignAnyGt,
+	SignAllGt:    SignAnyLte,
+	SignAllGte:   SignAnyLt,
+
+	SignIn:    SignNotIn,
+	SignNotIn: SignIn,
+
+	SignSQLLike:   SignSQLNlike,
+	SignSQLNlike:  SignSQLLike,
+	SignSQLIlike:  SignSQLNilike,
+	SignSQLNilike: SignSQLIlike,
+}
+
+// Not returns the logical negation of the provided parsed filter.
+//
+// The negation is pushed down to the individual expressions using the
+// De Morgan's laws (aka. `!(a && b)` becomes `!a || !b`) and each
+// expression sign operator is replaced with its opposite (eg. `=` with `!=`, `>` with `<=`).
+//
+// The array/any operators are negated to their array/all
+// counterparts and vice versa (eg. `?=` with `*!=`).
+//
+// Note that `&&` has higher precedence than `||` and an error is
+// returned if the filter is empty, contains a sign operator
+// without an opposite or a custom join operator (see RegisterJoinOp).
+func Not(exprs []ExprGroup) ([]ExprGroup, error) {
+	if len(exprs) == 0 {
+		return nil, errorf(ErrEmpty, "cannot negate an empty filter expression")
+	}
+
+	for _, g := range exprs[1:] {
+		if g.Join != JoinAnd && g.Join != JoinOr {
+			return nil, errorf(ErrUnsupportedJoinOp, "join operator %q cannot be negated", g.Join)
+		}
+	}
+
+	result := []ExprGroup{}
+
+	// !(c1 || c2) => !c1 && !c2
+	for _, conjunction := range splitOr(exprs) {
+		negated := make([]ExprGroup, 0, len(conjunction))
+
+		// !(a && b) => !a || !b
+		for _, g := range conjunction {
+			item, err := negateItem(g.Item)
+			if err != nil {
+				return nil, err
+			}
+
+			join := JoinOr
+			if len(negated) == 0 {
+				join = JoinAnd
+			}
+
+			negated = append(negated, ExprGroup{Join: join, Item: item})
+		}
+
+		group := ExprGroup{Join: JoinAnd, Item: negated}
+		if len(negated) == 1 {
+			group.Item = negated[0].Item
+		}
+
+		result = append(result, group)
+	}
+
+	return result, nil
+}
+
+// negateItem returns the logical negation of a single ExprGroup.Item.
+func negateItem(item interface{}) (interface{}, error) {
+	switch v := item.(type) {
+	case Expr:
+		op, ok := negatedSignOps[v.Op]
+		if !ok {
+			return nil, errorf(ErrUnsupportedSignOp, "sign operator %q cannot be negated", v.Op)
+		}
+
+		v.Op = op
+
+		return v, nil
+	case []ExprGroup:
+		return Not(v)
+	default:
+		return nil, errorf(ErrUnsupportedItem, "unsupported expression group item %T", item)
+	}
+}
+
+// splitOr splits the provided groups into `||` separated chains
+// of `&&` joined groups (aka. the operands of the top-level `||`).
+func splitOr(exprs []ExprGroup) [][]ExprGroup {
+	result := [][]ExprGroup{}
+
+	for i, g := range exprs {
+		if i == 0 || g.Join == JoinOr {
+			result = append(result, []ExprGroup{})
+		}
+
+		last := len(result) - 1
+		result[last] = append(result[last], g)
+	}
+
+	return result
+}
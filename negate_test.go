@@ -0,0 +1,83 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNot(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{`a ?= 1 || a *= 1`, false, `[{&& {{identifier a} *!= {number 1}}} {&& {{identifier a} ?!= {number 1}}}]`},
+		{`a ?!~ 1 && a *~ 1`, false, `[{&& [{&& {{identifier a} *~ {number 1}}} {|| {{identifier a} ?!~ {number 1}}}]}]`},
+		{`a ?> 1 || a *<= 1`, false, `[{&& {{identifier a} *<= {number 1}}} {&& {{identifier a} ?> {number 1}}}]`},
+		{`a ?>= 1 || a *< 1`, false, `[{&& {{identifier a} *< {number 1}}} {&& {{identifier a} ?>= {number 1}}}]`},
+		{`a = 1`, false, `[{&& {{identifier a} != {number 1}}}]`},
+		{`a != 1`, false, `[{&& {{identifier a} = {number 1}}}]`},
+		{`a ~ 1`, false, `[{&& {{identifier a} !~ {number 1}}}]`},
+		{`a !~ 1`, false, `[{&& {{identifier a} ~ {number 1}}}]`},
+		{`a > 1`, false, `[{&& {{identifier a} <= {number 1}}}]`},
+		{`a >= 1`, false, `[{&& {{identifier a} < {number 1}}}]`},
+		{`a < 1`, false, `[{&& {{identifier a} >= {number 1}}}]`},
+		{`a <= 1`, false, `[{&& {{identifier a} > {number 1}}}]`},
+		{`a in (1, 2)`, false, `[{&& {{identifier a} not in {list 1, 2}}}]`},
+		{`a not in ("x")`, false, `[{&& {{identifier a} in {list "x"}}}]`},
+		{`a like "x%" || a not ilike "y"`, false, `[{&& {{identifier a} not like {text x%}}} {&& {{identifier a} ilike {text y}}}]`},
+		{`a = 1 && b = 2`, false, `[{&& [{&& {{identifier a} != {number 1}}} {|| {{identifier b} != {number 2}}}]}]`},
+		{`a = 1 || b = 2`, false, `[{&& {{identifier a} != {number 1}}} {&& {{identifier b} != {number 2}}}]`},
+		{
+			`a = 1 && b = 2 || c = 3`,
+			false,
+			`[{&& [{&& {{identifier a} != {number 1}}} {|| {{identifier b} != {number 2}}}]} {&& {{identifier c} != {number 3}}}]`,
+		},
+		{
+			`a = 1 && (b = 2 || c = 3)`,
+			false,
+			`[{&& [{&& {{identifier a} != {number 1}}} {|| [{&& {{identifier b} != {number 2}}} {&& {{identifier c} != {number 3}}}]}]}]`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v, err := Not(exprs)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestNotEmpty(t *testing.T) {
+	if _, err := Not(nil); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestNotUnsupportedOperator(t *testing.T) {
+	exprs := []ExprGroup{{Join: JoinAnd, Item: Expr{
+		Left:  Token{Type: TokenIdentifier, Literal: "a"},
+		Op:    SignOp("unknown"),
+		Right: Token{Type: TokenNumber, Literal: "1"},
+	}}}
+
+	if _, err := Not(exprs); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
@@ -0,0 +1,186 @@
+package fexpr
+
+// maxNormalFormTerms is the maximum number of the ToDNF terms
+// and ToCNF clauses (the conversion could grow exponentially).
+const maxNormalFormTerms = 10000
+
+// ToDNF converts the provided parsed filter into its disjunctive normal
+// form, aka. a flat `||` chain of `&&` joined expressions without nested
+// groups (eg. `a = 1 && (b = 2 || c = 3)` becomes `a = 1 && b = 2 || a = 1 && c = 3`).
+//
+// The duplicated expressions of each term are removed.
+//
+// An error is returned if the result would have more than 10000 terms.
+func ToDNF(exprs []ExprGroup) ([]ExprGroup, error) {
+	terms, err := dnfTerms(exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []ExprGroup{}
+
+	for i, term := range terms {
+		for j, expr := range term {
+			join := JoinAnd
+			if i > 0 && j == 0 {
+				join = JoinOr
+			}
+
+			result = append(result, ExprGroup{Join: join, Item: expr})
+		}
+	}
+
+	return result, nil
+}
+
+// ToCNF converts the provided parsed filter into its conjunctive normal
+// form, aka. a `&&` chain of `||` joined expression groups (eg.
+// `a = 1 || b = 2 && c = 3` becomes `(a = 1 || b = 2) && (a = 1 || c = 3)`).
+//
+// The single expression clauses are not wrapped in a group and
+// the duplicated expressions of each clause are removed.
+//
+// An error is returned if the result would have more than 10000 clauses.
+func ToCNF(exprs []ExprGroup) ([]ExprGroup, error) {
+	clauses, err := cnfClauses(exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []ExprGroup{}
+
+	for _, clause := range clauses {
+		if len(clause) == 1 {
+			result = append(result, ExprGroup{Join: JoinAnd, Item: clause[0]})
+			continue
+		}
+
+		group := make([]ExprGroup, len(clause))
+		for i, expr := range clause {
+			join := JoinOr
+			if i == 0 {
+				join = JoinAnd
+			}
+
+			group[i] = ExprGroup{Join: join, Item: expr}
+		}
+
+		result = append(result, ExprGroup{Join: JoinAnd, Item: group})
+	}
+
+	return result, nil
+}
+
+// dnfTerms returns the DNF terms (aka. the `&&` joined expressions) of groups.
+func dnfTerms(groups []ExprGroup) ([][]Expr, error) {
+	result := [][]Expr{}
+
+	for _, conjunction := range splitOr(groups) {
+		terms := [][]Expr{{}}
+
+		for _, g := range conjunction {
+			itemTerms, err := itemNormalForm(g.Item, dnfTerms)
+			if err != nil {
+				return nil, err
+			}
+
+			if terms, err = crossNormalForm(terms, itemTerms); err != nil {
+				return nil, err
+			}
+		}
+
+		result = append(result, terms...)
+		if len(result) > maxNormalFormTerms {
+			return nil, normalFormLimitError(len(result))
+		}
+	}
+
+	return result, nil
+}
+
+// cnfClauses returns the CNF clauses (aka. the `||` joined expressions) of groups.
+func cnfClauses(groups []ExprGroup) ([][]Expr, error) {
+	var result [][]Expr
+
+	for _, conjunction := range splitOr(groups) {
+		clauses := [][]Expr{}
+
+		for _, g := range conjunction {
+			itemClauses, err := itemNormalForm(g.Item, cnfClauses)
+			if err != nil {
+				return nil, err
+			}
+
+			clauses = append(clauses, itemClauses...)
+			if len(clauses) > maxNormalFormTerms {
+				return nil, normalFormLimitError(len(clauses))
+			}
+		}
+
+		if result == nil {
+			result = clauses
+			continue
+		}
+
+		// (a && b) || (c && d) => (a || c) && (a || d) && (b || c) && (b || d)
+		var err error
+		if result, err = crossNormalForm(result, clauses); err != nil {
+			return nil, err
+		}
+	}
+
+	if result == nil {
+		result = [][]Expr{}
+	}
+
+	return result, nil
+}
+
+// itemNormalForm returns the normal form parts of a single ExprGroup.Item
+// using convert for the nested groups.
+func itemNormalForm(item interface{}, convert func([]ExprGroup) ([][]Expr, error)) ([][]Expr, error) {
+	switch v := item.(type) {
+	case Expr:
+		return [][]Expr{{v}}, nil
+	case []ExprGroup:
+		return convert(v)
+	default:
+		return nil, errorf(ErrUnsupportedItem, "unsupported expression group item %T", item)
+	}
+}
+
+// crossNormalForm returns the concatenations of each a and b parts pair.
+func crossNormalForm(a [][]Expr, b [][]Expr) ([][]Expr, error) {
+	if len(a)*len(b) > maxNormalFormTerms {
+		return nil, normalFormLimitError(len(a) * len(b))
+	}
+
+	result := make([][]Expr, 0, len(a)*len(b))
+
+	for _, x := range a {
+		for _, y := range b {
+			part := make([]Expr, 0, len(x)+len(y))
+			seen := map[string]struct{}{}
+
+			for _, expr := range append(append([]Expr{}, x...), y...) {
+				key := itemString(expr)
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+
+				part = append(part, expr)
+			}
+
+			result = append(result, part)
+		}
+	}
+
+	return result, nil
+}
+
+// normalFormLimitError returns the maxNormalFormTerms exceeded error
+// with the actual number of the normal form terms.
+func normalFormLimitError(actual int) error {
+	return newLimitError(ErrMaxComplexity, "the normal form exceeds the maximum allowed %d terms", maxNormalFormTerms, actual)
+}
@@ -0,0 +1,112 @@
+package fexpr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestToDNF(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1`, `a = 1`},
+		{`a = 1 && b = 2 || c = 3`, `a = 1 && b = 2 || c = 3`},
+		{`a = 1 && (b = 2 || c = 3)`, `a = 1 && b = 2 || a = 1 && c = 3`},
+		{`(a = 1 || b = 2) && (c = 3 || d = 4)`, `a = 1 && c = 3 || a = 1 && d = 4 || b = 2 && c = 3 || b = 2 && d = 4`},
+		{`((a = 1))`, `a = 1`},
+		{`a = 1 && (a = 1 || b = 2)`, `a = 1 || a = 1 && b = 2`},
+		{`x = 1 || (a = 1 && (b = 2 || c = 3))`, `x = 1 || a = 1 && b = 2 || a = 1 && c = 3`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := ToDNF(exprs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := Stringify(result); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestToCNF(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1`, `a = 1`},
+		{`a = 1 && (b = 2 || c = 3)`, `a = 1 && (b = 2 || c = 3)`},
+		{`a = 1 || b = 2 && c = 3`, `(a = 1 || b = 2) && (a = 1 || c = 3)`},
+		{`a = 1 && b = 2 || c = 3 && d = 4`, `(a = 1 || c = 3) && (a = 1 || d = 4) && (b = 2 || c = 3) && (b = 2 || d = 4)`},
+		{`(a = 1 || b = 2)`, `(a = 1 || b = 2)`},
+		{`a = 1 || a = 1 && b = 2`, `a = 1 && (a = 1 || b = 2)`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := ToCNF(exprs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := Stringify(result); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestNormalFormEmpty(t *testing.T) {
+	if v, err := ToDNF(nil); err != nil || len(v) != 0 {
+		t.Fatalf("Expected empty DNF, got %v (%v)", v, err)
+	}
+
+	if v, err := ToCNF(nil); err != nil || len(v) != 0 {
+		t.Fatalf("Expected empty CNF, got %v (%v)", v, err)
+	}
+}
+
+func TestNormalFormLimit(t *testing.T) {
+	// 2^14 terms/clauses
+	parts := make([]string, 14)
+	for i := range parts {
+		parts[i] = fmt.Sprintf("(a%d = 1 || b%d = 1)", i, i)
+	}
+
+	exprs, err := Parse(strings.Join(parts, " && "))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ToDNF(exprs); err == nil {
+		t.Fatal("Expected DNF limit error, got nil")
+	}
+
+	if _, err := ToCNF(exprs); err != nil {
+		t.Fatalf("Did not expect CNF error, got %v", err)
+	}
+
+	negated, err := Not(exprs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ToCNF(negated); err == nil {
+		t.Fatal("Expected CNF limit error, got nil")
+	}
+}
@@ -0,0 +1,152 @@
+package fexpr
+
+import (
+	"sort"
+	"strings"
+)
+
+// Normalize returns the canonical form of the provided parsed filter
+// so that equivalent filters (eg. `b = 2 && a = 1` and `(a = 1 && b = 2 && a = 1)`)
+// could be compared or hashed by their Stringify representation.
+//
+// The normalization:
+//   - sorts the `&&` operands and the `||` operands in a stable order
+//   - removes duplicated conditions
+//   - inlines the redundant nested groups
+//
+// Note that `&&` has higher precedence than `||`.
+func Normalize(exprs []ExprGroup) []ExprGroup {
+	type disjunct struct {
+		key   string
+		items []interface{}
+	}
+
+	disjuncts := []disjunct{}
+	seenDisjuncts := map[string]struct{}{}
+
+	for _, conjunction := range splitOr(exprs) {
+		for _, items := range normalizeConjunction(conjunction) {
+			items, keys := sortUniqueItems(items)
+			if len(items) == 0 {
+				continue
+			}
+
+			key := strings.Join(keys, " && ")
+			if _, ok := seenDisjuncts[key]; ok {
+				continue
+			}
+			seenDisjuncts[key] = struct{}{}
+
+			disjuncts = append(disjuncts, disjunct{key: key, items: items})
+		}
+	}
+
+	sort.SliceStable(disjuncts, func(i, j int) bool {
+		return disjuncts[i].key < disjuncts[j].key
+	})
+
+	result := []ExprGroup{}
+
+	for i, d := range disjuncts {
+		for j, item := range d.items {
+			join := JoinAnd
+			if i > 0 && j == 0 {
+				join = JoinOr
+			}
+
+			result = append(result, ExprGroup{Join: join, Item: item})
+		}
+	}
+
+	return result
+}
+
+// normalizeConjunction normalizes the nested groups of a single chain
+// of `&&` joined groups and returns the resulting items.
+//
+// Multiple item chains (aka. disjuncts) are returned if the conjunction
+// consists only of a single group that contains `||` operands.
+func normalizeConjunction(conjunction []ExprGroup) [][]interface{} {
+	items := []interface{}{}
+
+	for _, g := range conjunction {
+		if unary, ok := g.Item.(UnaryExpr); ok {
+			if unary, ok = mapUnaryOperand(unary, Normalize); ok {
+				items = append(items, unary)
+			}
+			continue
+		}
+
+		nested, ok := g.Item.([]ExprGroup)
+		if !ok {
+			items = append(items, g.Item)
+			continue
+		}
+
+		normalized := Normalize(nested)
+		if len(normalized) == 0 {
+			continue
+		}
+
+		parts := splitOr(normalized)
+
+		// a && (b && c) => a && b && c
+		if len(parts) == 1 {
+			for _, p := range parts[0] {
+				items = append(items, p.Item)
+			}
+			continue
+		}
+
+		// a || (b || c) => a || b || c
+		if len(conjunction) == 1 {
+			result := make([][]interface{}, len(parts))
+			for i, part := range parts {
+				for _, p := range part {
+					result[i] = append(result[i], p.Item)
+				}
+			}
+			return result
+		}
+
+		items = append(items, normalized)
+	}
+
+	return [][]interface{}{items}
+}
+
+// sortUniqueItems sorts and removes the duplicated items by their
+// text representation and returns the resulting items with their keys.
+func sortUniqueItems(items []interface{}) ([]interface{}, []string) {
+	keys := make([]string, 0, len(items))
+	unique := make([]interface{}, 0, len(items))
+	seen := map[string]struct{}{}
+
+	for _, item := range items {
+		key := itemString(item)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		keys = append(keys, key)
+		unique = append(unique, item)
+	}
+
+	sort.Sort(keyedItems{keys, unique})
+
+	return unique, keys
+}
+
+// keyedItems implements sort.Interface to sort items by their keys.
+type keyedItems struct {
+	keys  []string
+	items []interface{}
+}
+
+func (k keyedItems) Len() int           { return len(k.keys) }
+func (k keyedItems) Less(i, j int) bool { return k.keys[i] < k.keys[j] }
+func (k keyedItems) Swap(i, j int) {
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+	k.items[i], k.items[j] = k.items[j], k.items[i]
+}
@@ -0,0 +1,40 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1`, `a = 1`},
+		{`(a = 1)`, `a = 1`},
+		{`((a = 1))`, `a = 1`},
+		{`b = 2 && a = 1`, `a = 1 && b = 2`},
+		{`b = 2   &&   a = 1 && b = 2`, `a = 1 && b = 2`},
+		{`a = 1 && (b = 2 && a = 1)`, `a = 1 && b = 2`},
+		{`b = 2 || a = 1 || b = 2`, `a = 1 || b = 2`},
+		{`c = 3 || (b = 2 || a = 1)`, `a = 1 || b = 2 || c = 3`},
+		{`c = 3 && b = 2 || a = 1 && d = 4`, `a = 1 && d = 4 || b = 2 && c = 3`},
+		{`b = 2 && a = 1 || a = 1 && b = 2`, `a = 1 && b = 2`},
+		{`z = 1 && (c = 3 || b = 2)`, `(b = 2 || c = 3) && z = 1`},
+		{`(c = 3 || b = 2) && z = 1 && (b = 2 || c = 3)`, `(b = 2 || c = 3) && z = 1`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := Stringify(Normalize(exprs))
+			if result != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, result)
+			}
+		})
+	}
+}
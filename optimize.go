@@ -0,0 +1,253 @@
+package fexpr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Optimize returns a simplified copy of the provided parsed filter
+// with the overlapping conditions on the same identifier merged:
+//   - the numeric bounds of `&&` operands are reduced to the strictest
+//     ones (eg. `age > 5 && age > 10` becomes `age > 10`)
+//   - the numeric bounds of `||` operands are reduced to the loosest
+//     ones (eg. `age > 5 || age > 10` becomes `age > 5`)
+//   - the `||` equalities are merged in a list (eg. `a = 1 || a in (2, 3)`
+//     becomes `a in (1, 2, 3)`)
+//   - the `&&` inequalities are merged in a list (eg. `a != 1 && a != 2`
+//     becomes `a not in (1, 2)`)
+//
+// The operands of the mirrorable expressions are reordered so that
+// `5 < age` is treated the same as `age > 5`. The array/any and
+// array/all operators are not merged.
+func Optimize(exprs []ExprGroup) []ExprGroup {
+	disjuncts := [][]interface{}{}
+
+	for _, conjunction := range splitOr(Simplify(orientExprs(exprs))) {
+		items := make([]interface{}, 0, len(conjunction))
+
+		for _, g := range conjunction {
+			if nested, ok := g.Item.([]ExprGroup); ok {
+				nested = Optimize(nested)
+				if len(nested) == 0 {
+					continue
+				}
+				items = append(items, nested)
+				continue
+			}
+
+			if unary, ok := g.Item.(UnaryExpr); ok {
+				if unary, ok = mapUnaryOperand(unary, Optimize); ok {
+					items = append(items, unary)
+				}
+				continue
+			}
+
+			items = append(items, g.Item)
+		}
+
+		items = mergeBounds(items, true)
+		items = mergeLists(items, SignNeq, SignNotIn)
+
+		if len(items) > 0 {
+			disjuncts = append(disjuncts, items)
+		}
+	}
+
+	disjuncts = mergeDisjuncts(disjuncts)
+
+	result := []ExprGroup{}
+
+	for i, items := range disjuncts {
+		for j, item := range items {
+			join := JoinAnd
+			if i > 0 && j == 0 {
+				join = JoinOr
+			}
+
+			result = append(result, ExprGroup{Join: join, Item: item})
+		}
+	}
+
+	return Simplify(result)
+}
+
+// conjunctionItems represents a multiple items disjunct
+// that is left unchanged by the merge functions.
+type conjunctionItems []interface{}
+
+// mergeDisjuncts merges the single expression disjuncts
+// preserving the position of the first merged disjunct.
+func mergeDisjuncts(disjuncts [][]interface{}) [][]interface{} {
+	items := make([]interface{}, len(disjuncts))
+
+	for i, d := range disjuncts {
+		if len(d) == 1 {
+			items[i] = d[0]
+		} else {
+			items[i] = conjunctionItems(d)
+		}
+	}
+
+	items = mergeLists(mergeBounds(items, false), SignEq, SignIn)
+
+	result := make([][]interface{}, len(items))
+
+	for i, item := range items {
+		if d, ok := item.(conjunctionItems); ok {
+			result[i] = d
+		} else {
+			result[i] = []interface{}{item}
+		}
+	}
+
+	return result
+}
+
+// numericBound returns the literal value of a numeric bound expression
+// (eg. `a > 1`) and whether it is a lower or upper bound.
+func numericBound(item interface{}) (expr Expr, value float64, lower bool, ok bool) {
+	expr, ok = item.(Expr)
+	if !ok || expr.Left.Type != TokenIdentifier || expr.Right.Type != TokenNumber {
+		return expr, 0, false, false
+	}
+
+	switch expr.Op {
+	case SignGt, SignGte:
+		lower = true
+	case SignLt, SignLte:
+		lower = false
+	default:
+		return expr, 0, false, false
+	}
+
+	value, err := strconv.ParseFloat(expr.Right.Literal, 64)
+	if err != nil {
+		return expr, 0, false, false
+	}
+
+	return expr, value, lower, true
+}
+
+// mergeBounds reduces the numeric bounds of the same identifier
+// to the strictest (or loosest if strict is not set) ones.
+func mergeBounds(items []interface{}, strict bool) []interface{} {
+	type boundKey struct {
+		left  string
+		lower bool
+	}
+
+	result := make([]interface{}, 0, len(items))
+	indexes := map[boundKey]int{}
+	values := map[boundKey]float64{}
+
+	for _, item := range items {
+		expr, value, lower, ok := numericBound(item)
+		if !ok {
+			result = append(result, item)
+			continue
+		}
+
+		key := boundKey{left: expr.Left.Literal, lower: lower}
+
+		i, exists := indexes[key]
+		if !exists {
+			indexes[key] = len(result)
+			values[key] = value
+			result = append(result, item)
+			continue
+		}
+
+		current := result[i].(Expr)
+		if isStricterBound(expr, value, current, values[key], lower) == strict {
+			result[i] = expr
+			values[key] = value
+		}
+	}
+
+	return result
+}
+
+// isStricterBound checks if the bound a is stricter than the bound b.
+func isStricterBound(a Expr, aValue float64, b Expr, bValue float64, lower bool) bool {
+	if aValue == bValue {
+		// the exclusive operators are stricter
+		return a.Op != b.Op && (a.Op == SignGt || a.Op == SignLt)
+	}
+
+	if lower {
+		return aValue > bValue
+	}
+
+	return aValue < bValue
+}
+
+// mergeLists merges the op expressions and listOp lists with the
+// same identifier left operand and literal right operands into a single
+// listOp expression placed at the position of the first merged expression.
+func mergeLists(items []interface{}, op SignOp, listOp SignOp) []interface{} {
+	type listEntry struct {
+		index  int
+		values []Token
+		seen   map[string]struct{}
+		merged int // number of merged expressions
+	}
+
+	result := make([]interface{}, 0, len(items))
+	entries := map[string]*listEntry{}
+
+	for _, item := range items {
+		expr, isExpr := item.(Expr)
+
+		var values []Token
+		switch {
+		case !isExpr || expr.Left.Type != TokenIdentifier:
+		case expr.Op == op && (expr.Right.Type == TokenNumber || expr.Right.Type == TokenText):
+			values = []Token{expr.Right}
+		case expr.Op == listOp && expr.Right.Type == TokenList:
+			values, _ = SplitList(expr.Right.Literal)
+		}
+
+		if len(values) == 0 {
+			result = append(result, item)
+			continue
+		}
+
+		entry, ok := entries[expr.Left.Literal]
+		if !ok {
+			entry = &listEntry{index: len(result), seen: map[string]struct{}{}}
+			entries[expr.Left.Literal] = entry
+			result = append(result, item)
+		}
+
+		for _, v := range values {
+			key := tokenString(v)
+			if _, ok := entry.seen[key]; ok {
+				continue
+			}
+			entry.seen[key] = struct{}{}
+			entry.values = append(entry.values, v)
+		}
+
+		entry.merged++
+	}
+
+	// replace the first expressions with the merged lists
+	for left, entry := range entries {
+		if entry.merged < 2 {
+			continue
+		}
+
+		literals := make([]string, len(entry.values))
+		for i, v := range entry.values {
+			literals[i] = tokenString(v)
+		}
+
+		result[entry.index] = Expr{
+			Left:  Token{Type: TokenIdentifier, Literal: left},
+			Op:    listOp,
+			Right: Token{Type: TokenList, Literal: strings.Join(literals, ", ")},
+		}
+	}
+
+	return result
+}
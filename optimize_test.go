@@ -0,0 +1,58 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestOptimize(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1`, `a = 1`},
+		{`age > 5 && age > 10`, `age > 10`},
+		{`age > 10 && age > 5`, `age > 10`},
+		{`age >= 10 && age > 10`, `age > 10`},
+		{`age > 10 && age >= 10`, `age > 10`},
+		{`age < 5 && b = 1 && age <= 3 && 2 > age`, `age < 2 && b = 1`},
+		{`age > 5 && age < 10`, `age > 5 && age < 10`},
+		{`age > 5 || age > 10`, `age > 5`},
+		{`age > 10 || age >= 10`, `age >= 10`},
+		{`age < 5 || age < 10 || b = 1`, `age < 10 || b = 1`},
+		{`5 < age || 10 < age`, `age > 5`},
+		{`a = 1 || a = 2`, `a in (1, 2)`},
+		{`a = 1 || b = 1 || a = "x" || a in (1, 3)`, `a in (1, "x", 3) || b = 1`},
+		{`a in (1, 2) || a in (3)`, `a in (1, 2, 3)`},
+		{`a = 1 || (a = 2 && b = 3) || a = 4`, `a in (1, 4) || a = 2 && b = 3`},
+		{`a != 1 && a != "2"`, `a not in (1, "2")`},
+		{`a = 1 && a = 2`, `a = 1 && a = 2`},
+		{`a != 1 || a != 2`, `a != 1 || a != 2`},
+		{`a = b || a = c`, `a = b || a = c`},
+		{`a ?= 1 || a ?= 2`, `a ?= 1 || a ?= 2`},
+		{`a ?> 1 && a ?> 2`, `a ?> 1 && a ?> 2`},
+		{`a > "x" && a > "y"`, `a > "x" && a > "y"`},
+		{`(a > 1 && a > 2) || c = 1`, `a > 2 || c = 1`},
+		{`x = 1 && (a = 1 || a = 2)`, `x = 1 && a in (1, 2)`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := Optimize(exprs)
+
+			if v := Stringify(result); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+
+			// the result must be a valid filter
+			if _, err := Parse(Stringify(result)); err != nil {
+				t.Fatalf("Expected valid filter, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package fexpr
+
+import "strings"
+
+// ParseOption defines a single Parse configuration option.
+type ParseOption func(p *parser)
+
+// parser holds the Parse configuration options and state.
+type parser struct {
+	// macros holds the registered macros filters keyed by their "#name" identifier
+	macros map[string]string
+
+	// expanding holds the currently expanding macros (used to detect recursion)
+	expanding map[string]struct{}
+
+	// placeholders resolves the `@` prefixed identifier operands
+	placeholders PlaceholderResolver
+
+	// features holds the enabled opt-in syntax features (see EnableFeatures)
+	features Features
+
+	// comments is the Comments option destination
+	comments *[]ExprComment
+
+	// collectedComments holds the comments collected during the parsing
+	collectedComments []ExprComment
+
+	// path is the index path of the currently parsed nested group
+	path []int
+
+	// strict disables the lenient syntax (comments, operator aliases, etc.)
+	strict bool
+
+	// scannerOpts holds the options of the parser's text scanners
+	scannerOpts []ScannerOption
+
+	// maxComplexity is the MaxComplexity option limit (0 - no limit)
+	maxComplexity int
+
+	// complexity is the parsed top-level groups complexity total
+	complexity int
+
+	// maxDepth is the maximum allowed groups and macros nesting depth
+	maxDepth int
+
+	// allowEmpty enables parsing blank input to an empty result
+	allowEmpty bool
+
+	// exprHooks holds the registered OnExpr callbacks
+	exprHooks []ExprHook
+
+	// warnings is the Warnings option destination
+	warnings *[]Warning
+
+	// collectedWarnings holds the warnings collected during the parsing
+	collectedWarnings []Warning
+
+	// offset is the byte offset of the currently parsed nested group content
+	offset int
+}
+
+// defaultMaxDepth is the default MaxDepth option limit.
+const defaultMaxDepth = 1000
+
+// newParser creates a new parser with the specified options applied.
+func newParser(opts []ParseOption) *parser {
+	p := &parser{maxDepth: defaultMaxDepth}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// canParseSimple reports whether the single expression
+// fast path could be used with the current parser options.
+func (p *parser) canParseSimple() bool {
+	return len(p.macros) == 0 && p.placeholders == nil && p.features == 0 && len(p.exprHooks) == 0 && p.warnings == nil && p.comments == nil && !p.strict && len(p.scannerOpts) == 0 && p.maxComplexity <= 0
+}
+
+// finish stores the parser's collected state into the options destinations
+// (should be called only after successful parse).
+func (p *parser) finish() {
+	if p.comments != nil {
+		*p.comments = p.collectedComments
+	}
+
+	if p.warnings != nil {
+		*p.warnings = p.collectedWarnings
+	}
+}
+
+// Macro registers a reusable named filter fragment that could be
+// referenced as a standalone condition in the parsed text with
+// the `#name` identifier (eg. `Macro("mine", "owner = @request.auth.id")`
+// allows writing `#mine && status = "active"`).
+//
+// The referenced macros are expanded at parse time into a nested group.
+// Macros could reference other macros but not themselves.
+func Macro(name string, filter string) ParseOption {
+	return func(p *parser) {
+		if p.macros == nil {
+			p.macros = map[string]string{}
+		}
+
+		p.macros["#"+strings.TrimPrefix(name, "#")] = filter
+	}
+}
+
+// isMacro checks if t is a registered macro identifier.
+func (p *parser) isMacro(t Token) bool {
+	if t.Type != TokenIdentifier {
+		return false
+	}
+
+	_, ok := p.macros[t.Literal]
+
+	return ok
+}
+
+// expandMacro parses and returns the registered macro filter.
+func (p *parser) expandMacro(name string) ([]ExprGroup, error) {
+	if _, ok := p.expanding[name]; ok {
+		return nil, errorf(ErrRecursiveMacro, "recursive macro %q", name)
+	}
+
+	if p.expanding == nil {
+		p.expanding = map[string]struct{}{}
+	}
+	p.expanding[name] = struct{}{}
+	defer delete(p.expanding, name)
+
+	result, err := p.parse(p.macros[name])
+	if err != nil {
+		return nil, errorf(ErrInvalidMacro, "invalid macro %q: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// Keywords enables the case-insensitive SQL-like `and`, `or` and `not`
+// keyword operators as alternatives to `&&`, `||` and the negation
+// of the following condition (eg. `not (a = 1 or b = 2) and c = 3`).
+//
+// The negation is applied at parse time with Not, so `not a = 1` is
+// parsed as `a != 1`. With this option enabled `and`, `or` and `not`
+// can no longer be used as regular identifiers on their positions.
+func Keywords() ParseOption {
+	return func(p *parser) {
+		p.features |= FeatureKeywords
+	}
+}
+
+// ScannerOptions registers the options of the Scanner used for
+// tokenizing the parsed text (eg. `ScannerOptions(CommentMarkers("#"))`).
+func ScannerOptions(opts ...ScannerOption) ParseOption {
+	return func(p *parser) {
+		p.scannerOpts = append(p.scannerOpts, opts...)
+	}
+}
+
+// Strict enables the strict parse mode that rejects the lenient
+// syntax forms usually written only by humans, aka. comments
+// (including the ones in the `in` values lists) and the sign
+// operator aliases (eg. `==` instead of `=`).
+//
+// It is intended for environments where the filters are expected
+// to be machine-generated and any human-ism indicates tampering.
+func Strict() ParseOption {
+	return func(p *parser) {
+		p.strict = true
+	}
+}
+
+// AllowEmpty enables parsing empty, whitespace-only or comment-only
+// input to an empty (aka. match-all) result instead of ErrEmpty.
+//
+// The empty nested groups (eg. `a = 1 && ()`) and macros are still
+// reported as errors.
+func AllowEmpty() ParseOption {
+	return func(p *parser) {
+		p.allowEmpty = true
+	}
+}
+
+// MaxDepth changes the maximum allowed nesting depth of the parsed
+// groups and expanded macros (default to 1000).
+//
+// The limit protects the recursive parser from exhausting the goroutine
+// stack with deeply nested input, so it cannot be disabled and
+// a non-positive max restores the default.
+func MaxDepth(max int) ParseOption {
+	return func(p *parser) {
+		if max <= 0 {
+			max = defaultMaxDepth
+		}
+		p.maxDepth = max
+	}
+}
+
+// enterNested pushes the nested group or macro index to the parser
+// path or returns an error if the maximum nesting depth is reached.
+func (p *parser) enterNested(index int) error {
+	if len(p.path) >= p.maxDepth {
+		return newLimitError(ErrMaxDepth, "the maximum allowed nesting depth of %d is exceeded", p.maxDepth, len(p.path)+1)
+	}
+
+	p.path = append(p.path, index)
+
+	return nil
+}
+
+// isKeyword checks if t is the specified keyword operator.
+func (p *parser) isKeyword(t Token, keyword string) bool {
+	return p.features.Has(FeatureKeywords) && isWordToken(t, keyword)
+}
+
+// PlaceholderResolver resolves the values of the `@` prefixed
+// identifier placeholders (eg. "@now" or "@request.auth.id").
+type PlaceholderResolver interface {
+	// ResolvePlaceholder returns the token that should replace
+	// the named placeholder or an error if the placeholder is unknown.
+	ResolvePlaceholder(name string) (Token, error)
+}
+
+// PlaceholderResolverFunc is an adapter to allow the use of ordinary
+// functions as PlaceholderResolver.
+type PlaceholderResolverFunc func(name string) (Token, error)
+
+// ResolvePlaceholder calls f(name).
+func (f PlaceholderResolverFunc) ResolvePlaceholder(name string) (Token, error) {
+	return f(name)
+}
+
+// PlaceholderMap is a PlaceholderResolver that resolves
+// the placeholders from a static map of values.
+type PlaceholderMap map[string]Token
+
+// ResolvePlaceholder returns the mapped placeholder token
+// or an error if the placeholder is not in the map.
+func (m PlaceholderMap) ResolvePlaceholder(name string) (Token, error) {
+	t, ok := m[name]
+	if !ok {
+		return Token{}, errorf(ErrUnknownPlaceholder, "unknown placeholder %q", name)
+	}
+
+	return t, nil
+}
+
+// Placeholders registers a resolver for the `@` prefixed identifier
+// operands (eg. `Placeholders(PlaceholderMap{"@now": {Type: TokenText, Literal: "2022-01-01"}})`
+// replaces `created < @now` with `created < "2022-01-01"`).
+//
+// The parsing fails with the resolver error for unknown placeholders.
+func Placeholders(resolver PlaceholderResolver) ParseOption {
+	return func(p *parser) {
+		p.placeholders = resolver
+	}
+}
+
+// resolveOperand returns the resolved placeholder value of t or
+// t itself if it is not a placeholder identifier
+// (the enabled literal features are also applied, see EnableFeatures).
+func (p *parser) resolveOperand(t Token) (Token, error) {
+	if p.placeholders == nil || t.Type != TokenIdentifier || !strings.HasPrefix(t.Literal, "@") {
+		return p.literalOperand(t), nil
+	}
+
+	resolved, err := p.placeholders.ResolvePlaceholder(t.Literal)
+	if err != nil {
+		return Token{}, errorf(ErrInvalidPlaceholder, "invalid placeholder %q: %w", t.Literal, err)
+	}
+
+	if resolved.Type != TokenIdentifier && resolved.Type != TokenText && resolved.Type != TokenNumber &&
+		resolved.Type != TokenNull && resolved.Type != TokenBool {
+		return Token{}, errorf(ErrInvalidPlaceholder, "invalid placeholder %q value type %q", t.Literal, resolved.Type)
+	}
+
+	return resolved, nil
+}
+
+// ExprHook is an OnExpr callback that is invoked with the assembled
+// ExprGroup and the input byte offsets range of its item.
+type ExprHook func(g ExprGroup, span Span) error
+
+// OnExpr registers a callback that is invoked for every ExprGroup as
+// soon as it is assembled (the nested groups items are reported before
+// the group itself), allowing inline validation and early abort of the
+// parsing (eg. rejecting an unknown field the moment it appears).
+//
+// A non-nil hook error stops the parsing and is returned wrapped in
+// an *ExprError with the item's input position.
+//
+// The items of the expanded macros are not reported individually,
+// only the macro reference itself.
+func OnExpr(fn ExprHook) ParseOption {
+	return func(p *parser) {
+		if fn != nil {
+			p.exprHooks = append(p.exprHooks, fn)
+		}
+	}
+}
+
+// onExpr invokes the registered OnExpr hooks for g.
+func (p *parser) onExpr(g ExprGroup, span Span) error {
+	if len(p.expanding) > 0 {
+		return nil // macro item
+	}
+
+	for _, hook := range p.exprHooks {
+		if err := hook(g, span); err != nil {
+			return &ExprError{Span: span, Err: err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,480 @@
+package fexpr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMacro(t *testing.T) {
+	opts := []ParseOption{
+		Macro("mine", "owner = @request.auth.id"),
+		Macro("#active", "status = 'active' || status = 'pending'"),
+		Macro("both", "#mine && #active"),
+		Macro("invalid", "a >"),
+		Macro("self", "a = 1 || #self"),
+		Macro("loop1", "#loop2"),
+		Macro("loop2", "#loop1"),
+	}
+
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{`#missing`, true, `[]`},
+		{`#invalid`, true, `[]`},
+		{`#self`, true, `[]`},
+		{`#loop1`, true, `[]`},
+		{`#mine = 1`, true, `[]`},
+		{`#missing = 1`, false, `[{&& {{identifier #missing} = {number 1}}}]`},
+		{`a = #mine`, false, `[{&& {{identifier a} = {identifier #mine}}}]`},
+		{`#mine`, false, `[{&& [{&& {{identifier owner} = {identifier @request.auth.id}}}]}]`},
+		{
+			`a = 1 || #active`,
+			false,
+			`[{&& {{identifier a} = {number 1}}} {|| [{&& {{identifier status} = {text active}}} {|| {{identifier status} = {text pending}}}]}]`,
+		},
+		{
+			`(#both)`,
+			false,
+			`[{&& [{&& [{&& [{&& {{identifier owner} = {identifier @request.auth.id}}}]} {&& [{&& {{identifier status} = {text active}}} {|| {{identifier status} = {text pending}}}]}]}]}]`,
+		},
+		// the same macro could be used multiple times
+		{
+			`#mine && #mine`,
+			false,
+			`[{&& [{&& {{identifier owner} = {identifier @request.auth.id}}}]} {&& [{&& {{identifier owner} = {identifier @request.auth.id}}}]}]`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := Parse(s.input, opts...)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	resolver := PlaceholderMap{
+		"@now":             {Type: TokenText, Literal: "2022-01-01 00:00:00"},
+		"@request.auth.id": {Type: TokenText, Literal: "abc"},
+		"@invalid":         {Type: TokenJoin, Literal: "&&"},
+	}
+
+	opts := []ParseOption{
+		Placeholders(resolver),
+		Macro("mine", "owner = @request.auth.id"),
+	}
+
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{`a = @missing`, true, `[]`},
+		{`@missing = 1`, true, `[]`},
+		{`a = @invalid`, true, `[]`},
+		{`a = b`, false, `[{&& {{identifier a} = {identifier b}}}]`},
+		{`created < @now`, false, `[{&& {{identifier created} < {text 2022-01-01 00:00:00}}}]`},
+		{`@request.auth.id != "" && (a = 1 || #mine)`, false, `[{&& {{text abc} != {text }}} {&& [{&& {{identifier a} = {number 1}}} {|| [{&& {{identifier owner} = {text abc}}}]}]}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := Parse(s.input, opts...)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestPlaceholderResolverFunc(t *testing.T) {
+	var names []string
+
+	resolver := PlaceholderResolverFunc(func(name string) (Token, error) {
+		names = append(names, name)
+		return Token{Type: TokenNumber, Literal: "1"}, nil
+	})
+
+	v, err := Parse(`@a = @b.c`, Placeholders(resolver))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& {{number 1} = {number 1}}}]`
+	if vPrint := fmt.Sprintf("%v", v); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+
+	if v := fmt.Sprintf("%v", names); v != "[@a @b.c]" {
+		t.Fatalf("Expected the resolved names [@a @b.c], got %s", v)
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{`not`, true, `[]`},
+		{`a = 1 and`, true, `[]`},
+		{`a = 1 not b = 2`, true, `[]`},
+		{`not a ?= 1`, false, `[{&& {{identifier a} *!= {number 1}}}]`},
+		{`a = 1 AND b = 2 Or c = 3`, false, `[{&& {{identifier a} = {number 1}}} {&& {{identifier b} = {number 2}}} {|| {{identifier c} = {number 3}}}]`},
+		{`not a in (1, 2)`, false, `[{&& {{identifier a} not in {list 1, 2}}}]`},
+		{`not a = 1`, false, `[{&& {{identifier a} != {number 1}}}]`},
+		{`NOT not a = 1`, false, `[{&& {{identifier a} = {number 1}}}]`},
+		{`a = 1 && not (b > 2 or c ~ 'x')`, false, `[{&& {{identifier a} = {number 1}}} {&& [{&& {{identifier b} <= {number 2}}} {&& {{identifier c} !~ {text x}}}]}]`},
+		{`and = or`, false, `[{&& {{identifier and} = {identifier or}}}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := Parse(s.input, Keywords())
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestKeywordsDisabled(t *testing.T) {
+	if _, err := Parse(`a = 1 and b = 2`); err == nil {
+		t.Fatal("Expected the keyword operators to be disabled by default")
+	}
+}
+
+func TestStrict(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{`a == 1`, true, `[]`},
+		{`a <> 1`, true, `[]`},
+		{"a = 1 // test", true, `[]`},
+		{"(// test\na = 1)", true, `[]`},
+		{"a in (1, // test\n2)", true, `[]`},
+		{"a not // test\n in (1)", true, `[]`},
+		{`a not in (1)`, false, `[{&& {{identifier a} not in {list 1}}}]`},
+		{`a = 1`, false, `[{&& {{identifier a} = {number 1}}}]`},
+		{`a in (1, 2) && (b != "c")`, false, `[{&& {{identifier a} in {list 1, 2}}} {&& [{&& {{identifier b} != {text c}}}]}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := Parse(s.input, Strict())
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestAllowEmpty(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{``, false, `[]`},
+		{" \t\n ", false, `[]`},
+		{"// test\n// test2", false, `[]`},
+		{`()`, true, `[]`},
+		{`a = 1 && ()`, true, `[]`},
+		{`#empty`, true, `[]`},
+		{`a =`, true, `[]`},
+		{`a = 1`, false, `[{&& {{identifier a} = {number 1}}}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := Parse(s.input, AllowEmpty(), Macro("empty", " "))
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if !s.expectedError && v == nil {
+				t.Fatalf("Expected non-nil result")
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestAllowEmptyParseFunc(t *testing.T) {
+	var calls int
+
+	err := ParseFunc("  ", func(g ExprGroup) error {
+		calls++
+		return nil
+	}, AllowEmpty())
+	if err != nil {
+		t.Fatalf("Did not expect error, got %v", err)
+	}
+
+	if calls != 0 {
+		t.Fatalf("Expected no callback calls, got %d", calls)
+	}
+}
+
+func TestScannerOptions(t *testing.T) {
+	v, err := Parse("a = 1 -- test\n&& (b in (1, -- list\n2) # group\n)", ScannerOptions(CommentMarkers("--"), CommentMarkers("#")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& {{identifier a} = {number 1}}} {&& [{&& {{identifier b} in {list 1, 2}}}]}]`
+	if vPrint := fmt.Sprintf("%v", v); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+}
+
+func TestScannerOptionsLimits(t *testing.T) {
+	if _, err := Parse(`a = "abc" && b = 1`, ScannerOptions(MaxTextLength(3), MaxInputLength(18))); err != nil {
+		t.Fatalf("Did not expect error, got %v", err)
+	}
+
+	if _, err := Parse(`a = "abcd" && b = 1`, ScannerOptions(MaxTextLength(3))); err == nil {
+		t.Fatal("Expected text length error, got nil")
+	}
+
+	if _, err := Parse(`a = "abc" && b = 12`, ScannerOptions(MaxInputLength(18))); err == nil {
+		t.Fatal("Expected input length error, got nil")
+	}
+}
+
+func TestScannerOptionsRegisterSignOp(t *testing.T) {
+	opts := ScannerOptions(RegisterSignOp("@>", SignOpOptions{}), RegisterSignOp("<@@", SignOpOptions{List: true}))
+
+	v, err := Parse(`tags @> "x" && (a <@@ (1, "b") || c @> d)`, opts, Strict())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& {{identifier tags} @> {text x}}} {&& [{&& {{identifier a} <@@ {list 1, "b"}}} {|| {{identifier c} @> {identifier d}}}]}]`
+	if vPrint := fmt.Sprintf("%v", v); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+
+	if _, err := Parse(`a <@@ 1`, opts); err == nil {
+		t.Fatal("Expected list operand error, got nil")
+	}
+
+	if _, err := Parse(`a @> b`); err == nil {
+		t.Fatal("Expected unregistered operator error, got nil")
+	}
+}
+
+func TestScannerOptionsRegisterLiteral(t *testing.T) {
+	opts := ScannerOptions(RegisterLiteral('#', scanTestColor))
+
+	v, err := Parse(`color = #00ff00 || #ABCDEF != background`, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& {{identifier color} = {color #00ff00}}} {|| {{color #ABCDEF} != {identifier background}}}]`
+	if vPrint := fmt.Sprintf("%v", v); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+
+	if str := Stringify(v); str != `color = #00ff00 || #ABCDEF != background` {
+		t.Fatalf("Unexpected stringified filter %s", str)
+	}
+}
+
+func TestScannerOptionsRegisterJoinOp(t *testing.T) {
+	opts := ScannerOptions(RegisterJoinOp("=>", JoinOpOptions{}))
+
+	v, err := Parse(`a = 1 => (b = 2 || c = 3) && d = 4`, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& {{identifier a} = {number 1}}} {=> [{&& {{identifier b} = {number 2}}} {|| {{identifier c} = {number 3}}}]} {&& {{identifier d} = {number 4}}}]`
+	if vPrint := fmt.Sprintf("%v", v); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+
+	if str := Stringify(v); str != `a = 1 => (b = 2 || c = 3) && d = 4` {
+		t.Fatalf("Unexpected stringified filter %s", str)
+	}
+
+	if _, err := Parse(`not (a = 1 => b = 2)`, opts, Keywords()); err == nil {
+		t.Fatal("Expected custom join negation error, got nil")
+	}
+}
+
+func TestScannerOptionsTextUnescape(t *testing.T) {
+	v, err := Parse(`a like "50\\%" && b in ("x\"y", 'z\\w')`, ScannerOptions(TextUnescape(UnescapeStrict)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{&& {{identifier a} like {text 50\%}}} {&& {{identifier b} in {list 'x"y', "z\w"}}}]`
+	if vPrint := fmt.Sprintf("%v", v); vPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, vPrint)
+	}
+
+	if _, err := Parse(`a = "\d"`, ScannerOptions(TextUnescape(UnescapeStrict))); err == nil {
+		t.Fatal("Expected invalid escape sequence error, got nil")
+	}
+}
+
+func TestScannerOptionsPreciseNumbers(t *testing.T) {
+	v, err := Parse(`amount = 0.1 && (total >= 007.50 || count = 3)`, ScannerOptions(PreciseNumbers()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	where, err := ToGraphQLWhere(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := json.Marshal(where)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"_and":[{"amount":{"_eq":0.1}},{"_or":[{"total":{"_gte":7.50}},{"count":{"_eq":3}}]}]}`
+	if string(raw) != expected {
+		t.Fatalf("Expected %s, got %s", expected, raw)
+	}
+}
+
+func TestScannerOptionsOnToken(t *testing.T) {
+	var identifiers []string
+
+	// allow only the "a" and "b" fields
+	hook := func(t Token, span Span, err error) error {
+		if t.Type == TokenIdentifier {
+			identifiers = append(identifiers, t.Literal)
+			if t.Literal != "a" && t.Literal != "b" {
+				return fmt.Errorf("unknown field %q", t.Literal)
+			}
+		}
+		return nil
+	}
+
+	if _, err := Parse(`a = 1 && (b = 2 || a = b)`, ScannerOptions(OnToken(hook))); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[a b a b]`
+	if identifiersPrint := fmt.Sprintf("%v", identifiers); identifiersPrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, identifiersPrint)
+	}
+
+	_, err := Parse(`a = 1 && (c = 2)`, ScannerOptions(OnToken(hook)))
+	if err == nil || err.Error() != `unknown field "c"` {
+		t.Fatalf("Expected unknown field error, got %v", err)
+	}
+}
+
+func TestOnExpr(t *testing.T) {
+	input := `a = 1 && (b > 2 || not c ~ "x") && #m`
+
+	var trace []string
+
+	hook := func(g ExprGroup, span Span) error {
+		trace = append(trace, fmt.Sprintf("%s %q", g.Join, input[span.Start:span.End]))
+		return nil
+	}
+
+	_, err := Parse(input, OnExpr(hook), Keywords(), Macro("m", "d = 4 && e = 5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[&& "a = 1" && "b > 2" || "not c ~ \"x\"" && "(b > 2 || not c ~ \"x\")" && "#m"]`
+	if tracePrint := fmt.Sprintf("%v", trace); tracePrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, tracePrint)
+	}
+}
+
+func TestOnExprError(t *testing.T) {
+	input := `a = 1 && ( b = 2 || (c = 3 && deleted = true))`
+
+	var calls int
+
+	hook := func(g ExprGroup, span Span) error {
+		calls++
+		if expr, ok := g.Item.(Expr); ok && expr.Left.Literal == "deleted" {
+			return errors.New("unknown field")
+		}
+		return nil
+	}
+
+	_, err := Parse(input, OnExpr(hook))
+
+	var exprErr *ExprError
+	if !errors.As(err, &exprErr) {
+		t.Fatalf("Expected ExprError, got %v", err)
+	}
+
+	if expected := `unknown field (at 30:44)`; err.Error() != expected {
+		t.Fatalf("Expected error %q, got %q", expected, err.Error())
+	}
+
+	if text := input[exprErr.Span.Start:exprErr.Span.End]; text != "deleted = true" {
+		t.Fatalf("Expected the deleted condition span, got %q", text)
+	}
+
+	// the parsing should stop at the first error
+	if calls != 4 {
+		t.Fatalf("Expected 4 hook calls, got %d", calls)
+	}
+}
@@ -0,0 +1,108 @@
+package fexpr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parameterPrefix is the prefix of the Parameterize placeholders.
+const parameterPrefix = "@p"
+
+// Parameterize returns a copy of the provided parsed filter with each
+// literal operand (aka. number and text, including the `in` list items)
+// replaced with a positional placeholder identifier (`@p0`, `@p1`, etc.)
+// and the replaced literal tokens in their placeholders order.
+//
+// It is the inverse of the placeholders binding, aka. the original
+// filter could be restored by parsing the stringified result with
+// `Placeholders(ParameterMap(values))`.
+//
+// Note that the existing `@pN` identifiers of the filter are not renamed
+// and they could clash with the generated placeholders.
+func Parameterize(exprs []ExprGroup) ([]ExprGroup, []Token) {
+	values := []Token{}
+
+	result := parameterizeGroups(exprs, &values)
+
+	return result, values
+}
+
+// ParameterMap returns a PlaceholderResolver of the Parameterize
+// placeholders and their values.
+//
+// The other placeholder identifiers (eg. `@request.auth.id`) are
+// resolved to themselves, aka. they are left unchanged.
+func ParameterMap(values []Token) PlaceholderResolver {
+	params := make(PlaceholderMap, len(values))
+
+	for i, v := range values {
+		params[parameterName(i)] = v
+	}
+
+	return PlaceholderResolverFunc(func(name string) (Token, error) {
+		if t, ok := params[name]; ok {
+			return t, nil
+		}
+
+		return Token{Type: TokenIdentifier, Literal: name}, nil
+	})
+}
+
+// parameterizeGroups returns a copy of groups with their literal operands
+// replaced with placeholders and appends the replaced tokens to values.
+func parameterizeGroups(groups []ExprGroup, values *[]Token) []ExprGroup {
+	result := make([]ExprGroup, len(groups))
+
+	for i, g := range groups {
+		result[i] = g
+
+		switch v := g.Item.(type) {
+		case Expr:
+			v.Left = parameterizeToken(v.Left, values)
+			v.Right = parameterizeToken(v.Right, values)
+			result[i].Item = v
+		case []ExprGroup:
+			result[i].Item = parameterizeGroups(v, values)
+		case UnaryExpr:
+			result[i].Item, _ = mapUnaryOperand(v, func(operand []ExprGroup) []ExprGroup {
+				return parameterizeGroups(operand, values)
+			})
+		}
+	}
+
+	return result
+}
+
+// parameterizeToken replaces a literal operand with the next placeholder
+// (or each literal item of a TokenList) and appends the replaced tokens to values.
+func parameterizeToken(t Token, values *[]Token) Token {
+	switch t.Type {
+	case TokenNumber, TokenText:
+		*values = append(*values, t)
+		return Token{Type: TokenIdentifier, Literal: parameterName(len(*values) - 1)}
+	case TokenList:
+		items, err := SplitList(t.Literal)
+		if err != nil {
+			return t // not a valid list - leave it as it is
+		}
+
+		var sb strings.Builder
+
+		for i, item := range items {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+
+			writeToken(&sb, parameterizeToken(item, values))
+		}
+
+		return Token{Type: TokenList, Literal: sb.String()}
+	default:
+		return t
+	}
+}
+
+// parameterName returns the name of the placeholder at index i.
+func parameterName(i int) string {
+	return parameterPrefix + strconv.Itoa(i)
+}
@@ -0,0 +1,70 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParameterize(t *testing.T) {
+	scenarios := []struct {
+		input          string
+		expectedFilter string
+		expectedValues string
+	}{
+		{`a = b`, `a = b`, `[]`},
+		{`a = 1`, `a = @p0`, `[{number 1}]`},
+		{`"x" != a && (b > 1.5 || c in (1, "y", d))`, `@p0 != a && (b > @p1 || c in (@p2, @p3, d))`, `[{text x} {number 1.5} {number 1} {text y}]`},
+		{`a ~ "it's" || a = @request.id`, `a ~ @p0 || a = @request.id`, `[{text it's}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, values := Parameterize(exprs)
+
+			if v := Stringify(result); v != s.expectedFilter {
+				t.Fatalf("Expected filter %s, got %s", s.expectedFilter, v)
+			}
+
+			if v := fmt.Sprintf("%v", values); v != s.expectedValues {
+				t.Fatalf("Expected values %s, got %s", s.expectedValues, v)
+			}
+
+			// the original filter must not be modified
+			if v := Stringify(exprs); v == s.expectedFilter && len(values) > 0 {
+				t.Fatalf("The original filter was modified: %s", v)
+			}
+		})
+	}
+}
+
+func TestParameterizeRoundTrip(t *testing.T) {
+	scenarios := []string{
+		`"x" != a && (b > 1.5 || c in (1, "y", d))`,
+		`author = @request.auth.id && status in ("a", @status)`,
+	}
+
+	for i, input := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, input), func(t *testing.T) {
+			exprs, err := Parse(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			templated, values := Parameterize(exprs)
+
+			bound, err := Parse(Stringify(templated), Placeholders(ParameterMap(values)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected, v := fmt.Sprintf("%v", exprs), fmt.Sprintf("%v", bound); v != expected {
+				t.Fatalf("Expected %s, got %s", expected, v)
+			}
+		})
+	}
+}
@@ -23,7 +23,8 @@ func (e Expr) IsZero() bool {
 
 // ExprGroup represents a wrapped expression and its join type.
 //
-// The group's Item could be either an `Expr` instance or `[]ExprGroup` slice (for nested expressions).
+// The group's Item could be either an `Expr` instance or `[]ExprGroup` slice (for nested expressions)
+// or an `UnaryExpr` instance (for the custom unary operators, see RegisterUnaryOp).
 type ExprGroup struct {
 	Join JoinOp
 	Item interface{}
@@ -40,38 +41,238 @@ const (
 // Parse parses the provided text and returns its processed AST
 // in the form of `ExprGroup` slice(s).
 //
+// Comments and whitespaces are ignored (see the Comments option
+// for collecting the comments).
+//
+// The parse errors (except ErrEmpty) are returned as *ParseError
+// with the failure input position (see ErrorSnippet).
+func Parse(text string, opts ...ParseOption) ([]ExprGroup, error) {
+	p := newParser(opts)
+
+	if p.canParseSimple() {
+		if result, ok := parseSimple(text); ok {
+			return result, nil
+		}
+	}
+
+	result, err := p.parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p.finish()
+
+	return result, nil
+}
+
+// ParseFunc parses the provided text and invokes fn for each
+// top-level `ExprGroup` as soon as it is assembled, instead of
+// materializing the whole result slice.
+//
+// Parsing stops at the first error returned by fn.
 // Comments and whitespaces are ignored.
-func Parse(text string) ([]ExprGroup, error) {
+func ParseFunc(text string, fn func(ExprGroup) error, opts ...ParseOption) error {
+	p := newParser(opts)
+
+	if p.canParseSimple() {
+		if result, ok := parseSimple(text); ok {
+			return fn(result[0])
+		}
+	}
+
+	if err := p.parseFunc(text, fn); err != nil {
+		return err
+	}
+
+	p.finish()
+
+	return nil
+}
+
+// ParseSafe is the same as Parse but additionally recovers from
+// any unexpected panic and returns it as an error.
+//
+// Parse itself is not expected to panic for any input (including
+// invalid UTF-8 and deeply nested groups, see MaxDepth), but ParseSafe
+// could be used as extra guarantee when parsing untrusted input
+// or when the options callbacks (eg. a PlaceholderResolver) could panic.
+func ParseSafe(text string, opts ...ParseOption) (result []ExprGroup, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = fmt.Errorf("unexpected parse panic: %v", r)
+		}
+	}()
+
+	return Parse(text, opts...)
+}
+
+// parse is the generic Parse implementation that runs the full
+// tokens state machine and collects its result.
+func (p *parser) parse(text string) ([]ExprGroup, error) {
 	result := []ExprGroup{}
-	scanner := NewScanner(strings.NewReader(text))
+
+	err := p.parseFunc(text, func(g ExprGroup) error {
+		result = append(result, g)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// parseFunc runs the parser's tokens state machine and invokes
+// fn for each completed top-level `ExprGroup`.
+//
+// The parse errors are returned as *ParseError with the input position
+// of the last scanned token (the fn errors are returned as they are).
+func (p *parser) parseFunc(text string, fn func(ExprGroup) error) (err error) {
+	var total int
+	scanner := NewScanner(strings.NewReader(text), p.scannerOpts...)
+
+	var fnErr error
+	callback := fn
+	fn = func(g ExprGroup) error {
+		fnErr = callback(g)
+		return fnErr
+	}
+
+	defer func() {
+		if err != nil && err != fnErr {
+			err = p.positionError(err, scanner.LastSpan())
+		}
+	}()
 	step := stepBeforeSign
 	join := JoinAnd
 
 	var expr Expr
+	var negate bool
+
+	// the pending custom unary operators (see RegisterUnaryOp)
+	var unary []UnaryOp
+	var chains []unaryChain
+
+	comments := commentsTracker{p: p, last: -1}
+
+	// the start offset of the currently parsed item (-1 if none)
+	itemStart := -1
+
+	// the end offset of the last item and the span of the following join
+	// operator (used for the trailing join operator FixIt)
+	var itemEnd int
+	var joinSpan Span
+
+	// emit invokes fn with the (optionally negated) item
+	emit := func(item interface{}) error {
+		if negate {
+			negated, err := negateItem(item)
+			if err != nil {
+				return err
+			}
+			item = negated
+			negate = false
+		}
+
+		item = wrapUnary(item, unary)
+		unary = nil
+
+		if err := p.checkComplexity(item); err != nil {
+			return err
+		}
+
+		span := Span{Start: p.offset + itemStart, End: p.offset + scanner.LastSpan().End}
+		itemStart = -1
+		itemEnd = span.End
+		if err := p.onExpr(ExprGroup{Join: join, Item: item}, span); err != nil {
+			return err
+		}
+
+		// collect the item in the innermost pending unary `&&` chain
+		if last := len(chains) - 1; last >= 0 {
+			itemJoin := join
+			if len(chains[last].items) == 0 {
+				itemJoin = JoinAnd
+			}
+			chains[last].items = append(chains[last].items, ExprGroup{Join: itemJoin, Item: item})
+			return nil
+		}
+
+		comments.emitted(total)
+		total++
+
+		return fn(ExprGroup{Join: join, Item: item})
+	}
+
+	// closeChains closes and emits the pending unary `&&` chains
+	closeChains := func() error {
+		for last := len(chains) - 1; last >= 0; last-- {
+			group := closeUnaryChain(chains[last])
+			chains = chains[:last]
+
+			if last > 0 {
+				chains[last-1].items = append(chains[last-1].items, group)
+				continue
+			}
+
+			comments.emitted(total)
+			total++
+
+			if err := fn(group); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
 
 	for {
 		t, err := scanner.Scan()
 		if err != nil {
-			return nil, err
+			return p.shiftFixIt(err)
 		}
 
 		if t.Type == TokenEOF {
 			break
 		}
 
+		if t.Type == TokenComment && p.strict {
+			return errorf(ErrStrictMode, "comments are not allowed in strict mode")
+		}
+
+		if t.Type == TokenComment && (step == stepSign || step == stepAfterSign) {
+			p.warn(scanner.LastSpan(), "comment inside the %q expression", expr.Left.Literal)
+		}
+
 		if t.Type == TokenWS || t.Type == TokenComment {
+			comments.token(t)
 			continue
 		}
 
-		if t.Type == TokenGroup {
-			groupResult, err := Parse(t.Literal)
+		if step == stepBeforeSign && itemStart < 0 {
+			itemStart = scanner.LastSpan().Start
+		}
+
+		if t.Type == TokenGroup && !(step == stepAfterSign && scanner.isListSignOp(expr.Op)) {
+			comments.leading(total)
+			if err := p.enterNested(total); err != nil {
+				return err
+			}
+			offset := p.offset
+			p.offset += scanner.LastSpan().Start + 1 // skip the opening bracket
+			groupResult, err := p.parse(t.Literal)
+			p.offset = offset
+			p.path = p.path[:len(p.path)-1]
 			if err != nil {
-				return nil, err
+				return err
 			}
 
-			// append only if non-empty group
+			// emit only if non-empty group
 			if len(groupResult) > 0 {
-				result = append(result, ExprGroup{Join: join, Item: groupResult})
+				if err := emit(groupResult); err != nil {
+					return err
+				}
 			}
 
 			step = StepJoin
@@ -80,37 +281,124 @@ func Parse(text string) ([]ExprGroup, error) {
 
 		switch step {
 		case stepBeforeSign:
-			if t.Type != TokenIdentifier && t.Type != TokenText && t.Type != TokenNumber {
-				return nil, fmt.Errorf("expected left operand (identifier, text or number), got %q (%s)", t.Literal, t.Type)
+			if t.Type == TokenUnary {
+				if negate {
+					return errorf(ErrUnexpectedToken, "the custom unary operator %q cannot be negated", t.Literal)
+				}
+
+				op := UnaryOp(t.Literal)
+				opts, _ := scanner.LookupUnaryOp(op)
+
+				// the operators after a condition unary operator could apply only to the condition
+				if opts.Precedence == UnaryBindsAnd && len(unary) == 0 {
+					chainJoin := join
+					if last := len(chains) - 1; last >= 0 && len(chains[last].items) == 0 {
+						chainJoin = JoinAnd
+					}
+					chains = append(chains, unaryChain{op: op, join: chainJoin})
+				} else {
+					unary = append(unary, op)
+				}
+				continue
+			}
+
+			if p.isKeyword(t, "not") {
+				negate = !negate
+				continue
+			}
+
+			if p.isMacro(t) {
+				comments.leading(total)
+				if err := p.enterNested(total); err != nil {
+					return err
+				}
+				macroResult, err := p.expandMacro(t.Literal)
+				p.path = p.path[:len(p.path)-1]
+				if err != nil {
+					return err
+				}
+
+				if err := emit(macroResult); err != nil {
+					return err
+				}
+
+				step = StepJoin
+				continue
+			}
+
+			if !isOperandToken(t) {
+				return errorf(ErrUnexpectedToken, "expected left operand (identifier, text or number), got %q (%s)", t.Literal, t.Type)
+			}
+
+			if t, err = p.resolveOperand(t); err != nil {
+				return err
 			}
 
 			expr = Expr{Left: t}
 
 			step = stepSign
 		case stepSign:
+			if op, err := p.scanKeywordSignOp(t, scanner); err != nil {
+				return p.shiftFixIt(err)
+			} else if op != "" {
+				expr.Op = op
+				step = stepAfterSign
+				continue
+			}
+
 			if t.Type != TokenSign {
-				return nil, fmt.Errorf("expected a sign operator, got %q (%s)", t.Literal, t.Type)
+				return errorf(ErrUnexpectedToken, "expected a sign operator, got %q (%s)", t.Literal, t.Type)
 			}
 
-			expr.Op = SignOp(t.Literal)
+			expr.Op = normalizeSignOp(t.Literal)
+			if string(expr.Op) != t.Literal {
+				if p.strict {
+					return errorf(ErrStrictMode, "sign operator alias %q is not allowed in strict mode (use %q)", t.Literal, expr.Op)
+				}
+				p.warn(scanner.LastSpan(), "sign operator alias %q normalized to %q", t.Literal, expr.Op)
+			}
 			step = stepAfterSign
 		case stepAfterSign:
-			if t.Type != TokenIdentifier && t.Type != TokenText && t.Type != TokenNumber {
-				return nil, fmt.Errorf("expected right operand (identifier, text or number), got %q (%s)", t.Literal, t.Type)
+			if scanner.isListSignOp(expr.Op) {
+				if t.Type != TokenGroup {
+					return errorf(ErrUnexpectedToken, "expected a parenthesized values list after %q, got %q (%s)", expr.Op, t.Literal, t.Type)
+				}
+
+				if t, err = p.parseList(t.Literal); err != nil {
+					return err
+				}
+			} else if !isOperandToken(t) {
+				return errorf(ErrUnexpectedToken, "expected right operand (identifier, text or number), got %q (%s)", t.Literal, t.Type)
+			}
+
+			if t, err = p.resolveOperand(t); err != nil {
+				return err
 			}
 
 			expr.Right = t
-			result = append(result, ExprGroup{Join: join, Item: expr})
+			if err := emit(expr); err != nil {
+				return err
+			}
 
 			step = StepJoin
 		case StepJoin:
+			if p.isKeyword(t, "and") {
+				t = Token{Type: TokenJoin, Literal: string(JoinAnd)}
+			} else if p.isKeyword(t, "or") {
+				t = Token{Type: TokenJoin, Literal: string(JoinOr)}
+			}
+
 			if t.Type != TokenJoin {
-				return nil, fmt.Errorf("expected && or ||, got %q (%s)", t.Literal, t.Type)
+				return errorf(ErrUnexpectedToken, "expected && or ||, got %q (%s)", t.Literal, t.Type)
 			}
 
-			join = JoinAnd
-			if t.Literal == "||" {
-				join = JoinOr
+			join = JoinOp(t.Literal)
+			joinSpan = Span{Start: p.offset + scanner.LastSpan().Start, End: p.offset + scanner.LastSpan().End}
+
+			if join != JoinAnd {
+				if err := closeChains(); err != nil {
+					return err
+				}
 			}
 
 			step = stepBeforeSign
@@ -118,12 +406,209 @@ func Parse(text string) ([]ExprGroup, error) {
 	}
 
 	if step != StepJoin {
-		if len(result) == 0 && expr.IsZero() {
-			return nil, ErrEmpty
+		if total == 0 && expr.IsZero() && !negate && len(unary) == 0 && len(chains) == 0 {
+			// blank top-level input
+			if p.allowEmpty && len(p.path) == 0 {
+				comments.done()
+				return nil
+			}
+
+			return ErrEmpty
+		}
+
+		// trailing join operator
+		if step == stepBeforeSign && itemStart < 0 && joinSpan.End > 0 && len(p.expanding) == 0 {
+			return &kindError{
+				kind:   ErrIncomplete,
+				format: ErrIncomplete.Error(),
+				err:    ErrIncomplete,
+				fix:    &FixIt{Span: Span{Start: itemEnd, End: joinSpan.End}},
+			}
 		}
 
-		return nil, ErrIncomplete
+		return ErrIncomplete
 	}
 
-	return result, nil
+	if err := closeChains(); err != nil {
+		return err
+	}
+
+	comments.done()
+
+	return nil
+}
+
+// positionError wraps err into a *ParseError with the absolute input
+// position of span (the relative offset of the nested groups is applied).
+//
+// ErrEmpty, the already positioned errors and the errors of the
+// expanded macros (they are positioned at the macro identifier) are
+// returned as they are.
+func (p *parser) positionError(err error, span Span) error {
+	if err == ErrEmpty || len(p.expanding) > 0 {
+		return err
+	}
+
+	var parseErr *ParseError
+	var exprErr *ExprError
+	if errors.As(err, &parseErr) || errors.As(err, &exprErr) {
+		return err
+	}
+
+	return &ParseError{
+		Span: Span{Start: p.offset + span.Start, End: p.offset + span.End},
+		Err:  err,
+	}
+}
+
+// isOperandToken checks if t could be used as expression operand,
+// aka. identifier, text, number or custom literal (see RegisterLiteral) token.
+func isOperandToken(t Token) bool {
+	switch t.Type {
+	case TokenIdentifier, TokenText, TokenNumber, TokenNull, TokenBool:
+		return true
+	case "", TokenUnexpected, TokenEOF, TokenWS, TokenJoin, TokenSign, TokenGroup, TokenComment, TokenList, TokenUnary:
+		return false
+	default:
+		return true
+	}
+}
+
+// isWordToken checks if t is the specified case-insensitive word identifier.
+func isWordToken(t Token, word string) bool {
+	return t.Type == TokenIdentifier && strings.EqualFold(t.Literal, word)
+}
+
+// scanKeywordSignOp returns the keyword sign operator starting
+// with t (eg. "in" or "not in") or an empty string if t is not a keyword operator.
+//
+// The scanner tokens of the multi-word operators are consumed.
+func (p *parser) scanKeywordSignOp(t Token, scanner *Scanner) (SignOp, error) {
+	if !isWordToken(t, "not") {
+		return keywordSignOp(t), nil
+	}
+
+	next, err := p.scanNextToken(scanner)
+	if err != nil {
+		return "", err
+	}
+
+	op := keywordSignOp(next)
+	if op == "" {
+		return "", errorf(ErrUnexpectedToken, "expected keyword operator after \"not\", got %q (%s)", next.Literal, next.Type)
+	}
+
+	return SignOp("not " + op), nil
+}
+
+// scanNextToken returns the next scanned token that is
+// not a whitespace or comment (the comments are not allowed in strict mode).
+func (p *parser) scanNextToken(scanner *Scanner) (Token, error) {
+	for {
+		t, err := scanner.Scan()
+		if err == nil && t.Type == TokenComment && p.strict {
+			return t, errorf(ErrStrictMode, "comments are not allowed in strict mode")
+		}
+
+		if err != nil || (t.Type != TokenWS && t.Type != TokenComment) {
+			return t, err
+		}
+	}
+}
+
+// parseSimple is a fast path for the most common single
+// "operand sign operand" expression (eg. `id = 123`).
+//
+// It operates directly on the text without allocating a Scanner and
+// returns false if the text is not in that exact form, in which case
+// the caller should fallback to the generic parse.
+func parseSimple(text string) ([]ExprGroup, bool) {
+	// groups, joins, comments and escaped quotes are left for the generic parser
+	if strings.ContainsAny(text, "()&|/\\") {
+		return nil, false
+	}
+
+	left, rest, ok := cutSimpleOperand(trimLeftWhitespace(text))
+	if !ok {
+		return nil, false
+	}
+
+	rest = trimLeftWhitespace(rest)
+
+	i := 0
+	for i < len(rest) && isSignStartRune(rune(rest[i])) {
+		i++
+	}
+
+	op := rest[:i]
+	if !isSignOperator(op) {
+		return nil, false
+	}
+
+	right, rest, ok := cutSimpleOperand(trimLeftWhitespace(rest[i:]))
+	if !ok || trimLeftWhitespace(rest) != "" {
+		return nil, false
+	}
+
+	expr := Expr{Left: left, Op: normalizeSignOp(op), Right: right}
+
+	return []ExprGroup{{Join: JoinAnd, Item: expr}}, true
+}
+
+// cutSimpleOperand extracts the leading identifier, number or quoted
+// text operand from str and returns it together with the remaining string.
+func cutSimpleOperand(str string) (Token, string, bool) {
+	if str == "" {
+		return Token{}, str, false
+	}
+
+	first := rune(str[0])
+
+	if isTextStartRune(first) {
+		end := strings.IndexByte(str[1:], str[0])
+		if end < 0 {
+			return Token{}, str, false
+		}
+
+		return Token{Type: TokenText, Literal: str[1 : end+1]}, str[end+2:], true
+	}
+
+	i := 0
+	for i < len(str) {
+		ch := rune(str[i])
+		if isWhitespaceRune(ch) || isSignStartRune(ch) || isTextStartRune(ch) {
+			break
+		}
+		i++
+	}
+
+	literal := str[:i]
+
+	if isIdentifierStartRune(first) && isIdentifier(literal) {
+		return Token{Type: TokenIdentifier, Literal: literal}, str[i:], true
+	}
+
+	if isNumberStartRune(first) && isScannedNumber(literal) {
+		return Token{Type: TokenNumber, Literal: literal}, str[i:], true
+	}
+
+	return Token{}, str, false
+}
+
+// isScannedNumber checks if literal is a single valid number token
+// according to the scanner rules (see Scanner.scanNumber), aka. excluding
+// the other strconv.ParseFloat forms like `1e5`, `-inf` or `0x1p4`.
+func isScannedNumber(literal string) bool {
+	for _, ch := range literal[1:] {
+		if !isNumberPartRune(ch) {
+			return false
+		}
+	}
+
+	return isNumber(literal)
+}
+
+// trimLeftWhitespace returns str without its leading whitespace characters.
+func trimLeftWhitespace(str string) string {
+	return strings.TrimLeftFunc(str, isWhitespaceRune)
 }
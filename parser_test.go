@@ -1,7 +1,9 @@
 package fexpr
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -92,6 +94,15 @@ func TestParse(t *testing.T) {
 		{`demo='te\'st'`, false, `[{&& {{identifier demo} = {text te'st}}}]`},
 		{`demo="te\'st"`, false, `[{&& {{identifier demo} = {text te\'st}}}]`},
 		{`demo="te\"st"`, false, `[{&& {{identifier demo} = {text te"st}}}]`},
+		{`items[0].name ?= data["weird key"]`, false, `[{&& {{identifier items[0].name} ?= {identifier data["weird key"]}}}]`},
+		{`data->"profile"->>"name" = "test" || items->0 != null`, false, `[{&& {{identifier data->"profile"->>"name"} = {text test}}} {|| {{identifier items->0} != {identifier null}}}]`},
+		{`tags*~"a" && tags.* *!= 2`, false, `[{&& {{identifier tags} *~ {text a}}} {&& {{identifier tags.*} *!= {number 2}}}]`},
+		{"\xEF\xBB\xBFa = 1 && b = 2", false, `[{&& {{identifier a} = {number 1}}} {&& {{identifier b} = {number 2}}}]`},
+		{"\xFF\xFEa = 1", true, `[]`},
+		{"a = 1\r\n&& b = 2 // test\r\n", false, `[{&& {{identifier a} = {number 1}}} {&& {{identifier b} = {number 2}}}]`},
+		{`a == 1 && (b <> "c")`, false, `[{&& {{identifier a} = {number 1}}} {&& [{&& {{identifier b} != {text c}}}]}]`},
+		{`meta.count::int > 5`, false, `[{&& {{identifier meta.count::int} > {number 5}}}]`},
+		{`addresses.*.city = "Sofia" && tags.*~"urgent"`, false, `[{&& {{identifier addresses.*.city} = {text Sofia}}} {&& {{identifier tags.*} ~ {text urgent}}}]`},
 		// invalid parenthesis
 		{`(a=1`, true, `[]`},
 		{`a=1)`, true, `[]`},
@@ -137,3 +148,237 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSimple(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expectOk bool
+	}{
+		{``, false},
+		{`   `, false},
+		{`a`, false},
+		{`a =`, false},
+		{`= 1`, false},
+		{`a = 1 b`, false},
+		{`a = 1 && b = 2`, false},
+		{`(a = 1)`, false},
+		{`a = 1 // test`, false},
+		{`a = "te\"st"`, false},
+		{`a = "test`, false},
+		{`a = 1.`, false},
+		{`a =! 1`, false},
+		{`a = 1@test`, false},
+		{`a#@ = 1`, false},
+		{`1a = 1`, false},
+		{`a = 1`, true},
+		{"\t a>=-1.5 \n", true},
+		{`@request.auth.id != ""`, true},
+		{`"te'st" ?~ 'demo'`, true},
+		{`a.b:length<=b:c`, true},
+		{`a == 1`, true},
+		{`a<>b`, true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, ok := parseSimple(s.input)
+
+			if ok != s.expectOk {
+				t.Fatalf("Expected ok %v, got %v", s.expectOk, ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			// the fast path must produce the same result as the generic parser
+			expected, err := newParser(nil).parse(s.input)
+			if err != nil {
+				t.Fatalf("Did not expect the generic parse to fail, got %v", err)
+			}
+
+			if vPrint, expectedPrint := fmt.Sprintf("%v", v), fmt.Sprintf("%v", expected); vPrint != expectedPrint {
+				t.Fatalf("Expected %s, got %s", expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestParseSimpleConsistency(t *testing.T) {
+	scenarios := []string{
+		`a = 1`,
+		`a = -1.5`,
+		`a = 1e5`,
+		`a = 1E5`,
+		`a = 1e+5`,
+		`a = -inf`,
+		`a = -Infinity`,
+		`a = 0x1p4`,
+		`a = 1_0`,
+		`a = 0b101`,
+		`a = 1.2.3`,
+		`a = 007`,
+		`-1e5 = a`,
+	}
+
+	for i, input := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, input), func(t *testing.T) {
+			// the Comments option disables the fast path
+			var comments []ExprComment
+			generic, genericErr := Parse(input, Comments(&comments))
+
+			fast, fastErr := Parse(input)
+
+			if (fastErr != nil) != (genericErr != nil) {
+				t.Fatalf("Expected error %v, got %v", genericErr, fastErr)
+			}
+
+			if fastPrint, genericPrint := fmt.Sprintf("%v", fast), fmt.Sprintf("%v", generic); fastPrint != genericPrint {
+				t.Fatalf("Expected %s, got %s", genericPrint, fastPrint)
+			}
+		})
+	}
+}
+
+func TestParseFunc(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{``, true, `[]`},
+		{`a >`, true, `[]`},
+		{`a > 1`, false, `[{&& {{identifier a} > {number 1}}}]`},
+		{`a > 1 || (b = 2 && c = 3)`, false, `[{&& {{identifier a} > {number 1}}} {|| [{&& {{identifier b} = {number 2}}} {&& {{identifier c} = {number 3}}}]}]`},
+		// the already emitted groups are not reverted on error
+		{`a > 1 && b`, true, `[{&& {{identifier a} > {number 1}}}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			result := []ExprGroup{}
+
+			err := ParseFunc(s.input, func(g ExprGroup) error {
+				result = append(result, g)
+				return nil
+			})
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", result); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestParseFuncAbort(t *testing.T) {
+	abortErr := errors.New("abort")
+	calls := 0
+
+	err := ParseFunc(`a = 1 && b = 2 && c = 3`, func(g ExprGroup) error {
+		calls++
+		if calls == 2 {
+			return abortErr
+		}
+		return nil
+	})
+
+	if err != abortErr {
+		t.Fatalf("Expected the callback error, got %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("Expected 2 callback calls, got %d", calls)
+	}
+}
+
+func TestParseSafe(t *testing.T) {
+	resolver := PlaceholderResolverFunc(func(name string) (Token, error) {
+		panic("test panic")
+	})
+
+	result, err := ParseSafe(`a = @test`, Placeholders(resolver))
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if result != nil {
+		t.Fatalf("Expected nil result, got %v", result)
+	}
+
+	result, err = ParseSafe(`a = 1 && (b = 2)`)
+	if err != nil {
+		t.Fatalf("Did not expect error, got %v", err)
+	}
+	if v := fmt.Sprintf("%v", result); v != `[{&& {{identifier a} = {number 1}}} {&& [{&& {{identifier b} = {number 2}}}]}]` {
+		t.Fatalf("Unexpected result %s", v)
+	}
+}
+
+func TestParseHardening(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+	}{
+		{"a = \xff", true},
+		{"\xff = 1", true},
+		{"a = '\xff\xfe'", false},
+		{"(((a = \xff", true},
+		{strings.Repeat("(", 5000) + "a = 1" + strings.Repeat(")", 5000), true},
+		{strings.Repeat("(", 5000), true},
+		{strings.Repeat(")", 5000), true},
+		{strings.Repeat("a = 1 && ", 1000), true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d", i), func(t *testing.T) {
+			_, err := Parse(s.input)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		opts          []ParseOption
+		expectedError bool
+	}{
+		{`((a = 1))`, []ParseOption{MaxDepth(2)}, false},
+		{`(((a = 1)))`, []ParseOption{MaxDepth(2)}, true},
+		{`(a = 1) && (b = 1 || (c = 1))`, []ParseOption{MaxDepth(2)}, false},
+		{`(#test)`, []ParseOption{MaxDepth(2), Macro("test", "(a = 1)")}, true},
+		{`#test`, []ParseOption{MaxDepth(2), Macro("test", "(a = 1)")}, false},
+		{strings.Repeat("(", 1000) + "a = 1" + strings.Repeat(")", 1000), nil, false},
+		{strings.Repeat("(", 1001) + "a = 1" + strings.Repeat(")", 1001), nil, true},
+		{strings.Repeat("(", 1001) + "a = 1" + strings.Repeat(")", 1001), []ParseOption{MaxDepth(0)}, true},
+		{strings.Repeat("(", 1001) + "a = 1" + strings.Repeat(")", 1001), []ParseOption{MaxDepth(1001)}, false},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d", i), func(t *testing.T) {
+			_, err := Parse(s.input, s.opts...)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+		})
+	}
+}
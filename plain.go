@@ -0,0 +1,92 @@
+package fexpr
+
+// PlainExprGroup is an alternative ExprGroup representation that
+// uses only concrete field types instead of the interface{} Item.
+//
+// It is intended for easier serialization and for sharing the parse
+// result with other environments (eg. JS/WASM clients).
+//
+// Note that the precise token numbers (see PreciseNumbers) are not
+// serialized since they could be restored from the token literals.
+type PlainExprGroup struct {
+	Join JoinOp
+
+	// Expr is the group expression (nil for nested groups).
+	Expr *Expr
+
+	// Group holds the nested group items (nil for expressions).
+	Group []PlainExprGroup
+
+	// Unary is the custom unary operator item (see UnaryExpr).
+	Unary *PlainUnaryExpr `json:",omitempty"`
+}
+
+// PlainUnaryExpr is the plain representation of an UnaryExpr.
+type PlainUnaryExpr struct {
+	Op UnaryOp
+
+	// Group holds the operator operand as single group item
+	// (or as the nested group items).
+	Group []PlainExprGroup
+}
+
+// ToPlain converts the parsed filter groups into their plain representation.
+func ToPlain(exprs []ExprGroup) []PlainExprGroup {
+	result := make([]PlainExprGroup, 0, len(exprs))
+
+	for _, g := range exprs {
+		plain := PlainExprGroup{Join: g.Join}
+
+		switch v := g.Item.(type) {
+		case Expr:
+			expr := v
+			plain.Expr = &expr
+		case []ExprGroup:
+			plain.Group = ToPlain(v)
+		case UnaryExpr:
+			plain.Unary = &PlainUnaryExpr{Op: v.Op, Group: ToPlain(unaryOperand(v))}
+		}
+
+		result = append(result, plain)
+	}
+
+	return result
+}
+
+// FromPlain converts the plain filter groups back to their ExprGroup representation.
+//
+// An error is returned if a plain group doesn't have exactly one of Expr, Group and Unary set.
+func FromPlain(groups []PlainExprGroup) ([]ExprGroup, error) {
+	result := make([]ExprGroup, 0, len(groups))
+
+	for i, g := range groups {
+		switch {
+		case g.Expr != nil && g.Group == nil && g.Unary == nil:
+			result = append(result, ExprGroup{Join: g.Join, Item: *g.Expr})
+		case g.Expr == nil && g.Group != nil && g.Unary == nil:
+			nested, err := FromPlain(g.Group)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, ExprGroup{Join: g.Join, Item: nested})
+		case g.Expr == nil && g.Group == nil && g.Unary != nil:
+			operand, err := FromPlain(g.Unary.Group)
+			if err != nil {
+				return nil, err
+			}
+			if len(operand) == 0 {
+				return nil, errorf(ErrInvalidPlainGroup, "plain group %d must have non-empty Unary operand", i)
+			}
+
+			unary := UnaryExpr{Op: g.Unary.Op, Item: operand}
+			if len(operand) == 1 {
+				unary.Item = operand[0].Item // single condition
+			}
+			result = append(result, ExprGroup{Join: g.Join, Item: unary})
+		default:
+			return nil, errorf(ErrInvalidPlainGroup, "plain group %d must have exactly one of Expr, Group or Unary set", i)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package fexpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestToPlainAndFromPlain(t *testing.T) {
+	scenarios := []struct {
+		input        string
+		expectedJSON string
+	}{
+		{
+			`a = 1`,
+			`[{"Join":"\u0026\u0026","Expr":{"Left":{"Type":"identifier","Literal":"a"},"Op":"=","Right":{"Type":"number","Literal":"1"}},"Group":null}]`,
+		},
+		{
+			`a = 1 || (b > "c")`,
+			`[{"Join":"\u0026\u0026","Expr":{"Left":{"Type":"identifier","Literal":"a"},"Op":"=","Right":{"Type":"number","Literal":"1"}},"Group":null},{"Join":"||","Expr":null,"Group":[{"Join":"\u0026\u0026","Expr":{"Left":{"Type":"identifier","Literal":"b"},"Op":"\u003e","Right":{"Type":"text","Literal":"c"}},"Group":null}]}]`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			plain := ToPlain(exprs)
+
+			raw, err := json.Marshal(plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(raw) != s.expectedJSON {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expectedJSON, raw)
+			}
+
+			back, err := FromPlain(plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if backPrint, exprsPrint := fmt.Sprintf("%v", back), fmt.Sprintf("%v", exprs); backPrint != exprsPrint {
+				t.Fatalf("Expected %s, got %s", exprsPrint, backPrint)
+			}
+		})
+	}
+}
+
+func TestToPlainAndFromPlainUnary(t *testing.T) {
+	exprs, err := Parse(`~~ a = 1 && ~~ ~~ (b = 2 || c = 3)`, ScannerOptions(RegisterUnaryOp("~~", UnaryOpOptions{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := json.Marshal(ToPlain(exprs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var plain []PlainExprGroup
+	if err := json.Unmarshal(raw, &plain); err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := FromPlain(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backPrint, exprsPrint := fmt.Sprintf("%v", back), fmt.Sprintf("%v", exprs); backPrint != exprsPrint {
+		t.Fatalf("Expected %s, got %s", exprsPrint, backPrint)
+	}
+}
+
+func TestToPlainJSON(t *testing.T) {
+	exprs, err := Parse(`~~ a = 0.1`, ScannerOptions(
+		RegisterUnaryOp("~~", UnaryOpOptions{}),
+		PreciseNumbers(),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := json.Marshal(ToPlain(exprs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{"Join":"\u0026\u0026","Expr":null,"Group":null,"Unary":{"Op":"~~","Group":[{"Join":"\u0026\u0026","Expr":{"Left":{"Type":"identifier","Literal":"a"},"Op":"=","Right":{"Type":"number","Literal":"0.1"}},"Group":null}]}}]`
+	if string(raw) != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, raw)
+	}
+}
+
+func TestFromPlainInvalid(t *testing.T) {
+	scenarios := [][]PlainExprGroup{
+		{{Join: JoinAnd}},
+		{{Join: JoinAnd, Expr: &Expr{}, Group: []PlainExprGroup{}}},
+		{{Join: JoinAnd, Group: []PlainExprGroup{{Join: JoinAnd}}}},
+		{{Join: JoinAnd, Expr: &Expr{}, Unary: &PlainUnaryExpr{Op: "~~", Group: []PlainExprGroup{}}}},
+		{{Join: JoinAnd, Unary: &PlainUnaryExpr{Op: "~~"}}},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d", i), func(t *testing.T) {
+			if _, err := FromPlain(s); err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package fexpr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ToPrometheusMatchers converts the provided parsed filter into
+// a Prometheus label matchers selector, for example
+// `job = "api" && status !~ "5.."` becomes `{job="api", status!~"5.."}`.
+//
+// The filter must contain only `&&` joined conditions with plain label
+// name left operands and text or number right operands.
+//
+// The `=` and `!=` operators are converted to the label equality matchers.
+// The `~` and `!~` operators are converted to the regex matchers, aka. their
+// right operand is treated as RE2 regular expression (it is fully anchored
+// as usual for Prometheus). The `in`, `not in`, `like` and `not like`
+// operators are converted to equivalent regex matchers.
+//
+// An error is returned for all other operators, for the `||` conditions
+// and for an empty filter (it would match all series).
+func ToPrometheusMatchers(exprs []ExprGroup) (string, error) {
+	matchers := []string{}
+
+	if err := appendPrometheusMatchers(&matchers, exprs); err != nil {
+		return "", err
+	}
+
+	if len(matchers) == 0 {
+		return "", errorf(ErrEmpty, "cannot convert an empty filter expression to Prometheus label matchers")
+	}
+
+	return "{" + strings.Join(matchers, ", ") + "}", nil
+}
+
+// appendPrometheusMatchers appends the label matchers of the groups into dst.
+func appendPrometheusMatchers(dst *[]string, groups []ExprGroup) error {
+	for i, g := range groups {
+		if i > 0 && g.Join == JoinOr {
+			return errorf(ErrUnsupportedJoinOp, "the || conditions are not supported by Prometheus label matchers")
+		}
+
+		switch v := g.Item.(type) {
+		case Expr:
+			matcher, err := prometheusMatcher(v)
+			if err != nil {
+				return err
+			}
+			*dst = append(*dst, matcher)
+		case []ExprGroup:
+			if err := appendPrometheusMatchers(dst, v); err != nil {
+				return err
+			}
+		default:
+			return errorf(ErrUnsupportedItem, "unsupported expression group item %T", g.Item)
+		}
+	}
+
+	return nil
+}
+
+// prometheusMatcher converts a single expression into a label matcher.
+func prometheusMatcher(expr Expr) (string, error) {
+	if expr.Left.Type != TokenIdentifier || !isPlainFieldName(expr.Left.Literal) {
+		return "", errorf(ErrInvalidOperand, "expected label name, got %q (%s)", expr.Left.Literal, expr.Left.Type)
+	}
+
+	var values []Token
+	if expr.Right.Type == TokenList {
+		items, err := SplitList(expr.Right.Literal)
+		if err != nil {
+			return "", err
+		}
+		values = items
+	} else {
+		values = []Token{expr.Right}
+	}
+
+	for _, v := range values {
+		if v.Type != TokenText && v.Type != TokenNumber {
+			return "", errorf(ErrInvalidOperand, "expected text or number label value, got %q (%s)", v.Literal, v.Type)
+		}
+	}
+
+	var matcher string
+	var value string
+
+	switch expr.Op {
+	case SignEq, SignNeq:
+		matcher = string(expr.Op)
+		value = values[0].Literal
+	case SignLike, SignNlike:
+		if _, err := regexp.Compile(values[0].Literal); err != nil {
+			return "", errorf(ErrInvalidOperand, "invalid label value regex %q: %w", values[0].Literal, err)
+		}
+		matcher = "=~"
+		if expr.Op == SignNlike {
+			matcher = "!~"
+		}
+		value = values[0].Literal
+	case SignIn, SignNotIn:
+		alternatives := make([]string, len(values))
+		for i, v := range values {
+			alternatives[i] = regexp.QuoteMeta(v.Literal)
+		}
+		matcher = "=~"
+		if expr.Op == SignNotIn {
+			matcher = "!~"
+		}
+		value = strings.Join(alternatives, "|")
+	case SignSQLLike, SignSQLNlike:
+		matcher = "=~"
+		if expr.Op == SignSQLNlike {
+			matcher = "!~"
+		}
+		value = likeRegexp(values[0].Literal)
+	default:
+		return "", unsupportedSignOpError(expr.Op, "Prometheus label matchers")
+	}
+
+	return expr.Left.Literal + matcher + strconv.Quote(value), nil
+}
@@ -0,0 +1,73 @@
+package fexpr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestToPrometheusMatchers(t *testing.T) {
+	scenarios := []struct {
+		input          string
+		expectedError  bool
+		expectedResult string
+	}{
+		{`a = 1 || b = 2`, true, ``},
+		{`a = 1 && (b = 2 || c = 3)`, true, ``},
+		{`a.b = 1`, true, ``},
+		{`1 = a`, true, ``},
+		{`a = b`, true, ``},
+		{`a = null`, true, ``},
+		{`a > 1`, true, ``},
+		{`a ?= 1`, true, ``},
+		{`a ilike "x"`, true, ``},
+		{`a ~ "("`, true, ``},
+		{`a in (1, b)`, true, ``},
+		{`job = "api"`, false, `{job="api"}`},
+		{`job = "api" && status !~ "5.."`, false, `{job="api", status!~"5.."}`},
+		{`a != 1 && (b ~ "x|y" && c = 'say "hi"')`, false, `{a!="1", b=~"x|y", c="say \"hi\""}`},
+		{`a in ("x.y", 2) && b not in ("z")`, false, `{a=~"x\\.y|2", b!~"z"}`},
+		{`a like "x%" && b not like "_.y"`, false, `{a=~"x.*", b!~".\\.y"}`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := ToPrometheusMatchers(exprs)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if result != s.expectedResult {
+				t.Fatalf("Expected %s, got %s", s.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestToPrometheusMatchersEmpty(t *testing.T) {
+	scenarios := [][]ExprGroup{
+		nil,
+		{},
+		{{Join: JoinAnd, Item: []ExprGroup{}}},
+	}
+
+	for i, exprs := range scenarios {
+		t.Run(fmt.Sprintf("s%d", i), func(t *testing.T) {
+			result, err := ToPrometheusMatchers(exprs)
+
+			if !errors.Is(err, ErrEmpty) {
+				t.Fatalf("Expected ErrEmpty, got %v (%q)", err, result)
+			}
+		})
+	}
+}
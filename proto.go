@@ -0,0 +1,267 @@
+package fexpr
+
+import "encoding/binary"
+
+// The field numbers of the proto/fexpr.proto messages.
+const (
+	protoTokenType    = 1
+	protoTokenLiteral = 2
+
+	protoExprLeft  = 1
+	protoExprOp    = 2
+	protoExprRight = 3
+
+	protoGroupJoin  = 1
+	protoGroupExpr  = 2
+	protoGroupGroup = 3
+
+	protoFilterGroups = 1
+)
+
+// protobuf wire types
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+// MarshalProto encodes the provided parsed filter as a Protocol Buffers
+// `Filter` message defined in proto/fexpr.proto, allowing to transport
+// the parsed filters between services without re-parsing them.
+//
+// The result could be decoded with UnmarshalProto or with the types
+// generated from the proto schema.
+func MarshalProto(exprs []ExprGroup) ([]byte, error) {
+	return appendProtoFilter(nil, exprs, 0)
+}
+
+// UnmarshalProto decodes a Protocol Buffers `Filter` message
+// defined in proto/fexpr.proto into a parsed filter.
+//
+// The unknown fields are skipped.
+func UnmarshalProto(data []byte) ([]ExprGroup, error) {
+	return decodeProtoFilter(data, 0)
+}
+
+// appendProtoFilter appends the encoded Filter message fields of exprs to buf.
+func appendProtoFilter(buf []byte, exprs []ExprGroup, depth int) ([]byte, error) {
+	if depth >= defaultMaxDepth {
+		return nil, newLimitError(ErrMaxDepth, "the maximum allowed nesting depth of %d is exceeded", defaultMaxDepth, depth)
+	}
+
+	for _, g := range exprs {
+		group := appendProtoString(nil, protoGroupJoin, string(g.Join))
+
+		switch v := g.Item.(type) {
+		case Expr:
+			expr := appendProtoBytes(nil, protoExprLeft, appendProtoToken(nil, v.Left))
+			expr = appendProtoString(expr, protoExprOp, string(v.Op))
+			expr = appendProtoBytes(expr, protoExprRight, appendProtoToken(nil, v.Right))
+
+			group = appendProtoBytes(group, protoGroupExpr, expr)
+		case []ExprGroup:
+			nested, err := appendProtoFilter(nil, v, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			group = appendProtoBytes(group, protoGroupGroup, nested)
+		default:
+			return nil, errorf(ErrUnsupportedItem, "unsupported expression group item %T", g.Item)
+		}
+
+		buf = appendProtoBytes(buf, protoFilterGroups, group)
+	}
+
+	return buf, nil
+}
+
+// appendProtoToken appends the encoded Token message fields of t to buf.
+func appendProtoToken(buf []byte, t Token) []byte {
+	buf = appendProtoString(buf, protoTokenType, string(t.Type))
+	buf = appendProtoString(buf, protoTokenLiteral, t.Literal)
+
+	return buf
+}
+
+// appendProtoString appends a string field to buf
+// (empty strings are omitted as in proto3).
+func appendProtoString(buf []byte, field int, value string) []byte {
+	if value == "" {
+		return buf
+	}
+
+	return appendProtoBytes(buf, field, []byte(value))
+}
+
+// appendProtoBytes appends a length-delimited field to buf.
+func appendProtoBytes(buf []byte, field int, value []byte) []byte {
+	buf = appendProtoVarint(buf, uint64(field)<<3|protoWireBytes)
+	buf = appendProtoVarint(buf, uint64(len(value)))
+
+	return append(buf, value...)
+}
+
+// appendProtoVarint appends a varint encoded value to buf.
+func appendProtoVarint(buf []byte, value uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+
+	n := binary.PutUvarint(tmp[:], value)
+
+	return append(buf, tmp[:n]...)
+}
+
+// decodeProtoFilter decodes the Filter message data.
+func decodeProtoFilter(data []byte, depth int) ([]ExprGroup, error) {
+	if depth >= defaultMaxDepth {
+		return nil, newLimitError(ErrMaxDepth, "the maximum allowed nesting depth of %d is exceeded", defaultMaxDepth, depth)
+	}
+
+	result := []ExprGroup{}
+
+	err := decodeProtoFields(data, func(field int, value []byte) error {
+		if field != protoFilterGroups {
+			return nil
+		}
+
+		group, err := decodeProtoGroup(value, depth)
+		if err != nil {
+			return err
+		}
+
+		result = append(result, group)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// decodeProtoGroup decodes the ExprGroup message data.
+func decodeProtoGroup(data []byte, depth int) (ExprGroup, error) {
+	var group ExprGroup
+
+	err := decodeProtoFields(data, func(field int, value []byte) error {
+		switch field {
+		case protoGroupJoin:
+			group.Join = JoinOp(value)
+		case protoGroupExpr:
+			expr, err := decodeProtoExpr(value)
+			if err != nil {
+				return err
+			}
+			group.Item = expr
+		case protoGroupGroup:
+			nested, err := decodeProtoFilter(value, depth+1)
+			if err != nil {
+				return err
+			}
+			group.Item = nested
+		}
+
+		return nil
+	})
+	if err != nil {
+		return group, err
+	}
+
+	if group.Item == nil {
+		return group, errorf(ErrInvalidProto, "invalid proto expression group - missing expr or group item")
+	}
+
+	return group, nil
+}
+
+// decodeProtoExpr decodes the Expr message data.
+func decodeProtoExpr(data []byte) (Expr, error) {
+	var expr Expr
+
+	err := decodeProtoFields(data, func(field int, value []byte) error {
+		var err error
+
+		switch field {
+		case protoExprLeft:
+			expr.Left, err = decodeProtoToken(value)
+		case protoExprOp:
+			expr.Op = SignOp(value)
+		case protoExprRight:
+			expr.Right, err = decodeProtoToken(value)
+		}
+
+		return err
+	})
+
+	return expr, err
+}
+
+// decodeProtoToken decodes the Token message data.
+func decodeProtoToken(data []byte) (Token, error) {
+	var t Token
+
+	err := decodeProtoFields(data, func(field int, value []byte) error {
+		switch field {
+		case protoTokenType:
+			t.Type = TokenType(value)
+		case protoTokenLiteral:
+			t.Literal = string(value)
+		}
+
+		return nil
+	})
+
+	return t, err
+}
+
+// decodeProtoFields invokes fn for each length-delimited field of
+// a message data and skips the fields with other wire types.
+func decodeProtoFields(data []byte, fn func(field int, value []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errorf(ErrInvalidProto, "invalid proto field key")
+		}
+		data = data[n:]
+
+		field := int(key >> 3)
+		if field <= 0 {
+			return errorf(ErrInvalidProto, "invalid proto field number")
+		}
+
+		switch key & 7 {
+		case protoWireVarint:
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return errorf(ErrInvalidProto, "invalid proto varint field")
+			}
+			data = data[n:]
+		case protoWireFixed64:
+			if len(data) < 8 {
+				return errorf(ErrInvalidProto, "invalid proto fixed64 field")
+			}
+			data = data[8:]
+		case protoWireFixed32:
+			if len(data) < 4 {
+				return errorf(ErrInvalidProto, "invalid proto fixed32 field")
+			}
+			data = data[4:]
+		case protoWireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return errorf(ErrInvalidProto, "invalid proto length-delimited field")
+			}
+			data = data[n:]
+
+			if err := fn(field, data[:length]); err != nil {
+				return err
+			}
+			data = data[length:]
+		default:
+			return errorf(ErrInvalidProto, "unsupported proto wire type %d", key&7)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package fexpr
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	scenarios := []string{
+		`a = 1`,
+		`a = 1 && b != "" || (c ~ 'x' && (d in (1, 2)))`,
+		`@request.auth.id != "" && items[0].name ?= data->"key"`,
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s), func(t *testing.T) {
+			exprs, err := Parse(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := MarshalProto(exprs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := UnmarshalProto(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected, v := fmt.Sprintf("%v", exprs), fmt.Sprintf("%v", result); v != expected {
+				t.Fatalf("Expected %s, got %s", expected, v)
+			}
+		})
+	}
+}
+
+func TestMarshalProto(t *testing.T) {
+	exprs, err := Parse(`a = 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := MarshalProto(exprs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Filter{groups: [ExprGroup{join: "&&", expr: Expr{left: Token{"identifier", "a"}, op: "=", right: Token{"number", "1"}}}]}
+	expected := "0a27" + "0a022626" + "1221" +
+		"0a0f" + "0a0a6964656e746966696572" + "120161" +
+		"12013d" +
+		"1a0b" + "0a066e756d626572" + "120131"
+
+	if v := hex.EncodeToString(data); v != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, v)
+	}
+
+	if _, err := MarshalProto([]ExprGroup{{Join: JoinAnd, Item: 123}}); err == nil {
+		t.Fatal("Expected unsupported item error, got nil")
+	}
+}
+
+func TestUnmarshalProto(t *testing.T) {
+	scenarios := []struct {
+		hex           string
+		expectedError bool
+		expectedPrint string
+	}{
+		{"", false, `[]`},
+		// unknown varint, fixed64, fixed32 and bytes fields
+		{"08011100000000000000001d000000002201ff" + "0a0b" + "0a022626" + "1205" + "12013d" + "1801", false, `[{&& {{ } = { }}}]`},
+		// group without item
+		{"0a04" + "0a022626", true, `[]`},
+		// truncated length
+		{"0a05" + "0a022626", true, `[]`},
+		// invalid key
+		{"ff", true, `[]`},
+		// invalid field number
+		{"0200", true, `[]`},
+		// unsupported wire type
+		{"0b", true, `[]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.hex), func(t *testing.T) {
+			data, err := hex.DecodeString(s.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := UnmarshalProto(data)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if v := fmt.Sprintf("%v", result); v != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package fexpr
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Query represents a parsed ParseQuery combined query.
+type Query struct {
+	// Filter is the parsed "filter" section (nil if missing).
+	Filter []ExprGroup
+
+	// Sort is the parsed "sort" section (nil if missing).
+	Sort []SortField
+
+	// Limit is the "limit" section value (0 if missing, see HasLimit).
+	Limit int
+
+	// HasLimit indicates whether the "limit" section is specified
+	// (aka. to distinguish `limit: 0` from a missing limit).
+	HasLimit bool
+
+	// Offset is the "offset" section value (0 if missing).
+	Offset int
+}
+
+// SortField represents a single Query sort field.
+type SortField struct {
+	// Field is the sort field identifier.
+	Field string
+
+	// Desc indicates a descending sort order.
+	Desc bool
+}
+
+// ParseQuery parses a small combined query syntax consisting of
+// semicolon separated "key: value" sections, for example:
+//
+//	filter: status = "active" && total > 10; sort: -created, name asc; limit: 20; offset: 40
+//
+// The supported sections are:
+//   - filter - a filter expression parsed with Parse and the specified options
+//   - sort   - a comma separated list of identifiers with optional "-" (or "+")
+//     prefix or "desc" (or "asc") suffix
+//   - limit  - a non-negative integer
+//   - offset - a non-negative integer
+//
+// All sections are optional but each could be specified only once.
+//
+// Comments are allowed but they cannot contain `;`, aka. a comment cannot
+// swallow the following sections (eg. `filter: a = 1 // note; limit: 5`).
+func ParseQuery(text string, opts ...ParseOption) (Query, error) {
+	var query Query
+
+	sections, err := splitQuerySections(text, newParser(opts).scannerOpts)
+	if err != nil {
+		return query, err
+	}
+
+	seen := map[string]struct{}{}
+
+	for _, section := range sections {
+		key, value, err := cutQuerySection(section)
+		if err != nil {
+			return query, err
+		}
+
+		if _, ok := seen[key]; ok {
+			return query, errorf(ErrInvalidQuery, "duplicated query section %q", key)
+		}
+		seen[key] = struct{}{}
+
+		switch key {
+		case "filter":
+			query.Filter, err = Parse(value, opts...)
+		case "sort":
+			query.Sort, err = parseSortFields(value)
+		case "limit":
+			query.Limit, err = parseQueryInt(value)
+			query.HasLimit = err == nil
+		case "offset":
+			query.Offset, err = parseQueryInt(value)
+		default:
+			err = errors.New("unknown query section")
+		}
+
+		if err != nil {
+			return query, errorf(ErrInvalidQuery, "invalid query section %q: %w", key, err)
+		}
+	}
+
+	return query, nil
+}
+
+// splitQuerySections splits the query text into its non-empty
+// semicolon separated sections.
+//
+// The text is tokenized with the Scanner, so semicolons in
+// quoted text, groups or comments are not treated as separators.
+func splitQuerySections(text string, opts []ScannerOption) ([]string, error) {
+	result := []string{}
+
+	scanner := NewScanner(strings.NewReader(text), opts...)
+
+	// the section separator is an unexpected character for the scanner
+	// and the recovery mode would consume it together with the next section
+	scanner.recoverErrors = false
+
+	var start int
+
+	for {
+		t, err := scanner.Scan()
+		span := scanner.LastSpan()
+
+		// nothing was consumed and therefore no progress is possible
+		// (the section errors are reported by their own parsers)
+		if err != nil && span.End <= span.Start {
+			return nil, err
+		}
+
+		if t.Type == TokenComment && strings.Contains(t.Literal, ";") {
+			return nil, errorf(ErrInvalidQuery, "the query comments cannot contain \";\" (the comment ends only at the end of the line), got %q", t.Literal)
+		}
+
+		isSeparator := t.Type == TokenUnexpected && t.Literal == ";"
+
+		if !isSeparator && t.Type != TokenEOF {
+			continue
+		}
+
+		if section := strings.TrimSpace(text[start:span.Start]); section != "" {
+			result = append(result, section)
+		}
+
+		if t.Type == TokenEOF {
+			break
+		}
+
+		start = span.End
+	}
+
+	return result, nil
+}
+
+// cutQuerySection splits a single query section into its lowercased key and value.
+func cutQuerySection(section string) (string, string, error) {
+	i := strings.IndexByte(section, ':')
+	if i < 0 {
+		return "", "", errorf(ErrInvalidQuery, "invalid query section %q - expected \"key: value\" format", section)
+	}
+
+	key := strings.ToLower(strings.TrimSpace(section[:i]))
+	value := strings.TrimSpace(section[i+1:])
+
+	return key, value, nil
+}
+
+// parseSortFields parses a comma separated list of sort fields
+// (eg. `-created, name asc`).
+func parseSortFields(text string) ([]SortField, error) {
+	result := []SortField{}
+
+	scanner := NewScanner(strings.NewReader(text), SkipWhitespace())
+
+	var field *SortField  // the last parsed field (nil after a comma)
+	var hasDirection bool // whether the last parsed field has explicit direction
+	var prefix *Token     // the direction prefix of the next field
+
+	for {
+		t, err := scanner.Scan()
+
+		// the scanner doesn't have dedicated comma and direction prefix tokens
+		isComma := t.Type == TokenUnexpected && t.Literal == ","
+		isPrefix := (t.Type == TokenNumber && t.Literal == "-") || (t.Type == TokenUnexpected && t.Literal == "+")
+
+		if err != nil && !isComma && !isPrefix {
+			return nil, err
+		}
+
+		if t.Type == TokenEOF {
+			break
+		}
+
+		switch {
+		case isComma:
+			if field == nil {
+				return nil, errorf(ErrInvalidList, "missing sort field before \",\"")
+			}
+			field = nil
+			prefix = nil
+		case field == nil && isPrefix && prefix == nil:
+			prefix = &t
+		case field == nil && t.Type == TokenIdentifier:
+			result = append(result, SortField{Field: t.Literal, Desc: prefix != nil && prefix.Literal == "-"})
+			field = &result[len(result)-1]
+			hasDirection = prefix != nil
+		case field != nil && !hasDirection && (isWordToken(t, "asc") || isWordToken(t, "desc")):
+			field.Desc = isWordToken(t, "desc")
+			hasDirection = true
+		default:
+			return nil, errorf(ErrUnexpectedToken, "unexpected sort token %q (%s)", t.Literal, t.Type)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, errorf(ErrInvalidList, "empty sort fields list")
+	}
+
+	if field == nil {
+		return nil, errorf(ErrInvalidList, "missing sort field after the trailing \",\"")
+	}
+
+	return result, nil
+}
+
+// parseQueryInt parses a non-negative integer query section value.
+func parseQueryInt(text string) (int, error) {
+	v, err := strconv.Atoi(text)
+	if err != nil || v < 0 {
+		return 0, errorf(ErrInvalidQuery, "expected a non-negative integer, got %q", text)
+	}
+
+	return v, nil
+}
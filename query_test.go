@@ -0,0 +1,95 @@
+package fexpr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{``, false, `{[] [] 0 false 0}`},
+		{` ; ;`, false, `{[] [] 0 false 0}`},
+		{`filter`, true, ``},
+		{`unknown: 1`, true, ``},
+		{`limit: 1; limit: 2`, true, ``},
+		{`limit: -1`, true, ``},
+		{`limit: abc`, true, ``},
+		{`offset: 1.5`, true, ``},
+		{`filter: a >`, true, ``},
+		{`filter:`, true, ``},
+		{`sort:`, true, ``},
+		{`sort: a,`, true, ``},
+		{`sort: ,a`, true, ``},
+		{`sort: a b`, true, ``},
+		{`sort: -a desc`, true, ``},
+		{`sort: a asc desc`, true, ``},
+		{`sort: --a`, true, ``},
+		{`sort: "a"`, true, ``},
+		{`limit: 20`, false, `{[] [] 20 true 0}`},
+		{`FILTER: a = 1`, false, `{[{&& {{identifier a} = {number 1}}}] [] 0 false 0}`},
+		{
+			`filter: status = "a;b" && (total > 10); sort: -created, name asc, +id, title DESC; limit: 20; offset: 40;`,
+			false,
+			`{[{&& {{identifier status} = {text a;b}}} {&& [{&& {{identifier total} > {number 10}}}]}] [{created true} {name false} {id false} {title true}] 20 true 40}`,
+		},
+		{"filter: a = 1 // test; limit: 1\n; offset: 2", true, ``},
+		{"filter: a = 1 // test\n; offset: 2", false, `{[{&& {{identifier a} = {number 1}}}] [] 0 false 2}`},
+		{`limit: 0`, false, `{[] [] 0 true 0}`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := ParseQuery(s.input)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if s.expectedError {
+				return
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestParseQueryCommentSeparator(t *testing.T) {
+	_, err := ParseQuery(`limit: 1 # test; offset: 2`, ScannerOptions(CommentMarkers("#")))
+	if !errors.Is(err, ErrInvalidQuery) {
+		t.Fatalf("Expected ErrInvalidQuery, got %v", err)
+	}
+}
+
+func TestParseQueryRecoverErrors(t *testing.T) {
+	v, err := ParseQuery(`limit: 1;offset: 2`, ScannerOptions(RecoverErrors()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vPrint := fmt.Sprintf("%v", v); vPrint != `{[] [] 1 true 2}` {
+		t.Fatalf("Unexpected result %s", vPrint)
+	}
+}
+
+func TestParseQueryOptions(t *testing.T) {
+	v, err := ParseQuery(`filter: @a = 1 # test`+"\n; limit: 2", ScannerOptions(CommentMarkers("#")), Placeholders(PlaceholderMap{"@a": {Type: TokenNumber, Literal: "2"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vPrint := fmt.Sprintf("%v", v); vPrint != `{[{&& {{number 2} = {number 1}}}] [] 2 true 0}` {
+		t.Fatalf("Unexpected result %s", vPrint)
+	}
+}
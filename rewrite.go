@@ -0,0 +1,78 @@
+package fexpr
+
+import "strings"
+
+// MapFields returns a copy of the provided parsed filter with the
+// identifier operands (including the identifier items of the `in` lists)
+// renamed according to the fields map (eg. `{"created": "created_at"}`).
+//
+// Identifiers that are not in the fields map are left unchanged.
+func MapFields(exprs []ExprGroup, fields map[string]string) []ExprGroup {
+	return MapFieldsFunc(exprs, func(identifier string) string {
+		if mapped, ok := fields[identifier]; ok {
+			return mapped
+		}
+
+		return identifier
+	})
+}
+
+// MapFieldsFunc returns a copy of the provided parsed filter with
+// each identifier operand replaced with the result of fn.
+func MapFieldsFunc(exprs []ExprGroup, fn func(identifier string) string) []ExprGroup {
+	result := make([]ExprGroup, len(exprs))
+
+	for i, g := range exprs {
+		result[i] = g
+
+		result[i].Item = mapItemFields(g.Item, fn)
+	}
+
+	return result
+}
+
+// mapItemFields returns a copy of a single ExprGroup.Item with
+// each identifier operand replaced with the result of fn.
+func mapItemFields(item interface{}, fn func(identifier string) string) interface{} {
+	switch v := item.(type) {
+	case Expr:
+		v.Left = mapIdentifier(v.Left, fn)
+		v.Right = mapIdentifier(v.Right, fn)
+		return v
+	case []ExprGroup:
+		return MapFieldsFunc(v, fn)
+	case UnaryExpr:
+		v.Item = mapItemFields(v.Item, fn)
+		return v
+	default:
+		return item
+	}
+}
+
+// mapIdentifier replaces the token literal with the result of fn
+// if the token is an identifier (or each identifier item of a TokenList).
+func mapIdentifier(t Token, fn func(identifier string) string) Token {
+	switch t.Type {
+	case TokenIdentifier:
+		t.Literal = fn(t.Literal)
+	case TokenList:
+		items, err := SplitList(t.Literal)
+		if err != nil {
+			return t // not a valid list - leave it as it is
+		}
+
+		var sb strings.Builder
+
+		for i, item := range items {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+
+			writeToken(&sb, mapIdentifier(item, fn))
+		}
+
+		t.Literal = sb.String()
+	}
+
+	return t
+}
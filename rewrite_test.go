@@ -0,0 +1,57 @@
+package fexpr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMapFields(t *testing.T) {
+	exprs, err := Parse(`created > 1 && ("created" = name || (name ~ author.name))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := MapFields(exprs, map[string]string{
+		"created":     "created_at",
+		"author.name": "users.name",
+	})
+
+	expected := `created_at > 1 && ("created" = name || (name ~ users.name))`
+	if v := Stringify(result); v != expected {
+		t.Fatalf("Expected %s, got %s", expected, v)
+	}
+
+	// the original filter should remain unchanged
+	original := `created > 1 && ("created" = name || (name ~ author.name))`
+	if v := Stringify(exprs); v != original {
+		t.Fatalf("Expected the original filter to remain %s, got %s", original, v)
+	}
+}
+
+func TestMapFieldsList(t *testing.T) {
+	exprs, err := Parse(`a in (b, "c", 1) && d not in (a, b)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := MapFields(exprs, map[string]string{"a": "y", "b": "x"})
+
+	expected := `y in (x, "c", 1) && d not in (y, x)`
+	if v := Stringify(result); v != expected {
+		t.Fatalf("Expected %s, got %s", expected, v)
+	}
+}
+
+func TestMapFieldsFunc(t *testing.T) {
+	exprs, err := Parse(`a = b && (c ?= 'd' || 1 < e)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := MapFieldsFunc(exprs, strings.ToUpper)
+
+	expected := `A = B && (C ?= "d" || 1 < E)`
+	if v := Stringify(result); v != expected {
+		t.Fatalf("Expected %s, got %s", expected, v)
+	}
+}
@@ -0,0 +1,66 @@
+package fexpr
+
+// Sanitize removes (instead of rejecting) the expressions for which
+// allow returns false (eg. conditions referencing forbidden fields or
+// operators) and returns the cleaned filter together with the dropped
+// expressions.
+//
+// The dropped expressions are treated as if they were never part of
+// the filter, aka. `a = 1 && b = 2` with dropped `b = 2` becomes `a = 1`
+// and nested groups that end up empty are removed too.
+//
+// Note that this is a "best effort" operation and dropping a `&&`
+// operand makes the filter less restrictive (eg. `a = 1 && b = 2`
+// with dropped `b = 2` matches all `a = 1` records).
+func Sanitize(exprs []ExprGroup, allow func(expr Expr) bool) ([]ExprGroup, []Expr) {
+	dropped := []Expr{}
+
+	result := sanitizeGroups(exprs, allow, &dropped)
+
+	return result, dropped
+}
+
+// sanitizeGroups returns the groups without the not allowed expressions.
+func sanitizeGroups(groups []ExprGroup, allow func(expr Expr) bool, dropped *[]Expr) []ExprGroup {
+	result := []ExprGroup{}
+
+	for _, conjunction := range splitOr(groups) {
+		// the join of the first kept group of the conjunction
+		join := JoinOr
+		if len(result) == 0 {
+			join = JoinAnd
+		}
+
+		for _, g := range conjunction {
+			item := g.Item
+
+			switch v := g.Item.(type) {
+			case Expr:
+				if !allow(v) {
+					*dropped = append(*dropped, v)
+					continue
+				}
+			case []ExprGroup:
+				nested := sanitizeGroups(v, allow, dropped)
+				if len(nested) == 0 {
+					continue
+				}
+				item = nested
+			case UnaryExpr:
+				unary, ok := mapUnaryOperand(v, func(operand []ExprGroup) []ExprGroup {
+					return sanitizeGroups(operand, allow, dropped)
+				})
+				if !ok {
+					continue
+				}
+				item = unary
+			}
+
+			result = append(result, ExprGroup{Join: join, Item: item})
+
+			join = JoinAnd
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,51 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	// disallow the "secret" field and the like operators
+	allow := func(expr Expr) bool {
+		if expr.Left.Literal == "secret" || expr.Right.Literal == "secret" {
+			return false
+		}
+
+		return expr.Op != SignLike && expr.Op != SignNlike
+	}
+
+	scenarios := []struct {
+		input           string
+		expectedResult  string
+		expectedDropped string
+	}{
+		{`a = 1`, `a = 1`, `[]`},
+		{`secret = 1`, ``, `[{{identifier secret} = {number 1}}]`},
+		{`a = 1 && secret = 1`, `a = 1`, `[{{identifier secret} = {number 1}}]`},
+		{`secret = 1 && a = 1 || b = 2`, `a = 1 || b = 2`, `[{{identifier secret} = {number 1}}]`},
+		{`a = 1 || secret = 1 && b = 2`, `a = 1 || b = 2`, `[{{identifier secret} = {number 1}}]`},
+		{`a = 1 || secret = 1 || b ~ 2`, `a = 1`, `[{{identifier secret} = {number 1}} {{identifier b} ~ {number 2}}]`},
+		{`(secret = 1 || b ~ 2) && c = 3 || (d = 4 && (e = 5))`, `c = 3 || (d = 4 && (e = 5))`, `[{{identifier secret} = {number 1}} {{identifier b} ~ {number 2}}]`},
+		{`a = secret && (b = 1 || c !~ 2)`, `(b = 1)`, `[{{identifier a} = {identifier secret}} {{identifier c} !~ {number 2}}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, dropped := Sanitize(exprs, allow)
+
+			if v := Stringify(result); v != s.expectedResult {
+				t.Fatalf("Expected result %s, got %s", s.expectedResult, v)
+			}
+
+			if v := fmt.Sprintf("%v", dropped); v != s.expectedDropped {
+				t.Fatalf("Expected dropped %s, got %s", s.expectedDropped, v)
+			}
+		})
+	}
+}
@@ -3,12 +3,13 @@ package fexpr
 import (
 	"bufio"
 	"bytes"
-	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // eof represents a marker rune for the end of the reader.
@@ -46,6 +47,27 @@ const (
 	SignAnyLte   SignOp = "?<="
 	SignAnyGt    SignOp = "?>"
 	SignAnyGte   SignOp = "?>="
+
+	// array/all operators
+	SignAllEq    SignOp = "*="
+	SignAllNeq   SignOp = "*!="
+	SignAllLike  SignOp = "*~"
+	SignAllNlike SignOp = "*!~"
+	SignAllLt    SignOp = "*<"
+	SignAllLte   SignOp = "*<="
+	SignAllGt    SignOp = "*>"
+	SignAllGte   SignOp = "*>="
+
+	// list keyword operators
+	SignIn    SignOp = "in"
+	SignNotIn SignOp = "not in"
+
+	// SQL like keyword operators (the right operand is used as it is,
+	// aka. with the standard % and _ wildcards)
+	SignSQLLike   SignOp = "like"
+	SignSQLNlike  SignOp = "not like"
+	SignSQLIlike  SignOp = "ilike"
+	SignSQLNilike SignOp = "not ilike"
 )
 
 // TokenType represents a Token type.
@@ -63,29 +85,746 @@ const (
 	TokenText       TokenType = "text"  // ' or " quoted string
 	TokenGroup      TokenType = "group" // groupped/nested tokens
 	TokenComment    TokenType = "comment"
+	TokenList       TokenType = "list"  // comma separated operands list (see SplitList)
+	TokenUnary      TokenType = "unary" // custom unary prefix operator (see RegisterUnaryOp)
+	TokenNull       TokenType = "null"  // null literal operand (see FeatureNullLiteral)
+	TokenBool       TokenType = "bool"  // true or false literal operand (see FeatureBoolLiterals)
 )
 
 // Token represents a single scanned literal (one or more combined runes).
 type Token struct {
 	Type    TokenType
 	Literal string
+
+	// Number holds the exact value of the number tokens
+	// scanned with the PreciseNumbers option (nil otherwise).
+	//
+	// It is not part of the serialized token since it could be
+	// always restored from the Literal.
+	Number *big.Rat `json:"-"`
+}
+
+// String returns the `{type literal}` representation of the token
+// (or `{type literal number}` if the token has precise Number).
+func (t Token) String() string {
+	if t.Number != nil {
+		return fmt.Sprintf("{%s %s %s}", t.Type, t.Literal, t.Number)
+	}
+
+	return fmt.Sprintf("{%s %s}", t.Type, t.Literal)
 }
 
 // Scanner represents a filter and lexical scanner.
 type Scanner struct {
 	r *bufio.Reader
+
+	// peeked holds the already scanned but not yet consumed tokens
+	peeked []scanResult
+
+	// commentMarkers holds the additional line comment introducers
+	commentMarkers []string
+
+	// skip holds the token types that are not returned by Scan
+	skip []TokenType
+
+	// unicodeWhitespace enables the unicode space characters as whitespace
+	unicodeWhitespace bool
+
+	// started indicates whether the start of the input (aka. the BOM) was checked
+	started bool
+
+	// pos is the byte offset of the underlying reader position
+	pos int
+
+	// lastWidth is the byte width of the last read rune (0 if it can't be unread)
+	lastWidth int
+
+	// lastSpan is the span of the last token returned by Scan
+	lastSpan Span
+
+	// source is the scanner's io.Reader (used for the Remaining calculation)
+	source io.Reader
+
+	// recoverErrors enables the error-recovery scan mode
+	recoverErrors bool
+
+	// errors holds the recorded errors in error-recovery scan mode
+	errors []error
+
+	// maxTextLength is the maximum quoted text length in bytes (0 - no limit)
+	maxTextLength int
+
+	// maxInputLength is the maximum input length in bytes (0 - no limit)
+	maxInputLength int
+
+	// inputExceeded indicates whether the read stopped because of maxInputLength
+	inputExceeded bool
+
+	// exceededLength is the input length observed when maxInputLength was exceeded
+	exceededLength int
+
+	// customOps holds the registered custom sign, join and unary operators (sorted longest first)
+	customOps []customOp
+
+	// textUnescape is the quoted text tokens unescape mode
+	textUnescape TextUnescapeMode
+
+	// preciseNumbers enables the exact *big.Rat value in the number tokens Number
+	preciseNumbers bool
+
+	// literalScanners holds the registered custom literal scanners keyed by their start rune
+	literalScanners map[rune]LiteralScanFunc
+
+	// tokenHooks holds the registered OnToken callbacks
+	tokenHooks []TokenHook
+}
+
+// ScanError represents a recorded invalid token error
+// in error-recovery scan mode (see RecoverErrors).
+type ScanError struct {
+	// Span is the invalid token byte offsets range.
+	Span Span
+
+	// Err is the original scan error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("%v (at %d:%d)", e.Err, e.Span.Start, e.Span.End)
+}
+
+// Unwrap returns the original scan error.
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// errorMessage implements the messageError interface.
+func (e *ScanError) errorMessage() ErrorMessage {
+	return ErrorMessage{Code: ErrorCode(e.Err), Template: "%v (at %d:%d)", Args: []interface{}{e.Err, e.Span.Start, e.Span.End}}
+}
+
+// ScannerOption defines a single Scanner configuration option.
+type ScannerOption func(s *Scanner)
+
+// CommentMarkers registers additional line comment introducers
+// (eg. `CommentMarkers("#", "--")`) alongside the default `//`.
+//
+// The comment markers take precedence over the other tokens,
+// so for example registering "#" disables the `#` prefixed identifiers.
+func CommentMarkers(markers ...string) ScannerOption {
+	return func(s *Scanner) {
+		for _, m := range markers {
+			if m != "" {
+				s.commentMarkers = append(s.commentMarkers, m)
+			}
+		}
+	}
+}
+
+// SignOpOptions defines the parsing behavior of a custom sign operator
+// (see RegisterSignOp).
+type SignOpOptions struct {
+	// List indicates that the right operand of the operator is
+	// a parenthesized values list (similar to `in`).
+	List bool
+}
+
+// JoinOpOptions defines the metadata of a custom join operator
+// (see RegisterJoinOp).
+type JoinOpOptions struct {
+	// Metadata holds arbitrary embedder data associated with the
+	// operator (eg. its evaluation function or documentation).
+	Metadata interface{}
+}
+
+// customOp represents a single registered custom sign, join or unary operator.
+type customOp struct {
+	literal      string
+	tokenType    TokenType
+	signOptions  SignOpOptions
+	joinOptions  JoinOpOptions
+	unaryOptions UnaryOpOptions
+}
+
+// RegisterSignOp registers a custom sign operator for the scanner
+// (eg. `RegisterSignOp("@>", SignOpOptions{})`) that is returned as
+// TokenSign and parsed as any other sign operator into Expr.Op,
+// so that it could be handled by the embedder's own converters.
+//
+// Similar to the comment markers, the custom sign operators take
+// precedence over the other tokens (the longest one wins).
+//
+// RegisterSignOp panics if literal is empty or contains letters,
+// digits, whitespaces, quotes or parenthesis.
+func RegisterSignOp(literal string, opts SignOpOptions) ScannerOption {
+	if !isCustomOpLiteral(literal) {
+		panic(fmt.Sprintf("fexpr: invalid custom sign operator %q", literal))
+	}
+
+	return func(s *Scanner) {
+		s.registerOp(customOp{literal: literal, tokenType: TokenSign, signOptions: opts})
+	}
+}
+
+// RegisterJoinOp registers a custom binary join operator for the scanner
+// (eg. `RegisterJoinOp("=>", JoinOpOptions{})` for an implication operator)
+// that is returned as TokenJoin and parsed into ExprGroup.Join.
+//
+// Similar to the custom sign operators, the custom join operators take
+// precedence over the other tokens (the longest one wins).
+//
+// Note that the package helpers and converters understand only the
+// `&&` and `||` joins, so the custom joins should be resolved in advance
+// by the embedder (eg. rewriting `a => b` into `(!a) || b`).
+//
+// RegisterJoinOp panics if literal is empty or contains letters,
+// digits, whitespaces, quotes or parenthesis.
+func RegisterJoinOp(literal string, opts JoinOpOptions) ScannerOption {
+	if !isCustomOpLiteral(literal) {
+		panic(fmt.Sprintf("fexpr: invalid custom join operator %q", literal))
+	}
+
+	return func(s *Scanner) {
+		s.registerOp(customOp{literal: literal, tokenType: TokenJoin, joinOptions: opts})
+	}
+}
+
+// LookupJoinOp returns the options of a registered custom join operator.
+func (s *Scanner) LookupJoinOp(op JoinOp) (JoinOpOptions, bool) {
+	for _, custom := range s.customOps {
+		if custom.tokenType == TokenJoin && custom.literal == string(op) {
+			return custom.joinOptions, true
+		}
+	}
+
+	return JoinOpOptions{}, false
+}
+
+// registerOp registers the custom operator keeping the longest
+// operators first and replacing the existing registration.
+func (s *Scanner) registerOp(op customOp) {
+	i := 0
+	for i < len(s.customOps) && len(s.customOps[i].literal) >= len(op.literal) {
+		if s.customOps[i].literal == op.literal {
+			s.customOps[i] = op
+			return
+		}
+		i++
+	}
+
+	s.customOps = append(s.customOps, customOp{})
+	copy(s.customOps[i+1:], s.customOps[i:])
+	s.customOps[i] = op
+}
+
+// isCustomOpLiteral checks if literal could be used as custom operator.
+func isCustomOpLiteral(literal string) bool {
+	if literal == "" {
+		return false
+	}
+
+	for _, ch := range literal {
+		if isLetterRune(ch) || isDigitRune(ch) || isWhitespaceRune(ch) || unicode.IsSpace(ch) ||
+			isTextStartRune(ch) || isGroupStartRune(ch) || ch == ')' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// customOp returns the registered custom operator at the
+// current reader position (or false if there is none).
+func (s *Scanner) customOp() (customOp, bool) {
+	for _, op := range s.customOps {
+		if b, _ := s.r.Peek(len(op.literal)); string(b) == op.literal {
+			return op, true
+		}
+	}
+
+	return customOp{}, false
+}
+
+// isListSignOp checks if op is a list operator (`in`, `not in`
+// or a registered custom list sign operator).
+func (s *Scanner) isListSignOp(op SignOp) bool {
+	if isListSignOp(op) {
+		return true
+	}
+
+	for _, custom := range s.customOps {
+		if custom.tokenType == TokenSign && custom.literal == string(op) {
+			return custom.signOptions.List
+		}
+	}
+
+	return false
+}
+
+// LiteralScanFunc scans a single custom literal token from r,
+// which is positioned at the literal start rune (aka. the first
+// ReadRune call returns the registered start rune).
+//
+// The returned token could be of any custom type (eg. TokenType("color"))
+// and it is accepted by the parser as expression operand.
+type LiteralScanFunc func(r io.RuneScanner) (Token, error)
+
+// RegisterLiteral registers a custom literal scanner for the tokens
+// starting with the specified rune (eg. `{` for JSON snippets or `#`
+// for color literals), allowing new literal types to be introduced
+// without patching the package.
+//
+// The literal scanners take precedence over the default tokens starting
+// with the same rune (eg. registering `#` disables the `#` prefixed identifiers),
+// but not over the comment markers and the custom sign operators.
+func RegisterLiteral(start rune, fn LiteralScanFunc) ScannerOption {
+	return func(s *Scanner) {
+		if s.literalScanners == nil {
+			s.literalScanners = map[rune]LiteralScanFunc{}
+		}
+
+		s.literalScanners[start] = fn
+	}
+}
+
+// scannerRuneReader is an io.RuneScanner adapter of the Scanner reader
+// that keeps the scanner's position in sync (used by the literal scanners).
+type scannerRuneReader struct {
+	s *Scanner
+}
+
+// ReadRune implements the io.RuneReader interface.
+func (r scannerRuneReader) ReadRune() (rune, int, error) {
+	ch := r.s.read()
+	if ch == eof && r.s.lastWidth == 0 {
+		return eof, 0, io.EOF
+	}
+
+	return ch, r.s.lastWidth, nil
+}
+
+// UnreadRune implements the io.RuneScanner interface.
+func (r scannerRuneReader) UnreadRune() error {
+	return r.s.unread()
+}
+
+// scanCustomLiteral scans a token with the literal scanner registered for ch.
+func (s *Scanner) scanCustomLiteral(fn LiteralScanFunc, ch rune) (Token, error) {
+	start := s.pos
+
+	t, err := fn(scannerRuneReader{s})
+
+	// nothing was consumed and therefore no progress is possible
+	if s.pos == start {
+		s.read()
+		if err == nil {
+			err = errorf(ErrInvalidLiteral, "the literal scanner of %q didn't consume any input", ch)
+		}
+		return Token{Type: TokenUnexpected, Literal: string(ch)}, err
+	}
+
+	return t, err
+}
+
+// scanResult represents a single buffered Scanner.Scan result.
+type scanResult struct {
+	token Token
+	span  Span
+	err   error
+}
+
+// Span represents the byte offsets range of a token in the scanned input.
+type Span struct {
+	Start int
+	End   int
+}
+
+// SkipWhitespace excludes the whitespace tokens from the Scan and Peek results.
+func SkipWhitespace() ScannerOption {
+	return func(s *Scanner) {
+		s.skip = append(s.skip, TokenWS)
+	}
+}
+
+// SkipComments excludes the comment tokens from the Scan and Peek results.
+func SkipComments() ScannerOption {
+	return func(s *Scanner) {
+		s.skip = append(s.skip, TokenComment)
+	}
+}
+
+// UnicodeWhitespace enables treating all unicode space characters
+// (eg. the non-breaking space U+00A0) as whitespace, which is usually
+// needed for filters pasted from rich-text fields.
+func UnicodeWhitespace() ScannerOption {
+	return func(s *Scanner) {
+		s.unicodeWhitespace = true
+	}
+}
+
+// TextUnescapeMode defines how the escape sequences of the quoted
+// text tokens are processed (see TextUnescape).
+type TextUnescapeMode int
+
+// supported text unescape modes
+const (
+	// UnescapeQuotes removes the wrapping quotes and the escape prefix
+	// of the inner matching quotes (eg. `"a\"b\%"` => `a"b\%`).
+	// All other backslashes are preserved (this is the default mode).
+	UnescapeQuotes TextUnescapeMode = iota
+
+	// UnescapeNone removes only the wrapping quotes and keeps all
+	// backslashes as they are (eg. `"a\"b\%"` => `a\"b\%`).
+	UnescapeNone
+
+	// UnescapeStrict removes the wrapping quotes and processes the
+	// `\\`, `\'`, `\"`, `\n`, `\r` and `\t` escape sequences.
+	// The SQL like `\%` and `\_` escape sequences are preserved as they are
+	// and any other escape sequence is reported as an error.
+	//
+	// Note that in this mode a text could end with an escaped
+	// backslash (eg. `"a\\"` => `a\`).
+	UnescapeStrict
+
+	// UnescapeRaw keeps the quoted text as it is in the source,
+	// including its wrapping quotes (eg. `"a\"b"` => `"a\"b"`).
+	UnescapeRaw
+)
+
+// TextUnescape changes the escape sequences processing of the
+// quoted text tokens (default to UnescapeQuotes).
+//
+// Note that the package helpers that write filters (eg. Stringify)
+// always produce text in the default UnescapeQuotes syntax.
+func TextUnescape(mode TextUnescapeMode) ScannerOption {
+	return func(s *Scanner) {
+		s.textUnescape = mode
+	}
+}
+
+// PreciseNumbers attaches the exact *big.Rat value of the number
+// tokens in their Token.Number (eg. `0.1` => big.NewRat(1, 10)),
+// so that the embedders could compare monetary and other decimal
+// values without the float64 rounding.
+//
+// The package converters preserve the precise numbers by
+// representing their values as json.Number instead of float64.
+//
+// Note that the list items (see SplitList) are scanned without Number.
+func PreciseNumbers() ScannerOption {
+	return func(s *Scanner) {
+		s.preciseNumbers = true
+	}
+}
+
+// TokenHook is an OnToken callback that is invoked with the
+// returned token, its byte offsets range and its scan error (if any).
+type TokenHook func(t Token, span Span, err error) error
+
+// OnToken registers a callback that is invoked for every token returned
+// by Scan (including the final EOF and the errored tokens, but not the
+// skipped or only peeked ones), so that the embedders could collect
+// telemetry, build trace logs or enforce custom rules.
+//
+// A non-nil hook error is returned by Scan instead of the token's own
+// scan error (and the following hooks are not invoked).
+//
+// Note that Parse scans the nested groups and lists with separate
+// scanners, so their tokens span offsets are relative to the group content.
+func OnToken(fn TokenHook) ScannerOption {
+	return func(s *Scanner) {
+		if fn != nil {
+			s.tokenHooks = append(s.tokenHooks, fn)
+		}
+	}
+}
+
+// RecoverErrors enables the error-recovery scan mode.
+//
+// In this mode, instead of returning an error, the scanner records it
+// (see Scanner.Errors), consumes the remaining invalid runes until
+// the next whitespace, operator or group start and returns them as
+// a single TokenUnexpected token so that the scanning could continue.
+func RecoverErrors() ScannerOption {
+	return func(s *Scanner) {
+		s.recoverErrors = true
+	}
+}
+
+// MaxTextLength limits the maximum length in bytes of the quoted text
+// tokens (without the wrapping quotes).
+//
+// The scanning of a too long text stops with an error as soon as the
+// limit is exceeded, without buffering the rest of the text.
+// Non-positive max disables the limit.
+func MaxTextLength(max int) ScannerOption {
+	return func(s *Scanner) {
+		s.maxTextLength = max
+	}
+}
+
+// MaxInputLength limits the maximum length in bytes of the scanned input.
+//
+// The scanner returns an error as soon as the limit is exceeded,
+// without reading the rest of the input. Non-positive max disables the limit.
+func MaxInputLength(max int) ScannerOption {
+	return func(s *Scanner) {
+		s.maxInputLength = max
+	}
+}
+
+// NewScanner creates and returns a new scanner instance with the specified io.Reader
+// and optional scanner options.
+func NewScanner(r io.Reader, opts ...ScannerOption) *Scanner {
+	s := &Scanner{r: bufio.NewReader(r), source: r}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
-// NewScanner creates and returns a new scanner instance with the specified io.Reader.
-func NewScanner(r io.Reader) *Scanner {
-	return &Scanner{bufio.NewReader(r)}
+// Reset discards the scanner's buffered state and switches it to read from r,
+// allowing a single scanner instance to be reused for multiple inputs.
+func (s *Scanner) Reset(r io.Reader) {
+	s.r.Reset(r)
+	s.source = r
+	s.lastSpan = Span{}
+	s.errors = nil
+	s.inputExceeded = false
+	s.exceededLength = 0
+	s.peeked = s.peeked[:0]
+	s.started = false
+	s.pos = 0
+	s.lastWidth = 0
 }
 
 // Scan reads and returns the next available token value from the scanner's buffer.
 func (s *Scanner) Scan() (Token, error) {
+	var result scanResult
+
+	if len(s.peeked) > 0 {
+		result = s.peeked[0]
+		s.peeked = s.peeked[1:]
+	} else {
+		result = s.scan()
+	}
+
+	s.lastSpan = result.span
+
+	for _, hook := range s.tokenHooks {
+		if err := hook(result.token, result.span, result.err); err != nil {
+			return result.token, err
+		}
+	}
+
+	return result.token, result.err
+}
+
+// Errors returns the recorded errors in error-recovery scan mode (see RecoverErrors).
+func (s *Scanner) Errors() []error {
+	return s.errors
+}
+
+// Pos returns the byte offset of the scanner position in the input,
+// aka. the end of the last scanned token (peeked tokens are not counted).
+func (s *Scanner) Pos() int {
+	return s.lastSpan.End
+}
+
+// LastSpan returns the byte offsets range of the last scanned token.
+func (s *Scanner) LastSpan() Span {
+	return s.lastSpan
+}
+
+// Remaining returns the number of the input bytes after the scanner
+// position (see Pos) or -1 if it is unknown, aka. the scanner's reader
+// doesn't report its unread length (like strings.Reader and bytes.Reader do).
+func (s *Scanner) Remaining() int {
+	sized, ok := s.source.(interface{ Len() int })
+	if !ok {
+		return -1
+	}
+
+	// the peeked tokens are already read from the reader
+	peeked := s.pos - s.Pos()
+
+	return sized.Len() + s.r.Buffered() + peeked
+}
+
+// Peek returns the next available token without consuming it,
+// aka. the following Scan call will return the same token.
+func (s *Scanner) Peek() (Token, error) {
+	return s.PeekN(1)
+}
+
+// PeekN returns the n-th (starting from 1) next available token
+// without consuming it or any of the tokens before it.
+//
+// Peeking past the end of the input returns the EOF token.
+func (s *Scanner) PeekN(n int) (Token, error) {
+	if n < 1 {
+		return Token{}, fmt.Errorf("invalid peek position %d", n)
+	}
+
+	for len(s.peeked) < n {
+		s.peeked = append(s.peeked, s.scan())
+	}
+
+	result := s.peeked[n-1]
+
+	return result.token, result.err
+}
+
+// Tokenize scans the entire text and returns all of its tokens
+// (without the final EOF token).
+//
+// Tokens with type listed in ignore are excluded from the result
+// (eg. `Tokenize(text, TokenWS, TokenComment)`).
+//
+// On error, Tokenize returns the tokens scanned so far, including
+// the errored one.
+func Tokenize(text string, ignore ...TokenType) ([]Token, error) {
+	result := []Token{}
+	s := NewScanner(strings.NewReader(text))
+
+	for {
+		t, err := s.Scan()
+		if err != nil {
+			return append(result, t), err
+		}
+
+		if t.Type == TokenEOF {
+			break
+		}
+
+		if !isTokenTypeIn(t.Type, ignore) {
+			result = append(result, t)
+		}
+	}
+
+	return result, nil
+}
+
+// ScanTokens is a bufio.SplitFunc that splits the input into fexpr
+// tokens, allowing them to be pulled incrementally from network
+// streams or large files with a bufio.Scanner.
+//
+// The returned token is the raw unprocessed source of the scanned
+// token (eg. text tokens are returned with their quotes) and could
+// be further processed with NewScanner.
+func ScanTokens(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	s := NewScanner(bytes.NewReader(data))
+
+	scanErr := s.scan().err
+
+	advance = s.pos
+
+	// the token could continue in the next data chunk
+	if !atEOF && advance >= len(data) {
+		return 0, nil, nil
+	}
+
+	if scanErr != nil {
+		return 0, nil, scanErr
+	}
+
+	return advance, data[:advance], nil
+}
+
+// isTokenTypeIn checks if tokenType is one of the specified types.
+func isTokenTypeIn(tokenType TokenType, types []TokenType) bool {
+	for _, t := range types {
+		if t == tokenType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scan reads and returns the next not skipped token directly from the underlying reader.
+func (s *Scanner) scan() scanResult {
+	if !s.started {
+		s.started = true
+
+		if t, err := s.scanBOM(); err != nil {
+			return scanResult{token: t, span: Span{Start: 0, End: s.pos}, err: err}
+		}
+	}
+
+	for {
+		start := s.pos
+
+		t, err := s.scanToken()
+
+		// the token is incomplete - no recovery is possible
+		if s.inputExceeded {
+			err = newLimitError(ErrMaxInputLength, "input exceeds the maximum allowed length of %d bytes", s.maxInputLength, s.exceededLength)
+			return scanResult{token: t, span: Span{Start: start, End: s.pos}, err: err}
+		}
+
+		if err != nil && s.recoverErrors {
+			t = Token{Type: TokenUnexpected, Literal: t.Literal + s.scanUntilSync()}
+			s.errors = append(s.errors, &ScanError{Span: Span{Start: start, End: s.pos}, Err: err})
+			err = nil
+		}
+
+		if err != nil || !isTokenTypeIn(t.Type, s.skip) {
+			return scanResult{token: t, span: Span{Start: start, End: s.pos}, err: err}
+		}
+	}
+}
+
+// scanUntilSync consumes and returns all runes until the next whitespace,
+// operator, group start or EOF is reached (aka. the error-recovery sync point).
+func (s *Scanner) scanUntilSync() string {
+	var buf bytes.Buffer
+
+	for {
+		ch := s.read()
+
+		if ch == eof {
+			break
+		}
+
+		if s.isWhitespace(ch) || isSignStartRune(ch) || isJoinStartRune(ch) || isGroupStartRune(ch) {
+			s.unread()
+			break
+		}
+
+		buf.WriteRune(ch)
+	}
+
+	return buf.String()
+}
+
+// scanToken reads and returns the next token directly from the underlying reader.
+func (s *Scanner) scanToken() (Token, error) {
+	if marker := s.commentMarker(); marker != "" {
+		s.discard(len(marker))
+		return s.scanCommentText()
+	}
+
+	if op, ok := s.customOp(); ok {
+		s.discard(len(op.literal))
+		return Token{Type: op.tokenType, Literal: op.literal}, nil
+	}
+
 	ch := s.read()
 
-	if isWhitespaceRune(ch) {
+	if fn, ok := s.literalScanners[ch]; ok && ch != eof {
+		s.unread()
+		return s.scanCustomLiteral(fn, ch)
+	}
+
+	if s.isWhitespace(ch) {
 		s.unread()
 		return s.scanWhitespace()
 	}
@@ -129,7 +868,7 @@ func (s *Scanner) Scan() (Token, error) {
 		return Token{Type: TokenEOF, Literal: ""}, nil
 	}
 
-	return Token{Type: TokenUnexpected, Literal: string(ch)}, fmt.Errorf("unexpected character %q", ch)
+	return Token{Type: TokenUnexpected, Literal: string(ch)}, errorf(ErrUnexpectedCharacter, "unexpected character %q", ch)
 }
 
 // scanWhitespace consumes all contiguous whitespace runes.
@@ -145,7 +884,7 @@ func (s *Scanner) scanWhitespace() (Token, error) {
 			break
 		}
 
-		if !isWhitespaceRune(ch) {
+		if !s.isWhitespace(ch) {
 			s.unread()
 			break
 		}
@@ -161,34 +900,141 @@ func (s *Scanner) scanWhitespace() (Token, error) {
 func (s *Scanner) scanIdentifier() (Token, error) {
 	var buf bytes.Buffer
 
+	var prevCh rune
+
 	// Read every subsequent identifier rune into the buffer.
 	// Non-ident runes and EOF will cause the loop to exit.
 	for {
+		// JSON arrow operator access, aka. data->"key" or data->>"key"
+		if next, _ := s.r.Peek(2); string(next) == "->" {
+			if err := s.scanIdentifierArrow(&buf); err != nil {
+				return Token{Type: TokenIdentifier, Literal: buf.String()}, err
+			}
+
+			prevCh = '>'
+			continue
+		}
+
 		ch := s.read()
 
 		if ch == eof {
 			break
 		}
 
-		if !isIdentifierStartRune(ch) && !isDigitRune(ch) && ch != '.' && ch != ':' {
+		// bracket index access, aka. items[0] or data["key"]
+		if ch == '[' {
+			buf.WriteRune(ch)
+
+			if err := s.scanIdentifierIndex(&buf); err != nil {
+				return Token{Type: TokenIdentifier, Literal: buf.String()}, err
+			}
+
+			prevCh = ']'
+			continue
+		}
+
+		// wildcard path segment, aka. tags.*
+		isWildcard := ch == '*' && prevCh == '.'
+
+		if !isWildcard && !isIdentifierStartRune(ch) && !isDigitRune(ch) && ch != '.' && ch != ':' {
 			s.unread()
 			break
 		}
 
 		// write the ident rune
 		buf.WriteRune(ch)
+		prevCh = ch
 	}
 
 	literal := buf.String()
 
 	var err error
 	if !isIdentifier(literal) {
-		err = fmt.Errorf("Invalid identifier %q", literal)
+		err = errorf(ErrInvalidIdentifier, "Invalid identifier %q", literal)
 	}
 
 	return Token{Type: TokenIdentifier, Literal: literal}, err
 }
 
+// scanIdentifierArrow consumes the remaining runes of an identifier
+// JSON arrow operator access (aka. `->"key"` or `->>0`).
+func (s *Scanner) scanIdentifierArrow(buf *bytes.Buffer) error {
+	buf.WriteRune(s.read()) // -
+	buf.WriteRune(s.read()) // >
+
+	if ch := s.read(); ch == '>' {
+		buf.WriteRune(ch)
+	} else if ch != eof {
+		s.unread()
+	}
+
+	ch := s.read()
+
+	if isTextStartRune(ch) {
+		s.unread()
+
+		t, err := s.scanText(true)
+		buf.WriteString(t.Literal)
+		return err
+	}
+
+	if !isDigitRune(ch) {
+		if ch != eof {
+			s.unread()
+		}
+		return errorf(ErrInvalidIdentifier, "invalid identifier arrow key %q - expected number or quoted text", buf.String())
+	}
+
+	for isDigitRune(ch) {
+		buf.WriteRune(ch)
+		ch = s.read()
+	}
+
+	if ch != eof {
+		s.unread()
+	}
+
+	return nil
+}
+
+// scanIdentifierIndex consumes the remaining runes of an identifier
+// bracket index (aka. the `0]` or `"key"]` after the opening bracket).
+func (s *Scanner) scanIdentifierIndex(buf *bytes.Buffer) error {
+	ch := s.read()
+
+	if isTextStartRune(ch) {
+		s.unread()
+
+		t, err := s.scanText(true)
+		buf.WriteString(t.Literal)
+		if err != nil {
+			return err
+		}
+	} else if isDigitRune(ch) {
+		for isDigitRune(ch) {
+			buf.WriteRune(ch)
+			ch = s.read()
+		}
+		s.unread()
+	} else {
+		if ch != eof {
+			s.unread()
+		}
+		return errorf(ErrInvalidIdentifier, "invalid identifier index %q - expected number or quoted text", buf.String())
+	}
+
+	if ch := s.read(); ch != ']' {
+		if ch != eof {
+			s.unread()
+		}
+		return errorf(ErrInvalidIdentifier, "invalid identifier index %q - missing closing bracket", buf.String())
+	}
+
+	buf.WriteRune(']')
+
+	return nil
+}
+
 // scanNumber consumes all contiguous digit runes.
 func (s *Scanner) scanNumber() (Token, error) {
 	var buf bytes.Buffer
@@ -205,7 +1051,7 @@ func (s *Scanner) scanNumber() (Token, error) {
 			break
 		}
 
-		if !isDigitRune(ch) && ch != '.' {
+		if !isNumberPartRune(ch) {
 			s.unread()
 			break
 		}
@@ -217,11 +1063,16 @@ func (s *Scanner) scanNumber() (Token, error) {
 	literal := buf.String()
 
 	var err error
+	var number *big.Rat
 	if !isNumber(literal) {
-		err = fmt.Errorf("invalid number %q", literal)
+		err = errorf(ErrInvalidNumber, "invalid number %q", literal)
+	} else if s.preciseNumbers {
+		if r, ok := new(big.Rat).SetString(literal); ok {
+			number = r
+		}
 	}
 
-	return Token{Type: TokenNumber, Literal: literal}, err
+	return Token{Type: TokenNumber, Literal: literal, Number: number}, err
 }
 
 // scanText consumes all contiguous quoted text runes.
@@ -252,6 +1103,17 @@ func (s *Scanner) scanText(preserveQuotes bool) (Token, error) {
 			break
 		}
 
+		// stop before buffering the rest of a too long text
+		// (the length excludes the 2 wrapping quotes)
+		if s.maxTextLength > 0 && buf.Len()-1 > s.maxTextLength {
+			return Token{Type: TokenText, Literal: buf.String()}, newLimitError(ErrMaxTextLength, "quoted text exceeds the maximum allowed length of %d bytes", s.maxTextLength, buf.Len()-1)
+		}
+
+		// an escaped backslash doesn't escape the next rune in strict mode
+		if s.textUnescape == UnescapeStrict && ch == '\\' && prevCh == '\\' {
+			ch = 0
+		}
+
 		prevCh = ch
 	}
 
@@ -259,13 +1121,19 @@ func (s *Scanner) scanText(preserveQuotes bool) (Token, error) {
 
 	var err error
 	if !hasMatchingQuotes {
-		err = fmt.Errorf("invalid quoted text %q", literal)
+		err = errorf(ErrUnterminatedText, "invalid quoted text %q", literal)
+
+		// a trailing backslash would escape the inserted quote
+		if isTextStartRune(firstCh) && prevCh != '\\' {
+			err = withFixIt(err, &FixIt{Span: Span{Start: s.pos, End: s.pos}, Text: string(firstCh)})
+		}
 	} else if !preserveQuotes {
-		// unquote
-		literal = literal[1 : len(literal)-1]
-		// remove escaped quotes prefix (aka. \)
-		firstChStr := string(firstCh)
-		literal = strings.Replace(literal, `\`+firstChStr, firstChStr, -1)
+		// the errored text is returned as it is
+		if unescaped, unescapeErr := unescapeText(literal, s.textUnescape); unescapeErr != nil {
+			err = unescapeErr
+		} else {
+			literal = unescaped
+		}
 	}
 
 	return Token{Type: TokenText, Literal: literal}, err
@@ -297,7 +1165,7 @@ func (s *Scanner) scanSign() (Token, error) {
 
 	var err error
 	if !isSignOperator(literal) {
-		err = fmt.Errorf("invalid sign operator %q", literal)
+		err = newOperatorError(TokenSign, literal).withSuggestionFixIt(s.pos - len(literal))
 	}
 
 	return Token{Type: TokenSign, Literal: literal}, err
@@ -329,7 +1197,7 @@ func (s *Scanner) scanJoin() (Token, error) {
 
 	var err error
 	if !isJoinOperator(literal) {
-		err = fmt.Errorf("invalid join operator %q", literal)
+		err = newOperatorError(TokenJoin, literal).withSuggestionFixIt(s.pos - len(literal))
 	}
 
 	return Token{Type: TokenJoin, Literal: literal}, err
@@ -384,7 +1252,11 @@ func (s *Scanner) scanGroup() (Token, error) {
 
 	var err error
 	if !isGroupStartRune(firstChar) || openGroups > 0 {
-		err = fmt.Errorf("invalid formatted group - missing %d closing bracket(s)", openGroups)
+		err = errorf(ErrUnterminatedGroup, "invalid formatted group - missing %d closing bracket(s)", openGroups)
+
+		if isGroupStartRune(firstChar) {
+			err = withFixIt(err, &FixIt{Span: Span{Start: s.pos, End: s.pos}, Text: strings.Repeat(")", openGroups)})
+		}
 	}
 
 	return Token{Type: TokenGroup, Literal: literal}, err
@@ -393,19 +1265,26 @@ func (s *Scanner) scanGroup() (Token, error) {
 // scanComment consumes all contiguous single line comment runes until
 // a new character (\n) or EOF is reached.
 func (s *Scanner) scanComment() (Token, error) {
-	var buf bytes.Buffer
-
 	// Read the first 2 characters without writting them to the buffer.
 	if !isCommentStartRune(s.read()) || !isCommentStartRune(s.read()) {
-		return Token{Type: TokenComment}, errors.New("invalid comment")
+		return Token{Type: TokenComment}, errorf(ErrInvalidComment, "invalid comment")
 	}
 
+	return s.scanCommentText()
+}
+
+// scanCommentText consumes the comment text runes after the comment marker
+// until a new character (\n) or EOF is reached.
+func (s *Scanner) scanCommentText() (Token, error) {
+	var buf bytes.Buffer
+
 	// Read every subsequent comment text rune into the buffer.
 	// \n and EOF will cause the loop to exit.
-	for i := 0; ; i++ {
+	for {
 		ch := s.read()
 
-		if ch == eof || ch == '\n' {
+		// \r for the old Mac line endings (for \r\n the \n is left as whitespace)
+		if ch == eof || ch == '\n' || ch == '\r' {
 			break
 		}
 
@@ -417,26 +1296,96 @@ func (s *Scanner) scanComment() (Token, error) {
 	return Token{Type: TokenComment, Literal: literal}, nil
 }
 
+// scanBOM skips the UTF-8 byte order mark at the start of the input
+// and returns an error for the UTF-16 byte order marks.
+func (s *Scanner) scanBOM() (Token, error) {
+	if b, _ := s.r.Peek(3); bytes.Equal(b, []byte{0xEF, 0xBB, 0xBF}) {
+		s.discard(3)
+		return Token{}, nil
+	}
+
+	if b, _ := s.r.Peek(2); bytes.Equal(b, []byte{0xFE, 0xFF}) || bytes.Equal(b, []byte{0xFF, 0xFE}) {
+		literal := string(b)
+		s.discard(2)
+		return Token{Type: TokenUnexpected, Literal: literal}, errorf(ErrUnsupportedEncoding, "UTF-16 encoded input is not supported (expected UTF-8)")
+	}
+
+	return Token{}, nil
+}
+
+// isWhitespace checks if ch is a whitespace rune according
+// to the scanner options.
+func (s *Scanner) isWhitespace(ch rune) bool {
+	return isWhitespaceRune(ch) || (s.unicodeWhitespace && unicode.IsSpace(ch))
+}
+
+// commentMarker returns the registered comment marker at
+// the current reader position (or empty string if there is none).
+func (s *Scanner) commentMarker() string {
+	for _, m := range s.commentMarkers {
+		if b, _ := s.r.Peek(len(m)); string(b) == m {
+			return m
+		}
+	}
+
+	return ""
+}
+
 // read reads the next rune from the buffered reader.
 // Returns the `rune(0)` if an error or `io.EOF` occurs.
 func (s *Scanner) read() rune {
-	ch, _, err := s.r.ReadRune()
+	ch, size, err := s.r.ReadRune()
 	if err != nil {
+		s.lastWidth = 0
+		return eof
+	}
+
+	if s.maxInputLength > 0 && s.pos+size > s.maxInputLength {
+		s.r.UnreadRune()
+		s.lastWidth = 0
+		s.inputExceeded = true
+		s.exceededLength = s.pos + size
 		return eof
 	}
+
+	s.pos += size
+	s.lastWidth = size
+
 	return ch
 }
 
 // unread places the previously read rune back on the reader.
 func (s *Scanner) unread() error {
-	return s.r.UnreadRune()
+	if err := s.r.UnreadRune(); err != nil {
+		return err
+	}
+
+	// step back with the actual rune width (could be multi-byte)
+	s.pos -= s.lastWidth
+	s.lastWidth = 0
+
+	return nil
+}
+
+// discard skips the next n bytes of the buffered reader.
+func (s *Scanner) discard(n int) {
+	if s.maxInputLength > 0 && s.pos+n > s.maxInputLength {
+		s.inputExceeded = true
+		s.exceededLength = s.pos + n
+		return
+	}
+
+	discarded, _ := s.r.Discard(n)
+
+	s.pos += discarded
+	s.lastWidth = 0
 }
 
 // Lexical helpers:
 // -------------------------------------------------------------------
 
-// isWhitespaceRune checks if a rune is a space, tab, or newline.
-func isWhitespaceRune(ch rune) bool { return ch == ' ' || ch == '\t' || ch == '\n' }
+// isWhitespaceRune checks if a rune is a space, tab, newline or carriage return.
+func isWhitespaceRune(ch rune) bool { return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' }
 
 // isLetterRune checks if a rune is a letter.
 func isLetterRune(ch rune) bool {
@@ -464,10 +1413,17 @@ func isNumberStartRune(ch rune) bool {
 	return ch == '-' || isDigitRune(ch)
 }
 
+// isNumberPartRune checks if a rune is a valid number character
+// after its first rune (aka. a digit or the decimal dot).
+func isNumberPartRune(ch rune) bool {
+	return isDigitRune(ch) || ch == '.'
+}
+
 // isSignStartRune checks if a rune is a valid sign operator start character.
 func isSignStartRune(ch rune) bool {
 	return ch == '=' ||
 		ch == '?' ||
+		ch == '*' ||
 		ch == '!' ||
 		ch == '>' ||
 		ch == '<' ||
@@ -489,32 +1445,77 @@ func isCommentStartRune(ch rune) bool {
 	return ch == '/'
 }
 
-// isSignOperator checks if a literal is a valid sign operator.
+// signOperators lists all supported sign operators.
+var signOperators = []SignOp{
+	SignEq,
+	SignNeq,
+	SignLt,
+	SignLte,
+	SignGt,
+	SignGte,
+	SignLike,
+	SignNlike,
+	SignAnyEq,
+	SignAnyNeq,
+	SignAnyLike,
+	SignAnyNlike,
+	SignAnyLt,
+	SignAnyLte,
+	SignAnyGt,
+	SignAnyGte,
+	SignAllEq,
+	SignAllNeq,
+	SignAllLike,
+	SignAllNlike,
+	SignAllLt,
+	SignAllLte,
+	SignAllGt,
+	SignAllGte,
+}
+
+// signOperatorAliases maps the alternative sign operator
+// literals to their canonical SignOp.
+var signOperatorAliases = map[string]SignOp{
+	"==": SignEq,
+	"<>": SignNeq,
+}
+
+// isSignOperator checks if a literal is a valid sign operator (or sign operator alias).
 func isSignOperator(literal string) bool {
-	switch SignOp(literal) {
-	case
-		SignEq,
-		SignNeq,
-		SignLt,
-		SignLte,
-		SignGt,
-		SignGte,
-		SignLike,
-		SignNlike,
-		SignAnyEq,
-		SignAnyNeq,
-		SignAnyLike,
-		SignAnyNlike,
-		SignAnyLt,
-		SignAnyLte,
-		SignAnyGt,
-		SignAnyGte:
+	if _, ok := signOperatorAliases[literal]; ok {
 		return true
 	}
 
+	for _, op := range signOperators {
+		if SignOp(literal) == op {
+			return true
+		}
+	}
+
 	return false
 }
 
+// normalizeSignOp returns the canonical SignOp of a sign operator
+// literal (eg. "=" for the "==" alias).
+func normalizeSignOp(literal string) SignOp {
+	if op, ok := signOperatorAliases[literal]; ok {
+		return op
+	}
+
+	return SignOp(literal)
+}
+
+// signOperatorLiterals returns the literals of all supported sign operators.
+func signOperatorLiterals() []string {
+	result := make([]string, len(signOperators))
+
+	for i, op := range signOperators {
+		result[i] = string(op)
+	}
+
+	return result
+}
+
 // isJoinOperator checks if a literal is a valid join type operator.
 func isJoinOperator(literal string) bool {
 	op := JoinOp(literal)
@@ -535,7 +1536,80 @@ func isNumber(literal string) bool {
 	return err == nil
 }
 
-var identifierRegex = regexp.MustCompile(`^[\@\#\_]?[\w\.\:]*\w+$`)
+// unescapeText processes the quoted text literal according to
+// the specified unescape mode (see TextUnescapeMode).
+func unescapeText(quoted string, mode TextUnescapeMode) (string, error) {
+	switch mode {
+	case UnescapeNone:
+		return quoted[1 : len(quoted)-1], nil
+	case UnescapeStrict:
+		return unescapeStrictText(quoted[1 : len(quoted)-1])
+	case UnescapeRaw:
+		return quoted, nil
+	default:
+		return unquoteText(quoted), nil
+	}
+}
+
+// strictTextEscapes maps the supported UnescapeStrict escape
+// sequence characters to their unescaped value.
+var strictTextEscapes = map[byte]string{
+	'\\': `\`,
+	'\'': `'`,
+	'"':  `"`,
+	'n':  "\n",
+	'r':  "\r",
+	't':  "\t",
+	'%':  `\%`,
+	'_':  `\_`,
+}
+
+// unescapeStrictText processes the escape sequences of an unquoted text.
+func unescapeStrictText(text string) (string, error) {
+	if !strings.Contains(text, `\`) {
+		return text, nil
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' {
+			sb.WriteByte(text[i])
+			continue
+		}
+
+		if i+1 >= len(text) {
+			return "", errorf(ErrInvalidEscape, "invalid trailing text escape character")
+		}
+
+		i++
+
+		unescaped, ok := strictTextEscapes[text[i]]
+		if !ok {
+			return "", errorf(ErrInvalidEscape, "invalid text escape sequence %q", text[i-1:i+1])
+		}
+
+		sb.WriteString(unescaped)
+	}
+
+	return sb.String(), nil
+}
+
+// unquoteText removes the wrapping quotes of a quoted text literal
+// and the escape prefix (aka. \) of the inner matching quotes.
+func unquoteText(quoted string) string {
+	quote := quoted[:1]
+
+	return strings.Replace(quoted[1:len(quoted)-1], `\`+quote, quote, -1)
+}
+
+// identifierIndexPattern matches a single identifier bracket index (eg. [0] or ["key"]).
+const identifierIndexPattern = `\[(?:\d+|"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')\]`
+
+// identifierArrowPattern matches a single identifier JSON arrow key (eg. ->"key" or ->>0).
+const identifierArrowPattern = `->>?(?:\d+|"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')`
+
+var identifierRegex = regexp.MustCompile(`^[\@\#\_]?(?:[\w\.\:]|\.\*|` + identifierIndexPattern + `|` + identifierArrowPattern + `)*(?:\w|\.\*|` + identifierIndexPattern + `|` + identifierArrowPattern + `)$`)
 
 // isIdentifier checks if a literal is properly formatted identifier.
 func isIdentifier(literal string) bool {
@@ -1,9 +1,13 @@
 package fexpr
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestNewScanner(t *testing.T) {
@@ -16,6 +20,702 @@ func TestNewScanner(t *testing.T) {
 	}
 }
 
+func TestScannerReset(t *testing.T) {
+	s := NewScanner(strings.NewReader("a > 1"))
+
+	// partially consume the first input
+	if _, err := s.Scan(); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Reset(strings.NewReader("test"))
+
+	token, err := s.Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.Type != TokenIdentifier || token.Literal != "test" {
+		t.Fatalf("Expected token {identifier test}, got %v", token)
+	}
+
+	if token, _ := s.Scan(); token.Type != TokenEOF {
+		t.Fatalf("Expected EOF token, got %v", token)
+	}
+}
+
+func TestScannerCommentMarkers(t *testing.T) {
+	scenarios := []struct {
+		text     string
+		expected string
+	}{
+		{`# test`, `[{comment test}]`},
+		{"a = 1 # test\n-- other\n// default", `[{identifier a} {sign =} {number 1} {comment test} {comment other} {comment default}]`},
+		{"a=-1--test", `[{identifier a} {sign =} {number -1} {comment test}]`},
+		{"a=#b", `[{identifier a} {sign =} {comment b}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			scanner := NewScanner(strings.NewReader(s.text), CommentMarkers("#", "--", ""))
+
+			tokens := []Token{}
+			for {
+				token, err := scanner.Scan()
+				if err != nil || token.Type == TokenEOF {
+					break
+				}
+
+				if token.Type != TokenWS {
+					tokens = append(tokens, token)
+				}
+			}
+
+			if v := fmt.Sprintf("%v", tokens); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestScannerRegisterSignOp(t *testing.T) {
+	scenarios := []struct {
+		text          string
+		expected      string
+		expectedError bool
+	}{
+		{`a @> b`, `[{identifier a} {sign @>} {identifier b}]`, false},
+		{`a #= 1 && #macro = 2`, `[{identifier a} {sign #=} {number 1} {join &&} {identifier #macro} {sign =} {number 2}]`, false},
+		{`a=~"x"`, `[{identifier a} {sign =~} {text x}]`, false},
+		{`a <@@ (1, 2) && b <@ c`, `[{identifier a} {sign <@@} {group 1, 2} {join &&} {identifier b} {sign <@} {identifier c}]`, false},
+		{`a @ b`, `[{identifier a}]`, true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			scanner := NewScanner(
+				strings.NewReader(s.text),
+				RegisterSignOp("@>", SignOpOptions{}),
+				RegisterSignOp("#=", SignOpOptions{}),
+				RegisterSignOp("=~", SignOpOptions{}),
+				RegisterSignOp("<@", SignOpOptions{}),
+				RegisterSignOp("<@@", SignOpOptions{List: true}),
+			)
+
+			tokens := []Token{}
+			var scanErr error
+			for {
+				token, err := scanner.Scan()
+				if err != nil {
+					scanErr = err
+					break
+				}
+
+				if token.Type == TokenEOF {
+					break
+				}
+
+				if token.Type != TokenWS {
+					tokens = append(tokens, token)
+				}
+			}
+
+			if s.expectedError != (scanErr != nil) {
+				t.Fatalf("Expected error %v, got %v", s.expectedError, scanErr)
+			}
+
+			if v := fmt.Sprintf("%v", tokens); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestScannerRegisterSignOpPanic(t *testing.T) {
+	literals := []string{"", "in", "=1", "= =", `="`, "(", ")"}
+
+	for _, literal := range literals {
+		t.Run(literal, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Expected panic for %q", literal)
+				}
+			}()
+
+			RegisterSignOp(literal, SignOpOptions{})
+		})
+	}
+}
+
+func TestScannerRegisterJoinOp(t *testing.T) {
+	scanner := NewScanner(
+		strings.NewReader(`a = 1 => b >= 2 ^^ c=>=3`),
+		SkipWhitespace(),
+		RegisterJoinOp("=>", JoinOpOptions{Metadata: "implies"}),
+		RegisterJoinOp("^^", JoinOpOptions{}),
+	)
+
+	tokens, err := scanAll(scanner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{identifier a} {sign =} {number 1} {join =>} {identifier b} {sign >=} {number 2} {join ^^} {identifier c} {join =>} {sign =} {number 3}]`
+	if v := fmt.Sprintf("%v", tokens); v != expected {
+		t.Fatalf("Expected %s, got %s", expected, v)
+	}
+
+	if opts, ok := scanner.LookupJoinOp("=>"); !ok || opts.Metadata != "implies" {
+		t.Fatalf("Expected the => join options, got %v (%v)", opts, ok)
+	}
+
+	for _, op := range []JoinOp{JoinAnd, "=", "^"} {
+		if _, ok := scanner.LookupJoinOp(op); ok {
+			t.Fatalf("Expected %q to not be a custom join operator", op)
+		}
+	}
+}
+
+// scanAll returns all tokens of scanner until EOF or error.
+func scanAll(scanner *Scanner) ([]Token, error) {
+	tokens := []Token{}
+
+	for {
+		token, err := scanner.Scan()
+		if err != nil {
+			return tokens, err
+		}
+
+		if token.Type == TokenEOF {
+			return tokens, nil
+		}
+
+		tokens = append(tokens, token)
+	}
+}
+
+// scanTestColor is a test LiteralScanFunc for `#` prefixed hex color literals.
+func scanTestColor(r io.RuneScanner) (Token, error) {
+	var sb strings.Builder
+
+	for {
+		ch, _, err := r.ReadRune()
+		if err != nil {
+			break
+		}
+
+		if sb.Len() > 0 && !strings.ContainsRune("0123456789abcdefABCDEF", ch) {
+			r.UnreadRune()
+			break
+		}
+
+		sb.WriteRune(ch)
+	}
+
+	if sb.Len() != 7 {
+		return Token{Type: "color", Literal: sb.String()}, fmt.Errorf("invalid color %q", sb.String())
+	}
+
+	return Token{Type: "color", Literal: sb.String()}, nil
+}
+
+func TestScannerRegisterLiteral(t *testing.T) {
+	scenarios := []struct {
+		text          string
+		expected      string
+		expectedError bool
+	}{
+		{`a = #ff00AA`, `[{identifier a} {sign =} {color #ff00AA}]`, false},
+		{`#ff00aa!=b`, `[{color #ff00aa} {sign !=} {identifier b}]`, false},
+		{`a = #ff`, `[{identifier a} {sign =}]`, true},
+		{`a = $`, `[{identifier a} {sign =}]`, true},
+		{`a = b // #000000`, `[{identifier a} {sign =} {identifier b} {comment #000000}]`, false},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			scanner := NewScanner(
+				strings.NewReader(s.text),
+				RegisterLiteral('#', scanTestColor),
+				RegisterLiteral('$', func(r io.RuneScanner) (Token, error) {
+					return Token{Type: "noop"}, nil
+				}),
+			)
+
+			tokens := []Token{}
+			var scanErr error
+			for {
+				token, err := scanner.Scan()
+				if err != nil {
+					scanErr = err
+					break
+				}
+
+				if token.Type == TokenEOF {
+					break
+				}
+
+				if token.Type != TokenWS {
+					tokens = append(tokens, token)
+				}
+			}
+
+			if s.expectedError != (scanErr != nil) {
+				t.Fatalf("Expected error %v, got %v", s.expectedError, scanErr)
+			}
+
+			if v := fmt.Sprintf("%v", tokens); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestScannerTextUnescape(t *testing.T) {
+	scenarios := []struct {
+		mode          TextUnescapeMode
+		text          string
+		expected      string
+		expectedError bool
+	}{
+		{UnescapeQuotes, `"a\"b\%\n'"`, `a"b\%\n'`, false},
+		{UnescapeQuotes, `'a\'b'`, `a'b`, false},
+		{UnescapeNone, `"a\"b\%\n'"`, `a\"b\%\n'`, false},
+		{UnescapeNone, `'a\'b'`, `a\'b`, false},
+		{UnescapeRaw, `"a\"b\%"`, `"a\"b\%"`, false},
+		{UnescapeStrict, `"a\"b\%\_\n\t\r\'\\c"`, "a\"b\\%\\_\n\t\r'\\c", false},
+		{UnescapeStrict, `"a\\"`, `a\`, false},
+		{UnescapeStrict, `"a\\\""`, `a\"`, false},
+		{UnescapeStrict, `"a\d"`, `"a\d"`, true},
+		{UnescapeStrict, `"a\\" = 1`, `a\`, false},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			scanner := NewScanner(strings.NewReader(s.text), TextUnescape(s.mode))
+
+			token, err := scanner.Scan()
+
+			if s.expectedError != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", s.expectedError, err)
+			}
+
+			if token.Type != TokenText || token.Literal != s.expected {
+				t.Fatalf("Expected text token %q, got %v", s.expected, token)
+			}
+		})
+	}
+}
+
+func TestScannerPreciseNumbers(t *testing.T) {
+	scenarios := []struct {
+		text     string
+		opts     []ScannerOption
+		expected string
+	}{
+		{`0.1`, nil, `{number 0.1}`},
+		{`0.1`, []ScannerOption{PreciseNumbers()}, `{number 0.1 1/10}`},
+		{`-12.50`, []ScannerOption{PreciseNumbers()}, `{number -12.50 -25/2}`},
+		{`123`, []ScannerOption{PreciseNumbers()}, `{number 123 123/1}`},
+		{`1.2.3`, []ScannerOption{PreciseNumbers()}, `{number 1.2.3}`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			scanner := NewScanner(strings.NewReader(s.text), s.opts...)
+
+			token, _ := scanner.Scan()
+
+			if tokenPrint := fmt.Sprintf("%v", token); tokenPrint != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, tokenPrint)
+			}
+		})
+	}
+}
+
+func TestScannerOnToken(t *testing.T) {
+	var trace []string
+
+	hook := func(t Token, span Span, err error) error {
+		trace = append(trace, fmt.Sprintf("%v:%d-%d:%v", t, span.Start, span.End, err))
+		return nil
+	}
+
+	scanner := NewScanner(strings.NewReader(`a = 1.`), OnToken(hook), SkipWhitespace())
+
+	// peeked tokens shouldn't be reported until scanned
+	if _, err := scanner.PeekN(2); err != nil {
+		t.Fatal(err)
+	}
+	if len(trace) != 0 {
+		t.Fatalf("Expected no reported tokens, got %v", trace)
+	}
+
+	scanAll(scanner)
+
+	expected := `[{identifier a}:0-1:<nil> {sign =}:2-3:<nil> {number 1.}:4-6:invalid number "1."]`
+	if tracePrint := fmt.Sprintf("%v", trace); tracePrint != expected {
+		t.Fatalf("Expected %s, got %s", expected, tracePrint)
+	}
+}
+
+func TestScannerOnTokenError(t *testing.T) {
+	var calls int
+
+	hookErr := errors.New("test")
+
+	scanner := NewScanner(
+		strings.NewReader(`a = b`),
+		OnToken(func(t Token, span Span, err error) error {
+			if t.Type == TokenSign {
+				return hookErr
+			}
+			return nil
+		}),
+		OnToken(func(t Token, span Span, err error) error {
+			calls++
+			return nil
+		}),
+	)
+
+	for i := 0; i < 3; i++ {
+		token, err := scanner.Scan()
+
+		if token.Type == TokenSign {
+			if err != hookErr {
+				t.Fatalf("Expected the hook error, got %v", err)
+			}
+		} else if err != nil {
+			t.Fatalf("Expected nil error for %v, got %v", token, err)
+		}
+	}
+
+	// the second hook shouldn't be invoked for the errored sign token
+	if calls != 2 {
+		t.Fatalf("Expected 2 calls of the second hook, got %d", calls)
+	}
+}
+
+func TestScannerSkip(t *testing.T) {
+	text := "a = 1 // test\n&& (b > 2)"
+
+	scenarios := []struct {
+		opts     []ScannerOption
+		expected string
+	}{
+		{nil, `[{identifier a} {whitespace  } {sign =} {whitespace  } {number 1} {whitespace  } {comment test} {join &&} {whitespace  } {group b > 2}]`},
+		{[]ScannerOption{SkipWhitespace()}, `[{identifier a} {sign =} {number 1} {comment test} {join &&} {group b > 2}]`},
+		{[]ScannerOption{SkipComments()}, `[{identifier a} {whitespace  } {sign =} {whitespace  } {number 1} {whitespace  } {join &&} {whitespace  } {group b > 2}]`},
+		{[]ScannerOption{SkipWhitespace(), SkipComments()}, `[{identifier a} {sign =} {number 1} {join &&} {group b > 2}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d", i), func(t *testing.T) {
+			scanner := NewScanner(strings.NewReader(text), s.opts...)
+
+			// the peeked tokens should be skipped too
+			if _, err := scanner.PeekN(2); err != nil {
+				t.Fatal(err)
+			}
+
+			tokens := []Token{}
+			for {
+				token, err := scanner.Scan()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if token.Type == TokenEOF {
+					break
+				}
+
+				tokens = append(tokens, token)
+			}
+
+			if v := fmt.Sprintf("%v", tokens); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestScannerUnicodeWhitespace(t *testing.T) {
+	scanner := NewScanner(strings.NewReader("a\u00a0=\u2003 1"), UnicodeWhitespace())
+
+	tokens := []Token{}
+	for {
+		token, err := scanner.Scan()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if token.Type == TokenEOF {
+			break
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	expected := "[{identifier a} {whitespace \u00a0} {sign =} {whitespace \u2003 } {number 1}]"
+	if v := fmt.Sprintf("%v", tokens); v != expected {
+		t.Fatalf("Expected %q, got %q", expected, v)
+	}
+}
+
+func TestScannerBOM(t *testing.T) {
+	scenarios := []struct {
+		text          string
+		expectedError bool
+		expected      string
+	}{
+		{"\xEF\xBB\xBFa = 1", false, `[{identifier a} {whitespace  } {sign =} {whitespace  } {number 1}]`},
+		{"\xEF\xBB\xBF", false, `[]`},
+		{"a\xEF\xBB\xBF", true, `[{identifier a}]`},
+		{"\xFE\xFF\x00a", true, `[]`},
+		{"\xFF\xFEa\x00", true, `[]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d", i), func(t *testing.T) {
+			scanner := NewScanner(strings.NewReader(s.text))
+
+			var hasErr bool
+
+			tokens := []Token{}
+			for {
+				token, err := scanner.Scan()
+				if err != nil {
+					hasErr = true
+					break
+				}
+
+				if token.Type == TokenEOF {
+					break
+				}
+
+				tokens = append(tokens, token)
+			}
+
+			if hasErr != s.expectedError {
+				t.Fatalf("Expected hasErr %v, got %v", s.expectedError, hasErr)
+			}
+
+			if v := fmt.Sprintf("%v", tokens); v != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, v)
+			}
+		})
+	}
+
+	// the BOM should be checked again after reset
+	scanner := NewScanner(strings.NewReader("a"))
+	scanner.Scan()
+	scanner.Reset(strings.NewReader("\xEF\xBB\xBFb"))
+
+	if token, err := scanner.Scan(); err != nil || token.Literal != "b" {
+		t.Fatalf("Expected {identifier b} token, got %v (%v)", token, err)
+	}
+}
+
+func TestScannerPosition(t *testing.T) {
+	text := "a = \"ü€\" && (b > \"ж\") // ю\nc"
+
+	scanner := NewScanner(strings.NewReader(text))
+
+	expected := []int{1, 2, 3, 4, 11, 12, 14, 15, 25, 26, 32, 33}
+
+	for i, pos := range expected {
+		if _, err := scanner.Scan(); err != nil {
+			t.Fatalf("(%d) %v", i, err)
+		}
+
+		if scanner.pos != pos {
+			t.Fatalf("(%d) Expected position %d, got %d", i, pos, scanner.pos)
+		}
+	}
+
+	if token, _ := scanner.Scan(); token.Type != TokenEOF || scanner.pos != len(text) {
+		t.Fatalf("Expected EOF at %d, got %v at %d", len(text), token, scanner.pos)
+	}
+}
+
+func TestScannerSpans(t *testing.T) {
+	text := "\xEF\xBB\xBFab >= 'ü' // c"
+
+	scanner := NewScanner(strings.NewReader(text), SkipWhitespace())
+
+	if pos, remaining := scanner.Pos(), scanner.Remaining(); pos != 0 || remaining != len(text) {
+		t.Fatalf("Expected initial position 0 and %d remaining, got %d and %d", len(text), pos, remaining)
+	}
+
+	// peeking shouldn't affect the position
+	if _, err := scanner.PeekN(2); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		token     string
+		span      Span
+		remaining int
+	}{
+		{"{identifier ab}", Span{3, 5}, 13},
+		{"{sign >=}", Span{6, 8}, 10},
+		{"{text ü}", Span{9, 13}, 5},
+		{"{comment c}", Span{14, 18}, 0},
+		{"{eof }", Span{18, 18}, 0},
+	}
+
+	for i, s := range scenarios {
+		token, err := scanner.Scan()
+		if err != nil {
+			t.Fatalf("(%d) %v", i, err)
+		}
+
+		if v := fmt.Sprintf("%v", token); v != s.token {
+			t.Fatalf("(%d) Expected token %s, got %s", i, s.token, v)
+		}
+
+		if v := scanner.LastSpan(); v != s.span {
+			t.Fatalf("(%d) Expected span %v, got %v", i, s.span, v)
+		}
+
+		if v := scanner.Pos(); v != s.span.End {
+			t.Fatalf("(%d) Expected position %d, got %d", i, s.span.End, v)
+		}
+
+		if v := scanner.Remaining(); v != s.remaining {
+			t.Fatalf("(%d) Expected %d remaining bytes, got %d", i, s.remaining, v)
+		}
+	}
+}
+
+func TestScannerRemainingUnknown(t *testing.T) {
+	scanner := NewScanner(struct{ io.Reader }{strings.NewReader("a")})
+
+	if v := scanner.Remaining(); v != -1 {
+		t.Fatalf("Expected -1, got %d", v)
+	}
+}
+
+func TestScannerRecoverErrors(t *testing.T) {
+	text := "a =!= $x.y && b@c > 'd // e\n f"
+
+	scanner := NewScanner(strings.NewReader(text), RecoverErrors(), SkipWhitespace())
+
+	tokens := []Token{}
+	for {
+		token, err := scanner.Scan()
+		if err != nil {
+			t.Fatalf("Did not expect error, got %v", err)
+		}
+
+		if token.Type == TokenEOF {
+			break
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	expectedTokens := "[{identifier a} {unexpected =!=} {unexpected $x.y} {join &&} {unexpected b@c} {sign >} {unexpected 'd // e\n f}]"
+	if v := fmt.Sprintf("%v", tokens); v != expectedTokens {
+		t.Fatalf("Expected tokens %q, got %q", expectedTokens, v)
+	}
+
+	expectedErrors := []string{
+		`invalid sign operator "=!=" (did you mean "!="?) (at 2:5)`,
+		`unexpected character '$' (at 6:10)`,
+		`Invalid identifier "b@c" (at 14:17)`,
+		`invalid quoted text "'d // e\n f" (at 20:30)`,
+	}
+
+	errs := scanner.Errors()
+	if len(errs) != len(expectedErrors) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(expectedErrors), len(errs), errs)
+	}
+
+	for i, err := range errs {
+		if err.Error() != expectedErrors[i] {
+			t.Errorf("(%d) Expected error %q, got %q", i, expectedErrors[i], err.Error())
+		}
+	}
+
+	scanner.Reset(strings.NewReader("a"))
+	if errs := scanner.Errors(); len(errs) != 0 {
+		t.Fatalf("Expected no errors after reset, got %v", errs)
+	}
+}
+
+func TestScannerMaxTextLength(t *testing.T) {
+	scenarios := []struct {
+		text          string
+		expectedError bool
+		expectedToken string
+	}{
+		{`"abc"`, false, `{text abc}`},
+		{`"abcd"`, false, `{text abcd}`},
+		{`"abcde"`, true, `{text "abcde}`},
+		{`'ab\'cdefghij'`, true, `{text 'ab\'c}`},
+		{`(a = "abcdefgh")`, true, `{group a = "abcde}`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			scanner := NewScanner(strings.NewReader(s.text), MaxTextLength(4))
+
+			token, err := scanner.Scan()
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if v := fmt.Sprintf("%v", token); v != s.expectedToken {
+				t.Fatalf("Expected token %s, got %s", s.expectedToken, v)
+			}
+		})
+	}
+}
+
+func TestScannerMaxInputLength(t *testing.T) {
+	scenarios := []struct {
+		text          string
+		expectedError bool
+	}{
+		{`a = 1`, false},
+		{`a = 12`, false},
+		{`a = 123`, true},
+		{`a=1 //`, false},
+		{`a=1 ///`, true},
+		{`a = "ä"`, true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			scanner := NewScanner(strings.NewReader(s.text), MaxInputLength(6), CommentMarkers("//"))
+
+			var err error
+			for {
+				var token Token
+
+				token, err = scanner.Scan()
+				if err != nil || token.Type == TokenEOF {
+					break
+				}
+			}
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+		})
+	}
+}
+
 func TestScannerScan(t *testing.T) {
 	type output struct {
 		error bool
@@ -28,6 +728,8 @@ func TestScannerScan(t *testing.T) {
 		// whitespace
 		{"   ", []output{{false, "{whitespace    }"}}},
 		{"test 123", []output{{false, "{identifier test}"}, {false, "{whitespace  }"}, {false, "{number 123}"}}},
+		{"a\r\n\t1", []output{{false, "{identifier a}"}, {false, "{whitespace \r\n\t}"}, {false, "{number 1}"}}},
+		{"a\u00a01", []output{{false, "{identifier a}"}, {true, "{unexpected \u00a0}"}, {false, "{number 1}"}}},
 		// identifier
 		{`test`, []output{{false, `{identifier test}`}}},
 		{`@test.123`, []output{{false, `{identifier @test.123}`}}},
@@ -38,6 +740,26 @@ func TestScannerScan(t *testing.T) {
 		{`test#@`, []output{{true, `{identifier test#@}`}}},
 		{`test'`, []output{{false, `{identifier test}`}, {true, `{text '}`}}},
 		{`test"d`, []output{{false, `{identifier test}`}, {true, `{text "d}`}}},
+		{`items[0].name`, []output{{false, `{identifier items[0].name}`}}},
+		{`items[0][12]:length`, []output{{false, `{identifier items[0][12]:length}`}}},
+		{`data["weird key"]`, []output{{false, `{identifier data["weird key"]}`}}},
+		{`data['a\'b]'].c`, []output{{false, `{identifier data['a\'b]'].c}`}}},
+		{`items[]`, []output{{true, `{identifier items[}`}, {true, `{unexpected ]}`}}},
+		{`items[a]`, []output{{true, `{identifier items[}`}, {false, `{identifier a}`}, {true, `{unexpected ]}`}}},
+		{`items[0`, []output{{true, `{identifier items[0}`}}},
+		{`items[0 ]`, []output{{true, `{identifier items[0}`}, {false, `{whitespace  }`}, {true, `{unexpected ]}`}}},
+		{`data["a]`, []output{{true, `{identifier data["a]}`}}},
+		{`tags.*`, []output{{false, `{identifier tags.*}`}}},
+		{`addresses.*.city`, []output{{false, `{identifier addresses.*.city}`}}},
+		{`a.*:length`, []output{{false, `{identifier a.*:length}`}}},
+		{`a.**`, []output{{false, `{identifier a.*}`}, {true, `{sign *}`}}},
+		{`a*`, []output{{false, `{identifier a}`}, {true, `{sign *}`}}},
+		{`a:*`, []output{{true, `{identifier a:}`}, {true, `{sign *}`}}},
+		{`data->"profile"->>'name'`, []output{{false, `{identifier data->"profile"->>'name'}`}}},
+		{`items->0->>1.a`, []output{{false, `{identifier items->0->>1.a}`}}},
+		{`data->>"a">=1`, []output{{false, `{identifier data->>"a"}`}, {false, `{sign >=}`}, {false, `{number 1}`}}},
+		{`data->a`, []output{{true, `{identifier data->}`}, {false, `{identifier a}`}}},
+		{`data->"a`, []output{{true, `{identifier data->"a}`}}},
 		// number
 		{`123`, []output{{false, `{number 123}`}}},
 		{`-123`, []output{{false, `{number -123}`}}},
@@ -96,6 +818,25 @@ func TestScannerScan(t *testing.T) {
 			{false, `{whitespace  }`},
 			{false, `{sign ?<=}`},
 		}},
+		{`*= *!= *~ *!~ *< *<= *> *>=`, []output{
+			{false, `{sign *=}`},
+			{false, `{whitespace  }`},
+			{false, `{sign *!=}`},
+			{false, `{whitespace  }`},
+			{false, `{sign *~}`},
+			{false, `{whitespace  }`},
+			{false, `{sign *!~}`},
+			{false, `{whitespace  }`},
+			{false, `{sign *<}`},
+			{false, `{whitespace  }`},
+			{false, `{sign *<=}`},
+			{false, `{whitespace  }`},
+			{false, `{sign *>}`},
+			{false, `{whitespace  }`},
+			{false, `{sign *>=}`},
+		}},
+		{`*`, []output{{true, `{sign *}`}}},
+		{`== <>`, []output{{false, `{sign ==}`}, {false, `{whitespace  }`}, {false, `{sign <>}`}}},
 		// groups/parenthesis
 		{`a)`, []output{{false, `{identifier a}`}, {true, `{unexpected )}`}}},
 		{`(a b c`, []output{{true, `{group a b c}`}}},
@@ -112,6 +853,8 @@ func TestScannerScan(t *testing.T) {
 		{`// test`, []output{{false, `{comment test}`}}},
 		{`//   test1 //test2  `, []output{{false, `{comment test1 //test2}`}}},
 		{`///test`, []output{{false, `{comment /test}`}}},
+		{"// test\r\na", []output{{false, `{comment test}`}, {false, "{whitespace \n}"}, {false, `{identifier a}`}}},
+		{"// test\ra", []output{{false, `{comment test}`}, {false, `{identifier a}`}}},
 	}
 
 	for i, scenario := range testScenarios {
@@ -143,3 +886,141 @@ func TestScannerScan(t *testing.T) {
 		}
 	}
 }
+
+func TestScannerPeek(t *testing.T) {
+	s := NewScanner(strings.NewReader("a >= 1"))
+
+	if _, err := s.PeekN(0); err == nil {
+		t.Fatal("Expected PeekN(0) to fail")
+	}
+
+	scenarios := []struct {
+		peekN         int
+		expectedPeek  string
+		expectedToken string
+	}{
+		{1, "{identifier a}", "{identifier a}"},
+		{2, "{sign >=}", "{whitespace  }"},
+		{3, "{number 1}", "{sign >=}"},
+		{10, "{eof }", "{whitespace  }"},
+		{1, "{number 1}", "{number 1}"},
+		{1, "{eof }", "{eof }"},
+	}
+
+	for i, scenario := range scenarios {
+		peeked, err := s.PeekN(scenario.peekN)
+		if err != nil {
+			t.Fatalf("(%d) Did not expect error, got %v", i, err)
+		}
+
+		if v := fmt.Sprintf("%v", peeked); v != scenario.expectedPeek {
+			t.Fatalf("(%d) Expected peeked token %s, got %s", i, scenario.expectedPeek, v)
+		}
+
+		token, err := s.Scan()
+		if err != nil {
+			t.Fatalf("(%d) Did not expect error, got %v", i, err)
+		}
+
+		if v := fmt.Sprintf("%v", token); v != scenario.expectedToken {
+			t.Fatalf("(%d) Expected scanned token %s, got %s", i, scenario.expectedToken, v)
+		}
+	}
+}
+
+func TestScannerPeekError(t *testing.T) {
+	s := NewScanner(strings.NewReader("a %"))
+
+	if _, err := s.PeekN(3); err == nil {
+		t.Fatal("Expected peek error, got nil")
+	}
+
+	// the errored token should remain in the stream
+	for i := 0; i < 2; i++ {
+		if _, err := s.Scan(); err != nil {
+			t.Fatalf("Did not expect error, got %v", err)
+		}
+	}
+
+	token, err := s.Scan()
+	if err == nil || token.Type != TokenUnexpected {
+		t.Fatalf("Expected unexpected token error, got %v (%v)", token, err)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	scenarios := []struct {
+		text          string
+		ignore        []TokenType
+		expectedError bool
+		expectedPrint string
+	}{
+		{``, nil, false, `[]`},
+		{`a > 1 // test`, nil, false, `[{identifier a} {whitespace  } {sign >} {whitespace  } {number 1} {whitespace  } {comment test}]`},
+		{`a > 1 // test`, []TokenType{TokenWS, TokenComment}, false, `[{identifier a} {sign >} {number 1}]`},
+		{`a > 1 && (b = 2)`, []TokenType{TokenWS}, false, `[{identifier a} {sign >} {number 1} {join &&} {group b = 2}]`},
+		{`a > %`, []TokenType{TokenWS}, true, `[{identifier a} {sign >} {unexpected %}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			tokens, err := Tokenize(s.text, s.ignore...)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if v := fmt.Sprintf("%v", tokens); v != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, v)
+			}
+		})
+	}
+}
+
+func TestScanTokens(t *testing.T) {
+	scenarios := []struct {
+		text          string
+		bufSize       int
+		expectedError bool
+		expectedPrint string
+	}{
+		{``, 1, false, `[]`},
+		{`a>1`, 1, false, `[a > 1]`},
+		{"a  >= 'te\\'st' // test\n&& (b = 2)", 1, false, `[a    >=   'te\'st'   // test
+ &&   (b = 2)]`},
+		{`a>1 && b < 2`, 3, false, `[a > 1   &&   b   <   2]`},
+		{`"test`, 2, true, `[]`},
+		{`a > %`, 2, true, `[a   >  ]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.text), func(t *testing.T) {
+			bs := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(s.text)))
+			bs.Buffer(make([]byte, s.bufSize), 100)
+			bs.Split(ScanTokens)
+
+			tokens := []string{}
+			for bs.Scan() {
+				tokens = append(tokens, bs.Text())
+			}
+
+			err := bs.Err()
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if v := fmt.Sprintf("%v", tokens); v != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, v)
+			}
+		})
+	}
+}
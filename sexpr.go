@@ -0,0 +1,335 @@
+package fexpr
+
+import "strings"
+
+// ToSExpr converts the provided parsed filter into its s-expression
+// representation, for example `status = "active" && (age > 18 || vip = true)`
+// becomes `(and (= status "active") (or (> age 18) (= vip true)))`.
+//
+// The multi-word keyword operators are written with `-` instead of space
+// (eg. `not-in`), the `in` lists as `(list 1 2)`, the custom unary operators
+// as `(unary ~~ (= a 1))` and the identifiers that
+// contain whitespace, parenthesis, quotes or `|` are wrapped in `|`.
+//
+// An empty filter is converted to `(and)`.
+func ToSExpr(exprs []ExprGroup) string {
+	var sb strings.Builder
+
+	writeSExprGroups(&sb, exprs)
+
+	return sb.String()
+}
+
+// writeSExprGroups writes the s-expression of groups into sb.
+func writeSExprGroups(sb *strings.Builder, groups []ExprGroup) {
+	disjuncts := splitOr(groups)
+
+	if len(disjuncts) > 1 {
+		sb.WriteString("(or")
+		for _, d := range disjuncts {
+			sb.WriteString(" ")
+			writeSExprConjunction(sb, d)
+		}
+		sb.WriteString(")")
+		return
+	}
+
+	if len(disjuncts) == 0 {
+		sb.WriteString("(and)")
+		return
+	}
+
+	writeSExprConjunction(sb, disjuncts[0])
+}
+
+// writeSExprConjunction writes the s-expression of `&&` joined groups into sb.
+func writeSExprConjunction(sb *strings.Builder, conjunction []ExprGroup) {
+	if len(conjunction) == 1 {
+		writeSExprItem(sb, conjunction[0].Item)
+		return
+	}
+
+	sb.WriteString("(and")
+	for _, g := range conjunction {
+		sb.WriteString(" ")
+		writeSExprItem(sb, g.Item)
+	}
+	sb.WriteString(")")
+}
+
+// writeSExprItem writes the s-expression of a single ExprGroup.Item into sb.
+func writeSExprItem(sb *strings.Builder, item interface{}) {
+	switch v := item.(type) {
+	case Expr:
+		sb.WriteString("(")
+		sb.WriteString(strings.ReplaceAll(string(v.Op), " ", "-"))
+		sb.WriteString(" ")
+		writeSExprToken(sb, v.Left)
+		sb.WriteString(" ")
+		writeSExprToken(sb, v.Right)
+		sb.WriteString(")")
+	case []ExprGroup:
+		writeSExprGroups(sb, v)
+	case UnaryExpr:
+		sb.WriteString("(unary ")
+		writeSExprToken(sb, Token{Type: TokenIdentifier, Literal: string(v.Op)})
+		sb.WriteString(" ")
+		writeSExprItem(sb, v.Item)
+		sb.WriteString(")")
+	}
+}
+
+// writeSExprToken writes the s-expression of a single operand token into sb.
+func writeSExprToken(sb *strings.Builder, t Token) {
+	switch t.Type {
+	case TokenText:
+		sb.WriteString(`"`)
+		sb.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(t.Literal))
+		sb.WriteString(`"`)
+	case TokenList:
+		sb.WriteString("(list")
+		if items, err := SplitList(t.Literal); err == nil {
+			for _, item := range items {
+				sb.WriteString(" ")
+				writeSExprToken(sb, item)
+			}
+		}
+		sb.WriteString(")")
+	case TokenIdentifier:
+		if strings.ContainsAny(t.Literal, " \t\n\r()\"|;") {
+			sb.WriteString("|")
+			sb.WriteString(strings.NewReplacer(`\`, `\\`, `|`, `\|`).Replace(t.Literal))
+			sb.WriteString("|")
+		} else {
+			sb.WriteString(t.Literal)
+		}
+	default:
+		sb.WriteString(t.Literal)
+	}
+}
+
+// sexprNode represents a single parsed s-expression atom or list.
+type sexprNode struct {
+	token  Token // the atom token (TokenIdentifier, TokenNumber or TokenText)
+	list   []sexprNode
+	isList bool
+}
+
+// ParseSExpr parses the s-expression representation of a filter
+// (see ToSExpr) back into its parsed form.
+func ParseSExpr(text string) ([]ExprGroup, error) {
+	node, rest, err := parseSExprNode(text, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(rest) != "" {
+		return nil, errorf(ErrInvalidSExpr, "unexpected s-expression trailing text %q", strings.TrimSpace(rest))
+	}
+
+	return sexprGroups(node)
+}
+
+// parseSExprNode parses the first s-expression node of text
+// and returns it with the remaining unparsed text.
+func parseSExprNode(text string, depth int) (sexprNode, string, error) {
+	if depth >= defaultMaxDepth {
+		return sexprNode{}, "", newLimitError(ErrMaxDepth, "the maximum allowed nesting depth of %d is exceeded", defaultMaxDepth, depth)
+	}
+
+	text = strings.TrimLeft(text, " \t\n\r")
+
+	if text == "" {
+		return sexprNode{}, "", errorf(ErrInvalidSExpr, "unexpected end of s-expression")
+	}
+
+	switch text[0] {
+	case '(':
+		node := sexprNode{isList: true, list: []sexprNode{}}
+		text = text[1:]
+
+		for {
+			text = strings.TrimLeft(text, " \t\n\r")
+
+			if text == "" {
+				return sexprNode{}, "", errorf(ErrInvalidSExpr, "missing s-expression closing bracket")
+			}
+
+			if text[0] == ')' {
+				return node, text[1:], nil
+			}
+
+			child, rest, err := parseSExprNode(text, depth+1)
+			if err != nil {
+				return sexprNode{}, "", err
+			}
+
+			node.list = append(node.list, child)
+			text = rest
+		}
+	case ')':
+		return sexprNode{}, "", errorf(ErrInvalidSExpr, "unexpected s-expression closing bracket")
+	case '"', '|':
+		quote := text[0]
+
+		var sb strings.Builder
+		for i := 1; i < len(text); i++ {
+			switch ch := text[i]; {
+			case ch == '\\' && i+1 < len(text):
+				i++
+				sb.WriteByte(text[i])
+			case ch == quote:
+				t := Token{Type: TokenText, Literal: sb.String()}
+				if quote == '|' {
+					t.Type = TokenIdentifier
+				}
+				return sexprNode{token: t}, text[i+1:], nil
+			default:
+				sb.WriteByte(ch)
+			}
+		}
+
+		return sexprNode{}, "", errorf(ErrInvalidSExpr, "missing s-expression closing %c quote", quote)
+	default:
+		end := strings.IndexAny(text, " \t\n\r()\"|")
+		if end < 0 {
+			end = len(text)
+		}
+
+		atom := text[:end]
+		t := Token{Type: TokenIdentifier, Literal: atom}
+		if isNumber(atom) {
+			t.Type = TokenNumber
+		}
+
+		return sexprNode{token: t}, text[end:], nil
+	}
+}
+
+// sexprGroups converts a parsed s-expression node into filter groups.
+func sexprGroups(node sexprNode) ([]ExprGroup, error) {
+	if !node.isList || len(node.list) == 0 {
+		return nil, errorf(ErrInvalidSExpr, "expected s-expression list")
+	}
+
+	head := node.list[0]
+	if head.isList || head.token.Type != TokenIdentifier {
+		return nil, errorf(ErrInvalidSExpr, "expected s-expression operator symbol")
+	}
+
+	switch head.token.Literal {
+	case "and", "or":
+		join := JoinAnd
+		if head.token.Literal == "or" {
+			join = JoinOr
+		}
+
+		result := []ExprGroup{}
+
+		for _, arg := range node.list[1:] {
+			nested, err := sexprGroups(arg)
+			if err != nil {
+				return nil, err
+			}
+
+			g := ExprGroup{Join: join, Item: nested}
+			if len(nested) == 1 {
+				g.Item = nested[0].Item // single expression
+			}
+
+			if len(result) == 0 {
+				g.Join = JoinAnd
+			}
+
+			result = append(result, g)
+		}
+
+		return result, nil
+	case "unary":
+		if len(node.list) != 3 || node.list[1].isList || node.list[1].token.Type != TokenIdentifier {
+			return nil, errorf(ErrInvalidSExpr, "expected s-expression unary operator and operand")
+		}
+
+		operand, err := sexprGroups(node.list[2])
+		if err != nil {
+			return nil, err
+		}
+
+		var item interface{} = operand
+		if len(operand) == 1 {
+			item = operand[0].Item // single expression
+		}
+
+		return []ExprGroup{{Join: JoinAnd, Item: UnaryExpr{Op: UnaryOp(node.list[1].token.Literal), Item: item}}}, nil
+	}
+
+	expr, err := sexprExpr(node)
+	if err != nil {
+		return nil, err
+	}
+
+	return []ExprGroup{{Join: JoinAnd, Item: expr}}, nil
+}
+
+// sexprExpr converts an s-expression operator list into Expr.
+func sexprExpr(node sexprNode) (Expr, error) {
+	op := SignOp(strings.ReplaceAll(node.list[0].token.Literal, "-", " "))
+
+	valid := isSignOperator(string(op))
+	for _, kw := range keywordSignOps {
+		if op == kw || op == "not "+kw {
+			valid = true
+		}
+	}
+
+	if !valid {
+		return Expr{}, errorf(ErrInvalidSExpr, "unknown s-expression operator %q", node.list[0].token.Literal)
+	}
+
+	if len(node.list) != 3 {
+		return Expr{}, errorf(ErrInvalidSExpr, "expected 2 operands for s-expression operator %q, got %d", op, len(node.list)-1)
+	}
+
+	left, err := sexprOperand(node.list[1])
+	if err != nil {
+		return Expr{}, err
+	}
+
+	right, err := sexprOperand(node.list[2])
+	if err != nil {
+		return Expr{}, err
+	}
+
+	return Expr{Left: left, Op: normalizeSignOp(string(op)), Right: right}, nil
+}
+
+// sexprOperand converts an s-expression atom or `(list ...)` into an operand token.
+func sexprOperand(node sexprNode) (Token, error) {
+	if !node.isList {
+		if node.token.Type == TokenIdentifier && !isIdentifier(node.token.Literal) {
+			return Token{}, errorf(ErrInvalidSExpr, "invalid s-expression identifier %q", node.token.Literal)
+		}
+
+		return node.token, nil
+	}
+
+	if len(node.list) < 2 || node.list[0].isList || node.list[0].token.Literal != "list" {
+		return Token{}, errorf(ErrInvalidSExpr, "expected s-expression operand or (list ...)")
+	}
+
+	var sb strings.Builder
+
+	for i, item := range node.list[1:] {
+		if item.isList {
+			return Token{}, errorf(ErrInvalidSExpr, "nested s-expression lists are not supported")
+		}
+
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		writeToken(&sb, item.token)
+	}
+
+	return Token{Type: TokenList, Literal: sb.String()}, nil
+}
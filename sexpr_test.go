@@ -0,0 +1,102 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToSExpr(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1`, `(= a 1)`},
+		{`status = "active" && age > 18`, `(and (= status "active") (> age 18))`},
+		{`status = "active" && (age > 18 || vip = true)`, `(and (= status "active") (or (> age 18) (= vip true)))`},
+		{`a = 1 && b = 2 || c = 3`, `(or (and (= a 1) (= b 2)) (= c 3))`},
+		{`a not in (1, "x") && b like 'a"b\c'`, `(and (not-in a (list 1 "x")) (like b "a\"b\\c"))`},
+		{`data["weird key"] ?!= @request.id`, `(?!= |data["weird key"]| @request.id)`},
+		{`((a = 1))`, `(= a 1)`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := ToSExpr(exprs)
+			if result != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, result)
+			}
+
+			// round-trip
+			parsed, err := ParseSExpr(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !Equivalent(parsed, exprs) {
+				t.Fatalf("Expected %s to be equivalent to %s", Stringify(parsed), Stringify(exprs))
+			}
+		})
+	}
+}
+
+func TestToSExprEmpty(t *testing.T) {
+	if v := ToSExpr(nil); v != "(and)" {
+		t.Fatalf("Expected (and), got %s", v)
+	}
+}
+
+func TestParseSExpr(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expectedPrint string
+	}{
+		{``, true, `[]`},
+		{`a`, true, `[]`},
+		{`(`, true, `[]`},
+		{`)`, true, `[]`},
+		{`()`, true, `[]`},
+		{`(= a 1))`, true, `[]`},
+		{`(= a 1) (= b 2)`, true, `[]`},
+		{`(= a)`, true, `[]`},
+		{`(= a 1 2)`, true, `[]`},
+		{`(unknown a 1)`, true, `[]`},
+		{`((=) a 1)`, true, `[]`},
+		{`("=" a 1)`, true, `[]`},
+		{`(= a "b)`, true, `[]`},
+		{`(= |a b| 1)`, true, `[]`},
+		{`(= a (b 1))`, true, `[]`},
+		{`(in a (list))`, true, `[]`},
+		{`(in a (list (list 1)))`, true, `[]`},
+		{`(and (= a 1) b)`, true, `[]`},
+		{`(and)`, false, `[]`},
+		{` ( == a 1 ) `, false, `[{&& {{identifier a} = {number 1}}}]`},
+		{`(not-like a "x\\y")`, false, `[{&& {{identifier a} not like {text x\y}}}]`},
+		{`(in a (list 1 "b" c))`, false, `[{&& {{identifier a} in {list 1, "b", c}}}]`},
+		{`(or (and (= a 1) (= b 2)) (= c 3))`, false, `[{&& [{&& {{identifier a} = {number 1}}} {&& {{identifier b} = {number 2}}}]} {|| {{identifier c} = {number 3}}}]`},
+		{`(and (= a 1) (or (= b 2) (= c 3)))`, false, `[{&& {{identifier a} = {number 1}}} {&& [{&& {{identifier b} = {number 2}}} {|| {{identifier c} = {number 3}}}]}]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := ParseSExpr(s.input)
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
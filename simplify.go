@@ -0,0 +1,125 @@
+package fexpr
+
+import (
+	"sort"
+	"strings"
+)
+
+// Simplify returns a simplified copy of the provided parsed filter
+// that preserves the original conditions order.
+//
+// The simplification:
+//   - removes the duplicated `&&` and `||` operands (eg. `a = 1 || a = 1` becomes `a = 1`)
+//   - flattens the single expression groups (eg. `(a = 1)` becomes `a = 1`)
+//   - inlines the redundant nested groups (eg. `a = 1 && (b = 2 && c = 3)`)
+//   - drops the empty groups
+//
+// Note that `&&` has higher precedence than `||`.
+func Simplify(exprs []ExprGroup) []ExprGroup {
+	result := []ExprGroup{}
+	seenDisjuncts := map[string]struct{}{}
+
+	for _, conjunction := range splitOr(flattenGroups(exprs)) {
+		items := make([]ExprGroup, 0, len(conjunction))
+		keys := make([]string, 0, len(conjunction))
+		seen := map[string]struct{}{}
+
+		for _, g := range conjunction {
+			key := itemString(g.Item)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			keys = append(keys, key)
+			items = append(items, g)
+		}
+
+		// the && operands order doesn't matter when comparing the disjuncts
+		sort.Strings(keys)
+		disjunctKey := strings.Join(keys, " && ")
+		if _, ok := seenDisjuncts[disjunctKey]; ok {
+			continue
+		}
+		seenDisjuncts[disjunctKey] = struct{}{}
+
+		for i, g := range items {
+			g.Join = JoinAnd
+			if i == 0 && len(result) > 0 {
+				g.Join = JoinOr
+			}
+
+			result = append(result, g)
+		}
+	}
+
+	return result
+}
+
+// flattenGroups simplifies the nested groups and inlines
+// them in the parent when that doesn't change the filter meaning.
+func flattenGroups(groups []ExprGroup) []ExprGroup {
+	result := make([]ExprGroup, 0, len(groups))
+
+	// join of a dropped group that should be transferred to the next one
+	var pendingJoin JoinOp
+
+	for i, g := range groups {
+		if pendingJoin != "" {
+			g.Join = pendingJoin
+			pendingJoin = ""
+		}
+
+		if unary, ok := g.Item.(UnaryExpr); ok {
+			if unary, ok = mapUnaryOperand(unary, Simplify); ok {
+				result = append(result, ExprGroup{Join: g.Join, Item: unary})
+			} else if g.Join == JoinOr {
+				pendingJoin = JoinOr
+			}
+			continue
+		}
+
+		nested, ok := g.Item.([]ExprGroup)
+		if !ok {
+			result = append(result, g)
+			continue
+		}
+
+		nested = Simplify(nested)
+
+		if len(nested) == 0 {
+			if g.Join == JoinOr {
+				pendingJoin = JoinOr
+			}
+			continue
+		}
+
+		// the group is a standalone || operand, aka. a || (b && c || d) || e
+		standalone := (i == 0 || g.Join == JoinOr) &&
+			(i == len(groups)-1 || groups[i+1].Join == JoinOr)
+
+		if standalone || isConjunction(nested) {
+			for j, n := range nested {
+				if j == 0 {
+					n.Join = g.Join
+				}
+				result = append(result, n)
+			}
+		} else {
+			result = append(result, ExprGroup{Join: g.Join, Item: nested})
+		}
+	}
+
+	return result
+}
+
+// isConjunction checks if all groups are joined with `&&`.
+func isConjunction(groups []ExprGroup) bool {
+	for i, g := range groups {
+		if i > 0 && g.Join == JoinOr {
+			return false
+		}
+	}
+
+	return true
+}
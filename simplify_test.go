@@ -0,0 +1,67 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSimplify(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1`, `a = 1`},
+		{`(a = 1)`, `a = 1`},
+		{`((a = 1))`, `a = 1`},
+		{`a = 1 || a = 1`, `a = 1`},
+		{`a = 1 && a = 1`, `a = 1`},
+		{`b = 2 && a = 1 || a = 1 && b = 2`, `b = 2 && a = 1`},
+		{`b = 2 || a = 1 || b = 2`, `b = 2 || a = 1`},
+		{`a = 1 && (b = 2 && c = 3)`, `a = 1 && b = 2 && c = 3`},
+		{`a = 1 || (b = 2 && c = 3) && d = 4`, `a = 1 || b = 2 && c = 3 && d = 4`},
+		{`a = 1 || (b = 2 || c = 3) || d = 4`, `a = 1 || b = 2 || c = 3 || d = 4`},
+		{`a = 1 || (b = 2 && c = 3 || d = 4)`, `a = 1 || b = 2 && c = 3 || d = 4`},
+		{`(b = 2 || c = 3)`, `b = 2 || c = 3`},
+		{`a = 1 && (b = 2 || c = 3)`, `a = 1 && (b = 2 || c = 3)`},
+		{`(b = 2 || c = 3) && a = 1`, `(b = 2 || c = 3) && a = 1`},
+		{`a = 1 || (b = 2 || c = 3) && d = 4`, `a = 1 || (b = 2 || c = 3) && d = 4`},
+		{`a = 1 && ((b = 2 || (c = 3)) || b = 2)`, `a = 1 && (b = 2 || c = 3)`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := Stringify(Simplify(exprs))
+			if result != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestSimplifyEmptyGroups(t *testing.T) {
+	a := Expr{Left: Token{Type: TokenIdentifier, Literal: "a"}, Op: SignEq, Right: Token{Type: TokenNumber, Literal: "1"}}
+	b := Expr{Left: Token{Type: TokenIdentifier, Literal: "b"}, Op: SignEq, Right: Token{Type: TokenNumber, Literal: "2"}}
+
+	scenarios := []struct {
+		exprs    []ExprGroup
+		expected string
+	}{
+		{[]ExprGroup{{JoinAnd, []ExprGroup{}}}, ``},
+		{[]ExprGroup{{JoinAnd, []ExprGroup{}}, {JoinAnd, a}}, `a = 1`},
+		{[]ExprGroup{{JoinAnd, a}, {JoinOr, []ExprGroup{{JoinAnd, []ExprGroup{}}}}, {JoinAnd, b}}, `a = 1 || b = 2`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d", i), func(t *testing.T) {
+			result := Stringify(Simplify(s.exprs))
+			if result != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package fexpr
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Snippet renders the input line with the span start and
+// a `^~~~` underline of the span, for example:
+//
+//	a = 1 && deleted = true
+//	         ^~~~~~~~~~~~~~
+//
+// Spans over multiple lines are underlined only to the end of their first line.
+// Tabs are preserved in the underline indentation to keep the alignment.
+func Snippet(input string, span Span) string {
+	start := clampOffset(span.Start, input)
+	end := clampOffset(span.End, input)
+
+	lineStart := strings.LastIndexByte(input[:start], '\n') + 1
+
+	lineEnd := len(input)
+	if i := strings.IndexByte(input[start:], '\n'); i >= 0 {
+		lineEnd = start + i
+	}
+
+	if end > lineEnd {
+		end = lineEnd
+	}
+
+	line := strings.TrimSuffix(input[lineStart:lineEnd], "\r")
+
+	var sb strings.Builder
+
+	sb.WriteString(line)
+	sb.WriteString("\n")
+
+	for _, ch := range input[lineStart:start] {
+		if ch == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+
+	sb.WriteString("^")
+
+	if n := utf8.RuneCountInString(input[start:maxInt(start, end)]); n > 1 {
+		sb.WriteString(strings.Repeat("~", n-1))
+	}
+
+	return sb.String()
+}
+
+// ErrorSnippet renders the input Snippet of a positioned
+// error (aka. *ParseError, *ScanError or *ExprError).
+//
+// Returns false if err doesn't have input position.
+func ErrorSnippet(input string, err error) (string, bool) {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return Snippet(input, parseErr.Span), true
+	}
+
+	var scanErr *ScanError
+	if errors.As(err, &scanErr) {
+		return Snippet(input, scanErr.Span), true
+	}
+
+	var exprErr *ExprError
+	if errors.As(err, &exprErr) {
+		return Snippet(input, exprErr.Span), true
+	}
+
+	return "", false
+}
+
+// clampOffset returns the offset limited to the input bounds
+// (and moved back to the start of its UTF-8 sequence).
+func clampOffset(offset int, input string) int {
+	if offset < 0 {
+		return 0
+	}
+
+	if offset > len(input) {
+		return len(input)
+	}
+
+	for offset > 0 && offset < len(input) && !utf8.RuneStart(input[offset]) {
+		offset--
+	}
+
+	return offset
+}
+
+// maxInt returns the larger of a and b.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,123 @@
+package fexpr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSnippet(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		span     Span
+		expected string
+	}{
+		{``, Span{0, 0}, "\n^"},
+		{`a = 1`, Span{0, 1}, "a = 1\n^"},
+		{`a = 1`, Span{4, 5}, "a = 1\n    ^"},
+		{`a = 1`, Span{5, 5}, "a = 1\n     ^"},
+		{`a = 1`, Span{2, 0}, "a = 1\n  ^"},
+		{`a = 1`, Span{-1, 100}, "a = 1\n^~~~~"},
+		{`a = 1 && deleted = true`, Span{9, 23}, "a = 1 && deleted = true\n         ^~~~~~~~~~~~~~"},
+		{"a = 1 &&\n\tb = 2 ||\r\nc = 3", Span{10, 15}, "\tb = 2 ||\n\t^~~~~"},
+		{"a = 1 &&\nb = 2 ||\nc = 3", Span{4, 14}, "a = 1 &&\n    ^~~~"},
+		{`a = "ąб" && b = 1`, Span{4, 10}, "a = \"ąб\" && b = 1\n    ^~~~"},
+		{`"ąб" = b`, Span{2, 6}, "\"ąб\" = b\n ^~~"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			result := Snippet(s.input, s.span)
+
+			if result != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestErrorSnippet(t *testing.T) {
+	input := "a = 1 &&\n  (b = 2 || deleted = true)"
+
+	_, err := Parse(input, OnExpr(func(g ExprGroup, span Span) error {
+		if expr, ok := g.Item.(Expr); ok && expr.Left.Literal == "deleted" {
+			return errors.New("unknown field")
+		}
+		return nil
+	}))
+
+	snippet, ok := ErrorSnippet(input, err)
+	if !ok {
+		t.Fatalf("Expected positioned error, got %v", err)
+	}
+
+	expected := "  (b = 2 || deleted = true)\n            ^~~~~~~~~~~~~~"
+	if snippet != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, snippet)
+	}
+
+	if _, ok := ErrorSnippet(input, errors.New("test")); ok {
+		t.Fatal("Expected not positioned error")
+	}
+
+	if _, ok := ErrorSnippet(input, nil); ok {
+		t.Fatal("Expected not positioned nil error")
+	}
+}
+
+func TestErrorSnippetParse(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a = 1.`, "a = 1.\n    ^~"},
+		{`b => 2`, "b => 2\n  ^~"},
+		{`a = 'abc`, "a = 'abc\n    ^~~~"},
+		{`(a = 1`, "(a = 1\n^~~~~~"},
+		{`a = 1 && b ^ 2`, "a = 1 && b ^ 2\n           ^"},
+		{`x 1`, "x 1\n  ^"},
+		{`a = 1 &&`, "a = 1 &&\n        ^"},
+		{`a = 1 && (b = 2 && x 1)`, "a = 1 && (b = 2 && x 1)\n                     ^"},
+		{`a = 1 && (b = 2 || c = )`, "a = 1 && (b = 2 || c = )\n                       ^"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			_, err := Parse(s.input)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected *ParseError, got %T", err)
+			}
+
+			snippet, ok := ErrorSnippet(s.input, err)
+			if !ok {
+				t.Fatalf("Expected positioned error, got %v", err)
+			}
+
+			if snippet != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, snippet)
+			}
+		})
+	}
+}
+
+func TestErrorSnippetParseMacro(t *testing.T) {
+	input := `a = 1 && #m`
+
+	_, err := Parse(input, Macro("m", "b ="))
+
+	snippet, ok := ErrorSnippet(input, err)
+	if !ok {
+		t.Fatalf("Expected positioned error, got %v", err)
+	}
+
+	// the macro errors are positioned at the macro identifier
+	expected := "a = 1 && #m\n         ^~"
+	if snippet != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, snippet)
+	}
+}
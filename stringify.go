@@ -0,0 +1,111 @@
+package fexpr
+
+import (
+	"strings"
+)
+
+// Stringify converts the provided parsed filter back into its text
+// representation with normalized whitespaces (aka. single space
+// around the sign and join operators).
+//
+// Comments are not part of the parsed filter and are not restored.
+func Stringify(exprs []ExprGroup) string {
+	var sb strings.Builder
+
+	writeExprGroups(&sb, exprs, " ")
+
+	return sb.String()
+}
+
+// Minify strips the comments, superfluous whitespaces and parenthesis
+// from the provided filter expression while preserving its meaning.
+//
+// An error is returned if input is not a valid filter expression.
+func Minify(input string) (string, error) {
+	exprs, err := Parse(input)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
+	writeExprGroups(&sb, Simplify(exprs), "")
+
+	return sb.String(), nil
+}
+
+// writeExprGroups writes the text representation of groups into sb
+// using space as separator around the operators.
+func writeExprGroups(sb *strings.Builder, groups []ExprGroup, space string) {
+	for i, g := range groups {
+		if i > 0 {
+			sb.WriteString(space)
+			sb.WriteString(string(g.Join))
+			sb.WriteString(space)
+		}
+
+		writeItem(sb, g.Item, space)
+	}
+}
+
+// writeItem writes the text representation of a single ExprGroup.Item into sb.
+func writeItem(sb *strings.Builder, item interface{}, space string) {
+	switch v := item.(type) {
+	case Expr:
+		opSpace := space
+		if isKeywordSignOp(v.Op) {
+			opSpace = " " // keyword operators always need a separator
+		}
+
+		writeToken(sb, v.Left)
+		sb.WriteString(opSpace)
+		sb.WriteString(string(v.Op))
+		sb.WriteString(opSpace)
+		writeToken(sb, v.Right)
+	case []ExprGroup:
+		sb.WriteString("(")
+		writeExprGroups(sb, v, space)
+		sb.WriteString(")")
+	case UnaryExpr:
+		sb.WriteString(string(v.Op))
+		sb.WriteString(" ") // the custom operators could be ambiguous without a separator
+		writeItem(sb, v.Item, space)
+	}
+}
+
+// writeToken writes the text representation of a single operand token into sb.
+func writeToken(sb *strings.Builder, t Token) {
+	switch t.Type {
+	case TokenText:
+		sb.WriteString(quoteText(t.Literal))
+	case TokenList:
+		sb.WriteString("(")
+		sb.WriteString(t.Literal)
+		sb.WriteString(")")
+	default:
+		sb.WriteString(t.Literal)
+	}
+}
+
+// quoteText wraps the provided text in quotes, preferring the ones
+// that don't need escaping.
+func quoteText(text string) string {
+	if !strings.Contains(text, `"`) {
+		return `"` + text + `"`
+	}
+
+	if !strings.Contains(text, `'`) {
+		return `'` + text + `'`
+	}
+
+	return `"` + strings.Replace(text, `"`, `\"`, -1) + `"`
+}
+
+// itemString returns the text representation of a single ExprGroup.Item.
+func itemString(item interface{}) string {
+	var sb strings.Builder
+
+	writeItem(&sb, item, " ")
+
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStringify(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		expected string
+	}{
+		{`a=1`, `a = 1`},
+		{"  a   >=   -1.5  // test\n", `a >= -1.5`},
+		{`@a.b:c ?!~ "test"`, `@a.b:c ?!~ "test"`},
+		{`a = 'te"st'`, `a = 'te"st'`},
+		{`a = "te'st"`, `a = "te'st"`},
+		{`a = "te'\"st"`, `a = "te'\"st"`},
+		{`a in(1,'b')&&c  NOT in  (d)`, `a in (1, "b") && c not in (d)`},
+		{`a = 1 && "b" != c || (d < 2 && (e > 3))`, `a = 1 && "b" != c || (d < 2 && (e > 3))`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			exprs, err := Parse(s.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := Stringify(exprs)
+			if result != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, result)
+			}
+
+			// the stringified filter should be parsed into the same AST
+			reparsed, err := Parse(result)
+			if err != nil {
+				t.Fatalf("Failed to parse the stringified filter: %v", err)
+			}
+
+			if a, b := fmt.Sprintf("%v", exprs), fmt.Sprintf("%v", reparsed); a != b {
+				t.Fatalf("Expected the reparsed filter to be %s, got %s", a, b)
+			}
+		})
+	}
+}
+
+func TestMinify(t *testing.T) {
+	scenarios := []struct {
+		input         string
+		expectedError bool
+		expected      string
+	}{
+		{``, true, ``},
+		{`a >`, true, ``},
+		{`a = 1`, false, `a=1`},
+		{`a not in ( 1,2 ) || b = 3`, false, `a not in (1, 2)||b=3`},
+		{`a  ILIKE  "%b%"`, false, `a ilike "%b%"`},
+		{"  a   >=   -1.5  // test\n", false, `a>=-1.5`},
+		{`a ?!~ "te'st" && ((b < c))`, false, `a?!~"te'st"&&b<c`},
+		{"a = 1 ||\n// test\n(b = 2 && (c = 3 || d = 4))", false, `a=1||b=2&&(c=3||d=4)`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			result, err := Minify(s.input)
+
+			if s.expectedError && err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, result)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if _, err := Parse(result); err != nil {
+				t.Fatalf("Failed to parse the minified filter: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package fexpr
+
+import "fmt"
+
+// UnaryOp represents a custom unary prefix operator (see RegisterUnaryOp).
+type UnaryOp string
+
+// UnaryExpr represents a custom unary prefix operator applied to a condition.
+//
+// The Item could be an `Expr` instance, `[]ExprGroup` slice or another `UnaryExpr`.
+type UnaryExpr struct {
+	Op   UnaryOp
+	Item interface{}
+}
+
+// UnaryPrecedence defines the operand of a custom unary operator.
+type UnaryPrecedence int
+
+// supported unary operator precedences
+const (
+	// UnaryBindsCondition applies the operator only to the following
+	// condition or group (eg. `~~ a = 1 && b = 2` => `(~~ a = 1) && b = 2`).
+	UnaryBindsCondition UnaryPrecedence = iota
+
+	// UnaryBindsAnd applies the operator to the following `&&` joined
+	// conditions (eg. `~~ a = 1 && b = 2 || c = 3` => `(~~ (a = 1 && b = 2)) || c = 3`),
+	// aka. it has lower precedence than `&&` but higher than `||`.
+	UnaryBindsAnd
+)
+
+// UnaryOpOptions defines the parsing behavior of a custom unary operator
+// (see RegisterUnaryOp).
+type UnaryOpOptions struct {
+	// Precedence defines the operand of the operator (default to UnaryBindsCondition).
+	Precedence UnaryPrecedence
+}
+
+// RegisterUnaryOp registers a custom unary prefix operator for the scanner
+// (eg. `RegisterUnaryOp("~~", UnaryOpOptions{})` for a "fuzzy" match modifier)
+// that is returned as TokenUnary and parsed into an UnaryExpr item.
+//
+// Similar to the custom sign operators, the custom unary operators take
+// precedence over the other tokens (the longest one wins).
+//
+// Note that the `not` keyword (see Keywords) cannot negate the custom unary
+// operators. The package helpers process the UnaryExpr operands
+// (eg. Sanitize, Inspect and MapFields), but the converters and the helpers
+// that depend on the operators semantics (eg. Not and ToDNF) return an error
+// (see also BuildCondition and UnaryConditionBuilder).
+//
+// RegisterUnaryOp panics if literal is empty or contains letters,
+// digits, whitespaces, quotes or parenthesis.
+func RegisterUnaryOp(literal string, opts UnaryOpOptions) ScannerOption {
+	if !isCustomOpLiteral(literal) {
+		panic(fmt.Sprintf("fexpr: invalid custom unary operator %q", literal))
+	}
+
+	return func(s *Scanner) {
+		s.registerOp(customOp{literal: literal, tokenType: TokenUnary, unaryOptions: opts})
+	}
+}
+
+// LookupUnaryOp returns the options of a registered custom unary operator.
+func (s *Scanner) LookupUnaryOp(op UnaryOp) (UnaryOpOptions, bool) {
+	for _, custom := range s.customOps {
+		if custom.tokenType == TokenUnary && custom.literal == string(op) {
+			return custom.unaryOptions, true
+		}
+	}
+
+	return UnaryOpOptions{}, false
+}
+
+// unaryChain holds the `&&` joined items of a pending UnaryBindsAnd operator.
+type unaryChain struct {
+	op    UnaryOp
+	join  JoinOp
+	items []ExprGroup
+}
+
+// wrapUnary wraps item with the specified unary operators (outermost first).
+func wrapUnary(item interface{}, ops []UnaryOp) interface{} {
+	for i := len(ops) - 1; i >= 0; i-- {
+		item = UnaryExpr{Op: ops[i], Item: item}
+	}
+
+	return item
+}
+
+// closeUnaryChain returns the UnaryExpr group of the chain items.
+func closeUnaryChain(chain unaryChain) ExprGroup {
+	var item interface{} = chain.items
+	if len(chain.items) == 1 {
+		item = chain.items[0].Item
+	}
+
+	return ExprGroup{Join: chain.join, Item: UnaryExpr{Op: chain.op, Item: item}}
+}
+
+// unaryOperand returns the operand of an UnaryExpr as groups slice.
+func unaryOperand(expr UnaryExpr) []ExprGroup {
+	if groups, ok := expr.Item.([]ExprGroup); ok {
+		return groups
+	}
+
+	return []ExprGroup{{Join: JoinAnd, Item: expr.Item}}
+}
+
+// mapUnaryOperand returns a copy of expr with its operand replaced
+// with the fn result of the operand groups (see unaryOperand).
+//
+// Returns false if fn returns no groups (aka. the operand was removed).
+func mapUnaryOperand(expr UnaryExpr, fn func(groups []ExprGroup) []ExprGroup) (UnaryExpr, bool) {
+	groups := fn(unaryOperand(expr))
+
+	switch len(groups) {
+	case 0:
+		return expr, false
+	case 1:
+		expr.Item = groups[0].Item
+	default:
+		expr.Item = groups
+	}
+
+	return expr, true
+}
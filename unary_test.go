@@ -0,0 +1,231 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRegisterUnaryOp(t *testing.T) {
+	opts := ScannerOptions(
+		RegisterUnaryOp("~~", UnaryOpOptions{}),
+		RegisterUnaryOp("!!", UnaryOpOptions{Precedence: UnaryBindsAnd}),
+	)
+
+	scenarios := []struct {
+		input             string
+		expectedError     bool
+		expectedPrint     string
+		expectedStringify string
+	}{
+		{`~~`, true, ``, ``},
+		{`a = 1 && ~~`, true, ``, ``},
+		{`~~ a`, true, ``, ``},
+		{`not ~~ a = 1`, true, ``, ``},
+		{`~~ a = 1`, false, `[{&& {~~ {{identifier a} = {number 1}}}}]`, `~~ a = 1`},
+		{
+			`~~ a = 1 && b = 2`,
+			false,
+			`[{&& {~~ {{identifier a} = {number 1}}}} {&& {{identifier b} = {number 2}}}]`,
+			`~~ a = 1 && b = 2`,
+		},
+		{
+			`a = 1 || ~~ ~~ (b = 2 || c = 3)`,
+			false,
+			`[{&& {{identifier a} = {number 1}}} {|| {~~ {~~ [{&& {{identifier b} = {number 2}}} {|| {{identifier c} = {number 3}}}]}}}]`,
+			`a = 1 || ~~ ~~ (b = 2 || c = 3)`,
+		},
+		{
+			`~~ not a = 1`,
+			false,
+			`[{&& {~~ {{identifier a} != {number 1}}}}]`,
+			`~~ a != 1`,
+		},
+		{
+			`!! a = 1`,
+			false,
+			`[{&& {!! {{identifier a} = {number 1}}}}]`,
+			`!! a = 1`,
+		},
+		{
+			`x = 0 || !! a = 1 && ~~ b = 2 || c = 3`,
+			false,
+			`[{&& {{identifier x} = {number 0}}} {|| {!! [{&& {{identifier a} = {number 1}}} {&& {~~ {{identifier b} = {number 2}}}}]}} {|| {{identifier c} = {number 3}}}]`,
+			`x = 0 || !! (a = 1 && ~~ b = 2) || c = 3`,
+		},
+		{
+			`!! a = 1 && !! b = 2 && c = 3`,
+			false,
+			`[{&& {!! [{&& {{identifier a} = {number 1}}} {&& {!! [{&& {{identifier b} = {number 2}}} {&& {{identifier c} = {number 3}}}]}}]}}]`,
+			`!! (a = 1 && !! (b = 2 && c = 3))`,
+		},
+		{
+			`~~ !! a = 1 && b = 2`,
+			false,
+			`[{&& {~~ {!! {{identifier a} = {number 1}}}}} {&& {{identifier b} = {number 2}}}]`,
+			`~~ !! a = 1 && b = 2`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			v, err := Parse(s.input, opts, Keywords())
+
+			if s.expectedError && err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !s.expectedError && err != nil {
+				t.Fatalf("Did not expect error, got %v", err)
+			}
+
+			if s.expectedError {
+				return
+			}
+
+			if vPrint := fmt.Sprintf("%v", v); vPrint != s.expectedPrint {
+				t.Fatalf("Expected %s, got %s", s.expectedPrint, vPrint)
+			}
+
+			str := Stringify(v)
+			if str != s.expectedStringify {
+				t.Fatalf("Expected stringified %s, got %s", s.expectedStringify, str)
+			}
+
+			// the stringified filter should be parsed to the same AST
+			reparsed, err := Parse(str, opts)
+			if err != nil {
+				t.Fatalf("Failed to parse the stringified filter: %v", err)
+			}
+			if vPrint := fmt.Sprintf("%v", reparsed); vPrint != s.expectedPrint {
+				t.Fatalf("Expected reparsed %s, got %s", s.expectedPrint, vPrint)
+			}
+		})
+	}
+}
+
+func TestLookupUnaryOp(t *testing.T) {
+	s := NewScanner(nil, RegisterUnaryOp("!!", UnaryOpOptions{Precedence: UnaryBindsAnd}))
+
+	if opts, ok := s.LookupUnaryOp("!!"); !ok || opts.Precedence != UnaryBindsAnd {
+		t.Fatalf("Expected the !! unary options, got %v (%v)", opts, ok)
+	}
+
+	if _, ok := s.LookupUnaryOp("~~"); ok {
+		t.Fatal("Expected ~~ to not be a registered unary operator")
+	}
+}
+
+func TestUnaryExprHelpers(t *testing.T) {
+	v, err := Parse(`~~ (a = 1 && b = c)`, ScannerOptions(RegisterUnaryOp("~~", UnaryOpOptions{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapped := MapFields(v, map[string]string{"a": "x", "c": "y"})
+	if str := Stringify(mapped); str != `~~ (x = 1 && b = y)` {
+		t.Fatalf("Unexpected mapped filter %s", str)
+	}
+
+	expectedDump := "`-- && unary ~~\n" +
+		"    |-- && identifier \"a\" = number \"1\"\n" +
+		"    `-- && identifier \"b\" = identifier \"c\""
+	if dump := Dump(v); dump != expectedDump {
+		t.Fatalf("Expected dump\n%s\ngot\n%s", expectedDump, dump)
+	}
+}
+
+func TestUnaryExprWalkers(t *testing.T) {
+	opts := ScannerOptions(RegisterUnaryOp("~~", UnaryOpOptions{}))
+
+	v, err := Parse(`~~ secret = 1 && a = 2 || ~~ (b = "x" && (c = 3))`, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name     string
+		result   func() string
+		expected string
+	}{
+		{
+			"Sanitize",
+			func() string {
+				result, dropped := Sanitize(v, func(expr Expr) bool { return expr.Left.Literal != "secret" })
+				return fmt.Sprintf("%s %v", Stringify(result), dropped)
+			},
+			`a = 2 || ~~ (b = "x" && (c = 3)) [{{identifier secret} = {number 1}}]`,
+		},
+		{
+			"Sanitize (removed operand)",
+			func() string {
+				result, _ := Sanitize(v, func(expr Expr) bool { return expr.Left.Literal != "b" && expr.Left.Literal != "c" })
+				return Stringify(result)
+			},
+			`~~ secret = 1 && a = 2`,
+		},
+		{
+			"Inspect",
+			func() string { return fmt.Sprintf("%v", Inspect(v)) },
+			`{[a b c secret] [=] [&& ||] [number text] [~~]}`,
+		},
+		{
+			"Complexity",
+			func() string { return fmt.Sprint(Complexity(v)) },
+			fmt.Sprint(2 + 1 + 1 + 1 + 1 + 2 + 1),
+		},
+		{
+			"Normalize",
+			func() string { return Stringify(Normalize(v)) },
+			`a = 2 && ~~ secret = 1 || ~~ (b = "x" && c = 3)`,
+		},
+		{
+			"Simplify",
+			func() string { return Stringify(Simplify(v)) },
+			`~~ secret = 1 && a = 2 || ~~ (b = "x" && c = 3)`,
+		},
+		{
+			"Optimize",
+			func() string { return Stringify(Optimize(v)) },
+			`~~ secret = 1 && a = 2 || ~~ (b = "x" && c = 3)`,
+		},
+		{
+			"Fingerprint",
+			func() string {
+				other, err := Parse(`~~ secret = 9 && a = 8 || ~~ (b = "y" && (c = 7))`, opts)
+				if err != nil {
+					return err.Error()
+				}
+				return fmt.Sprint(Fingerprint(v, true) == Fingerprint(other, true))
+			},
+			`true`,
+		},
+		{
+			"Parameterize",
+			func() string {
+				result, values := Parameterize(v)
+				return fmt.Sprintf("%s %v", Stringify(result), values)
+			},
+			`~~ secret = @p0 && a = @p1 || ~~ (b = @p2 && (c = @p3)) [{number 1} {number 2} {text x} {number 3}]`,
+		},
+		{
+			"SExpr",
+			func() string {
+				sexpr := ToSExpr(v)
+				parsed, err := ParseSExpr(sexpr)
+				if err != nil {
+					return err.Error()
+				}
+				return sexpr + " " + Stringify(parsed)
+			},
+			`(or (and (unary ~~ (= secret 1)) (= a 2)) (unary ~~ (and (= b "x") (= c 3)))) (~~ secret = 1 && a = 2) || ~~ (b = "x" && c = 3)`,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.name), func(t *testing.T) {
+			if result := s.result(); result != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package fexpr
+
+import "fmt"
+
+// Warning represents a non-fatal parse issue, aka. an input that
+// was accepted by the lenient syntax but is likely a mistake
+// (eg. an operator alias).
+type Warning struct {
+	// Span is the input byte offsets range of the issue.
+	Span Span
+
+	// Message is the warning description.
+	Message string
+}
+
+// String returns the `message (at start:end)` representation of the warning.
+func (w Warning) String() string {
+	return fmt.Sprintf("%s (at %d:%d)", w.Message, w.Span.Start, w.Span.End)
+}
+
+// Warnings enables collecting the non-fatal parse warnings into dst:
+//   - sign operator aliases (eg. `==` normalized to `=`)
+//   - comments inside an expression (eg. `a = // 1` followed by a new line operand)
+//
+// The warnings of the expanded macros are not collected.
+//
+// dst is replaced only on successful parse.
+func Warnings(dst *[]Warning) ParseOption {
+	return func(p *parser) {
+		p.warnings = dst
+	}
+}
+
+// warn collects a single parse warning for the span
+// of the currently parsed text.
+func (p *parser) warn(span Span, format string, args ...interface{}) {
+	if p.warnings == nil || len(p.expanding) > 0 {
+		return
+	}
+
+	p.collectedWarnings = append(p.collectedWarnings, Warning{
+		Span:    Span{Start: p.offset + span.Start, End: p.offset + span.End},
+		Message: fmt.Sprintf(format, args...),
+	})
+}
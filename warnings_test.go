@@ -0,0 +1,47 @@
+package fexpr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	scenarios := []struct {
+		input    string
+		opts     []ParseOption
+		expected string
+	}{
+		{`a = 1 && b != 2 // test`, nil, `[]`},
+		{`a == 1 && (b <> 2 || c = 3)`, nil, `[sign operator alias "==" normalized to "=" (at 2:4) sign operator alias "<>" normalized to "!=" (at 13:15)]`},
+		{"a = // 1\n2 && b = 3", nil, `[comment inside the "a" expression (at 4:9)]`},
+		{"(a // b\n= 2)", nil, `[comment inside the "a" expression (at 3:8)]`},
+		{`#m && a == 1`, []ParseOption{Macro("m", "b == 2")}, `[sign operator alias "==" normalized to "=" (at 8:10)]`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("s%d:%s", i, s.input), func(t *testing.T) {
+			var warnings []Warning
+
+			if _, err := Parse(s.input, append(s.opts, Warnings(&warnings))...); err != nil {
+				t.Fatal(err)
+			}
+
+			if warningsPrint := fmt.Sprintf("%v", warnings); warningsPrint != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, warningsPrint)
+			}
+		})
+	}
+}
+
+func TestWarningsParseError(t *testing.T) {
+	warnings := []Warning{{Message: "test"}}
+
+	if _, err := Parse(`a == 1 &&`, Warnings(&warnings)); err == nil {
+		t.Fatal("Expected parse error, got nil")
+	}
+
+	// dst should be replaced only on successful parse
+	if len(warnings) != 1 || warnings[0].Message != "test" {
+		t.Fatalf("Expected unchanged warnings, got %v", warnings)
+	}
+}